@@ -13,9 +13,17 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
+	"github.com/rodrwan/vinilo/internal/artwork"
 	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/exporter"
 	"github.com/rodrwan/vinilo/internal/handlers"
+	"github.com/rodrwan/vinilo/internal/importer"
+	"github.com/rodrwan/vinilo/internal/metadata"
+	"github.com/rodrwan/vinilo/internal/mirror"
 	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/internal/resync"
+	"github.com/rodrwan/vinilo/internal/storage"
+	"github.com/rodrwan/vinilo/internal/trash"
 )
 
 func main() {
@@ -27,6 +35,7 @@ func main() {
 	// Configuración
 	port := getEnv("PORT", "8080")
 	dbPath := getEnv("DB_PATH", "./data/vinilo.db")
+	mediaDir := getEnv("MEDIA_DIR", "./data/media")
 
 	// Inicializar base de datos
 	db, err := database.NewDB(dbPath)
@@ -35,13 +44,73 @@ func main() {
 	}
 	defer db.Close()
 
-	// Inicializar repositorio
+	// Inicializar almacenamiento de archivos (arte subido)
+	mediaStore, err := storage.NewLocalStore(mediaDir)
+	if err != nil {
+		log.Fatalf("Error inicializando almacenamiento de archivos: %v", err)
+	}
+
+	// Inicializar repositorios
 	recordRepo := repository.NewRecordRepository(db)
+	locationRepo := repository.NewLocationRepository(db)
+	loanRepo := repository.NewLoanRepository(db)
+	wantlistRepo := repository.NewWantlistRepository(db)
+	playRepo := repository.NewPlayRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	crateRepo := repository.NewSmartCrateRepository(db)
+	playlistRepo := repository.NewPlaylistRepository(db)
+	fieldRepo := repository.NewCustomFieldRepository(db)
+	identifierRepo := repository.NewIdentifierRepository(db)
+	importProfileRepo := repository.NewImportProfileRepository(db)
+	metadataSyncRepo := repository.NewMetadataSyncRepository(db)
+	artworkMirrorRepo := repository.NewArtworkMirrorRepository(db)
+	recordImageRepo := repository.NewRecordImageRepository(db)
+	duplicateRepo := repository.NewDuplicateRepository(db)
+	recordVersionRepo := repository.NewRecordVersionRepository(db)
 
 	// Inicializar handlers
 	landingHandler := handlers.LandingHandler()
-	recordsHandler := handlers.NewRecordsHandler(recordRepo)
-	adminHandler := handlers.NewAdminHandler(recordRepo)
+	detailSources := handlers.RecordDetailSources{
+		Locations:   locationRepo,
+		Loans:       loanRepo,
+		Plays:       playRepo,
+		Tags:        tagRepo,
+		Fields:      fieldRepo,
+		Identifiers: identifierRepo,
+		Metadata:    metadataSyncRepo,
+		Images:      recordImageRepo,
+	}
+	conflicts := handlers.RecordConflicts{
+		Records:   recordRepo,
+		Versions:  recordVersionRepo,
+		Locations: locationRepo,
+	}
+	recordsHandler := handlers.NewRecordsHandler(recordRepo, detailSources)
+	providers := metadataProviders()
+	syncer := resync.New(recordRepo, metadataSyncRepo, providers, metadataSyncInterval())
+	art := artwork.New(mediaStore)
+	artMirror := mirror.New(artworkMirrorRepo, art, artworkMirrorInterval())
+	adminHandler := handlers.NewAdminHandler(recordRepo, detailSources, conflicts, providers, art)
+	locationsHandler := handlers.NewLocationsHandler(locationRepo, recordRepo)
+	loansHandler := handlers.NewLoansHandler(loanRepo, recordRepo)
+	wantlistHandler := handlers.NewWantlistHandler(wantlistRepo)
+	playsHandler := handlers.NewPlaysHandler(playRepo, recordRepo)
+	statsHandler := handlers.NewStatsHandler(statsRepo)
+	tagsHandler := handlers.NewTagsHandler(tagRepo, recordRepo)
+	cratesHandler := handlers.NewCratesHandler(crateRepo, recordRepo, tagRepo)
+	playlistsHandler := handlers.NewPlaylistsHandler(playlistRepo, recordRepo)
+	fieldsHandler := handlers.NewCustomFieldsHandler(fieldRepo, recordRepo, conflicts)
+	identifiersHandler := handlers.NewIdentifiersHandler(identifierRepo, recordRepo)
+	exportHandler := handlers.NewExportHandler(exporter.New(recordRepo, identifierRepo, tagRepo, fieldRepo), recordRepo)
+	importHandler := handlers.NewImportHandler(importer.New(recordRepo, identifierRepo), importProfileRepo, fieldRepo)
+	metadataHandler := handlers.NewMetadataHandler(syncer, metadataSyncRepo, recordRepo)
+	mirrorHandler := handlers.NewMirrorHandler(artMirror, artworkMirrorRepo, recordRepo)
+	imagesHandler := handlers.NewImagesHandler(recordImageRepo, recordRepo, art)
+	duplicatesHandler := handlers.NewDuplicatesHandler(duplicateRepo, recordRepo, identifierRepo, conflicts)
+	purger := trash.New(recordRepo, trashRetention())
+	trashHandler := handlers.NewTrashHandler(recordRepo, purger)
+	historyHandler := handlers.NewHistoryHandler(recordRepo, recordVersionRepo, locationRepo)
 	// Configurar router
 	r := chi.NewRouter()
 
@@ -52,6 +121,10 @@ func main() {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Timeout(30 * time.Second))
 	r.Use(middleware.Compress(5))
+	if getEnv("TRUST_PROXY_USER", "") == "true" {
+		// El historial registra el usuario que informa el proxy con autenticación
+		r.Use(handlers.ProxyUser)
+	}
 
 	// CORS
 	r.Use(cors.Handler(cors.Options{
@@ -67,10 +140,17 @@ func main() {
 	fileServer := http.FileServer(http.Dir("web/static"))
 	r.Handle("/static/*", http.StripPrefix("/static/", fileServer))
 
+	// Arte y demás archivos del almacenamiento
+	r.Get("/media/*", handlers.MediaHandler(mediaStore))
+
 	// Rutas
 	r.Get("/", landingHandler)
 	r.Get("/records", recordsHandler.ListHandler())
 	r.Get("/records/{id}", recordsHandler.DetailHandler())
+	r.Get("/wantlist", wantlistHandler.ListHandler())
+	r.Get("/crates", cratesHandler.ListHandler())
+	r.Get("/crates/{id}", cratesHandler.DetailHandler())
+	r.Get("/playlists/{id}", playlistsHandler.PublicHandler())
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -83,7 +163,103 @@ func main() {
 	r.Get("/admin/records", adminHandler.ListHandler())
 	r.Get("/admin/records/new", adminHandler.NewRecordHandler())
 	r.Post("/admin/records", adminHandler.CreateRecordHandler())
+	r.Post("/admin/records/bulk", adminHandler.BulkHandler())
 	r.Get("/admin/records/{id}", adminHandler.DetailHandler())
+	r.Post("/admin/records/{id}/rating", adminHandler.RateHandler())
+	r.Post("/admin/records/{id}/location", adminHandler.LocationHandler())
+	r.Post("/admin/records/{id}/artwork", adminHandler.ArtworkHandler())
+	r.Get("/admin/stats", statsHandler.HomeHandler())
+
+	// Ubicaciones físicas
+	r.Get("/admin/locations", locationsHandler.ListHandler())
+	r.Post("/admin/locations", locationsHandler.CreateHandler())
+	r.Get("/admin/locations/{id}", locationsHandler.DetailHandler())
+	r.Post("/admin/locations/{id}", locationsHandler.UpdateHandler())
+	r.Post("/admin/locations/{id}/delete", locationsHandler.DeleteHandler())
+	r.Post("/admin/locations/{id}/records", locationsHandler.MoveRecordsHandler())
+
+	// Préstamos
+	r.Post("/admin/records/{id}/loans", loansHandler.CreateHandler())
+	r.Post("/admin/loans/{id}/return", loansHandler.ReturnHandler())
+
+	// Registro de escucha
+	r.Post("/admin/records/{id}/plays", playsHandler.CreateHandler())
+	r.Get("/admin/plays", playsHandler.ListHandler())
+
+	// Tags y crates inteligentes
+	r.Post("/admin/records/{id}/tags", tagsHandler.AddHandler())
+	r.Post("/admin/records/{id}/tags/{tagID}/delete", tagsHandler.RemoveHandler())
+	r.Get("/admin/crates", cratesHandler.AdminListHandler())
+	r.Post("/admin/crates", cratesHandler.CreateHandler())
+	r.Post("/admin/crates/{id}/delete", cratesHandler.DeleteHandler())
+
+	// Campos personalizados
+	r.Get("/admin/fields", fieldsHandler.ListHandler())
+	r.Post("/admin/fields", fieldsHandler.CreateHandler())
+	r.Post("/admin/fields/{id}/delete", fieldsHandler.DeleteHandler())
+	r.Post("/admin/records/{id}/fields", fieldsHandler.UpdateRecordHandler())
+
+	// Identificadores
+	r.Post("/admin/records/{id}/identifiers", identifiersHandler.CreateHandler())
+	r.Post("/admin/records/{id}/identifiers/{identifierID}/delete", identifiersHandler.DeleteHandler())
+
+	// Galería de imágenes
+	r.Post("/admin/records/{id}/images", imagesHandler.CreateHandler())
+	r.Post("/admin/records/{id}/images/order", imagesHandler.ReorderHandler())
+	r.Post("/admin/records/{id}/images/{imageID}", imagesHandler.UpdateHandler())
+	r.Post("/admin/records/{id}/images/{imageID}/cover", imagesHandler.CoverHandler())
+	r.Post("/admin/records/{id}/images/{imageID}/delete", imagesHandler.DeleteHandler())
+
+	// Importación y exportación
+	r.Get("/admin/export", exportHandler.ExportHandler())
+	r.Get("/admin/import", importHandler.PageHandler())
+	r.Post("/admin/import/discogs", importHandler.DiscogsHandler())
+	r.Post("/admin/import/file", importHandler.FileHandler())
+	r.Post("/admin/import/profiles/{id}/delete", importHandler.DeleteProfileHandler())
+
+	// Re-sincronización de metadatos
+	r.Get("/admin/metadata", metadataHandler.ReviewHandler())
+	r.Post("/admin/metadata/sync", metadataHandler.SyncHandler())
+	r.Post("/admin/metadata/changes/{id}/accept", metadataHandler.AcceptChangeHandler())
+	r.Post("/admin/metadata/changes/{id}/reject", metadataHandler.RejectChangeHandler())
+	r.Post("/admin/metadata/records/{id}/accept", metadataHandler.AcceptRecordHandler())
+	r.Post("/admin/metadata/records/{id}/reject", metadataHandler.RejectRecordHandler())
+
+	// Copia local del arte externo
+	r.Get("/admin/artwork", mirrorHandler.ReportHandler())
+	r.Post("/admin/artwork/mirror", mirrorHandler.RunHandler())
+	r.Post("/admin/artwork/{id}/retry", mirrorHandler.RetryHandler())
+
+	// Duplicados
+	r.Get("/admin/duplicates", duplicatesHandler.ListHandler())
+	r.Get("/admin/duplicates/{a}/{b}", duplicatesHandler.ReviewHandler())
+	r.Post("/admin/duplicates/{a}/{b}/merge", duplicatesHandler.MergeHandler())
+	r.Post("/admin/duplicates/{a}/{b}/dismiss", duplicatesHandler.DismissHandler())
+
+	// Papelera
+	r.Post("/admin/records/{id}/delete", trashHandler.DeleteHandler())
+	r.Get("/admin/trash", trashHandler.ListHandler())
+	r.Post("/admin/trash/{id}/restore", trashHandler.RestoreHandler())
+	r.Post("/admin/trash/{id}/purge", trashHandler.PurgeHandler())
+
+	// Historial de cambios
+	r.Get("/admin/records/{id}/history", historyHandler.ListHandler())
+	r.Post("/admin/records/{id}/history/{versionID}/revert", historyHandler.RevertHandler())
+
+	// Playlists
+	r.Get("/admin/playlists", playlistsHandler.AdminListHandler())
+	r.Post("/admin/playlists", playlistsHandler.CreateHandler())
+	r.Get("/admin/playlists/{id}", playlistsHandler.DetailHandler())
+	r.Post("/admin/playlists/{id}/items", playlistsHandler.AddItemHandler())
+	r.Post("/admin/playlists/{id}/items/{itemID}/delete", playlistsHandler.RemoveItemHandler())
+	r.Post("/admin/playlists/{id}/order", playlistsHandler.ReorderHandler())
+	r.Post("/admin/playlists/{id}/delete", playlistsHandler.DeleteHandler())
+
+	// Wantlist
+	r.Get("/admin/wantlist", wantlistHandler.AdminListHandler())
+	r.Post("/admin/wantlist", wantlistHandler.CreateHandler())
+	r.Post("/admin/wantlist/{id}/purchase", wantlistHandler.PurchaseHandler())
+	r.Post("/admin/wantlist/{id}/delete", wantlistHandler.DeleteHandler())
 
 	// Configurar servidor
 	srv := &http.Server{
@@ -98,11 +274,25 @@ func main() {
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	// Re-sincronización periódica de metadatos en segundo plano
+	syncCtx, stopSync := context.WithCancel(context.Background())
+	defer stopSync()
+	if syncer.Interval() > 0 {
+		go syncer.Start(syncCtx)
+	}
+	if artMirror.Interval() > 0 {
+		go artMirror.Start(syncCtx)
+	}
+	if purger.Retention() > 0 {
+		go purger.Start(syncCtx)
+	}
+
 	// Iniciar servidor en goroutine
 	go func() {
 		log.Printf("🚀 Servidor iniciado en http://localhost:%s", port)
 		log.Printf("📊 Health check: http://localhost:%s/health", port)
 		log.Printf("🗄️ Base de datos: %s", dbPath)
+		log.Printf("🖼️ Archivos: %s", mediaDir)
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Error iniciando servidor: %v", err)
@@ -112,6 +302,7 @@ func main() {
 	// Esperar señal de terminación
 	<-done
 	log.Println("🛑 Cerrando servidor...")
+	stopSync()
 
 	// Shutdown graceful
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -124,6 +315,23 @@ func main() {
 	log.Println("✅ Servidor cerrado correctamente")
 }
 
+// metadataProviders configura los proveedores de metadatos según las
+// variables de entorno. MusicBrainz no necesita token y está habilitado
+// salvo que MUSICBRAINZ_ENABLED=false.
+func metadataProviders() []metadata.Provider {
+	var providers []metadata.Provider
+	if token := getEnv("DISCOGS_TOKEN", ""); token != "" {
+		providers = append(providers, metadata.NewDiscogs(token, getEnv("DISCOGS_API_URL", "")))
+	}
+	if getEnv("MUSICBRAINZ_ENABLED", "true") != "false" {
+		providers = append(providers, metadata.NewMusicBrainz(getEnv("MUSICBRAINZ_API_URL", ""), getEnv("COVERART_API_URL", "")))
+	}
+	if getEnv("METADATA_FAKE", "") == "true" {
+		providers = append(providers, metadata.NewFake())
+	}
+	return providers
+}
+
 // getEnv obtiene una variable de entorno o retorna un valor por defecto
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -131,3 +339,42 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// metadataSyncInterval retorna cada cuánto se re-sincronizan los metadatos
+// de cada record (METADATA_SYNC_INTERVAL, p. ej. "24h"). Con "0" la
+// sincronización solo se ejecuta a pedido desde el admin.
+func metadataSyncInterval() time.Duration {
+	value := getEnv("METADATA_SYNC_INTERVAL", "24h")
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Printf("⚠️ METADATA_SYNC_INTERVAL inválido (%s), se usa 24h", value)
+		return 24 * time.Hour
+	}
+	return interval
+}
+
+// artworkMirrorInterval retorna cada cuánto se busca arte externo nuevo
+// para copiar (ARTWORK_MIRROR_INTERVAL, p. ej. "1h"). Con "0" la copia
+// solo se ejecuta a pedido desde el admin.
+func artworkMirrorInterval() time.Duration {
+	value := getEnv("ARTWORK_MIRROR_INTERVAL", "1h")
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Printf("⚠️ ARTWORK_MIRROR_INTERVAL inválido (%s), se usa 1h", value)
+		return time.Hour
+	}
+	return interval
+}
+
+// trashRetention retorna cuánto tiempo se conservan los records en la
+// papelera antes de eliminarlos definitivamente (TRASH_RETENTION, p. ej.
+// "720h"). Con "0" solo se eliminan a mano desde el admin.
+func trashRetention() time.Duration {
+	value := getEnv("TRASH_RETENTION", "720h")
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		log.Printf("⚠️ TRASH_RETENTION inválido (%s), se usa 720h", value)
+		return 720 * time.Hour
+	}
+	return retention
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/exporter"
+	"github.com/rodrwan/vinilo/internal/importer"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/internal/storage"
+)
+
+const usage = `Uso: vinilo <comando> [opciones]
+
+Comandos:
+  import discogs [-dry-run] <archivo.csv>                Importa una exportación de colección de Discogs
+  import file -profile <nombre> [-dry-run] <archivo>     Importa un CSV o JSON con un perfil de mapeo guardado
+  export [-format csv|ndjson|discogs] [-o archivo]       Exporta la colección (por defecto a la salida estándar)
+         [-search texto] [-tag tag] [-filter expresión]
+  thumbnails [-force]                                    Genera las miniaturas que falten del arte y la galería
+  palette [-force]                                       Calcula los colores del arte subido que no los tengan
+`
+
+// cliActor es el actor con el que los comandos registran sus cambios en el
+// historial de los records
+const cliActor = "línea de comandos"
+
+func main() {
+	// Cargar variables de entorno
+	godotenv.Load()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "thumbnails":
+		err = runThumbnails(os.Args[2:])
+	case "palette":
+		err = runPalette(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+// openDB abre la base de datos configurada en DB_PATH
+func openDB() (*database.DB, error) {
+	return database.NewDB(getEnv("DB_PATH", "./data/vinilo.db"))
+}
+
+// runImport ejecuta `vinilo import <fuente> [opciones] <archivo>`
+func runImport(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("falta la fuente de importación: discogs o file")
+	}
+
+	flags := flag.NewFlagSet("import "+args[0], flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "muestra el reporte sin guardar cambios")
+	profileName := flags.String("profile", "", "perfil de mapeo de columnas (solo para file)")
+	flags.Parse(args[1:])
+	if flags.NArg() != 1 {
+		return fmt.Errorf("falta el archivo a importar")
+	}
+
+	content, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("error abriendo archivo: %w", err)
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return fmt.Errorf("error conectando a la base de datos: %w", err)
+	}
+	defer db.Close()
+
+	imp := importer.New(repository.NewRecordRepository(db).WithActor(cliActor), repository.NewIdentifierRepository(db))
+
+	var report *importer.Report
+	switch args[0] {
+	case "discogs":
+		rows, err := importer.ParseDiscogsCSV(bytes.NewReader(content))
+		if err != nil {
+			return err
+		}
+		report, err = imp.Run(flags.Arg(0), rows, *dryRun)
+		if err != nil {
+			return err
+		}
+	case "file":
+		if *profileName == "" {
+			return fmt.Errorf("falta el perfil de mapeo: vinilo import file -profile <nombre> <archivo>")
+		}
+		profile, err := repository.NewImportProfileRepository(db).GetByName(*profileName)
+		if err != nil {
+			return err
+		}
+		fields, err := repository.NewCustomFieldRepository(db).GetAll()
+		if err != nil {
+			return err
+		}
+
+		table, err := importer.ReadTable(content, importer.DetectFormat(content))
+		if err != nil {
+			return err
+		}
+		rows, err := importer.MapTable(table, profile.GetMapeo(), fields)
+		if err != nil {
+			return err
+		}
+		report, err = imp.RunAll(flags.Arg(0), rows, *dryRun)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("fuente de importación no soportada: %s", args[0])
+	}
+
+	report.WriteText(os.Stdout)
+	switch {
+	case report.Rejected:
+		return fmt.Errorf("importación cancelada: corrige las filas con errores")
+	case report.DryRun:
+		log.Println("ℹ️ Dry-run: no se guardaron cambios")
+	default:
+		log.Printf("✅ Importación completada: %d records creados", report.Created)
+	}
+	return nil
+}
+
+// runExport ejecuta `vinilo export [opciones]`
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", exporter.FormatCSV, "formato: csv, ndjson o discogs")
+	output := flags.String("o", "", "archivo de salida (por defecto la salida estándar)")
+	search := flags.String("search", "", "exporta solo los records que coinciden con la búsqueda")
+	tag := flags.String("tag", "", "exporta solo los records con el tag")
+	expression := flags.String("filter", "", "filtro avanzado, p. ej. \"formato=LP and anio<1980\"")
+	flags.Parse(args)
+
+	if !exporter.IsValidFormat(*format) {
+		return fmt.Errorf("formato de exportación inválido: %s", *format)
+	}
+
+	conditions, err := models.ParseFilterExpression(*expression)
+	if err != nil {
+		return err
+	}
+	filter := models.RecordFilter{
+		Search:     *search,
+		Tag:        models.NormalizeTag(*tag),
+		Conditions: conditions,
+		Sort:       models.SortArtist,
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return fmt.Errorf("error conectando a la base de datos: %w", err)
+	}
+	defer db.Close()
+
+	w := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("error creando archivo: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	exp := exporter.New(
+		repository.NewRecordRepository(db),
+		repository.NewIdentifierRepository(db),
+		repository.NewTagRepository(db),
+		repository.NewCustomFieldRepository(db),
+	)
+	count, err := exp.Export(w, *format, filter)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✅ %d records exportados en formato %s", count, *format)
+	return nil
+}
+
+// runThumbnails ejecuta `vinilo thumbnails [-force]`, que genera las
+// miniaturas del arte guardado antes de que existieran, tanto la portada de
+// cada record como las imágenes de su galería
+func runThumbnails(args []string) error {
+	flags := flag.NewFlagSet("thumbnails", flag.ExitOnError)
+	force := flags.Bool("force", false, "regenera también las miniaturas existentes")
+	flags.Parse(args)
+
+	db, err := openDB()
+	if err != nil {
+		return fmt.Errorf("error conectando a la base de datos: %w", err)
+	}
+	defer db.Close()
+
+	store, err := storage.NewLocalStore(getEnv("MEDIA_DIR", "./data/media"))
+	if err != nil {
+		return err
+	}
+	art := artwork.New(store)
+	repo := repository.NewRecordRepository(db)
+	images := repository.NewRecordImageRepository(db)
+
+	const batchSize = 100
+	var records, created, failed int
+	for offset := 0; ; offset += batchSize {
+		batch, err := repo.GetAll(batchSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, record := range batch {
+			gallery, err := images.GetByRecord(record.ID)
+			if err != nil {
+				return err
+			}
+			urls := []string{record.ArteURL.String}
+			for _, image := range gallery {
+				urls = append(urls, image.URL)
+			}
+
+			var recordCreated int
+			for _, url := range urls {
+				count, err := art.Thumbnails(url, *force)
+				if err != nil {
+					log.Printf("⚠️ %s (%s): %v", record.GetDisplayTitle(), url, err)
+					failed++
+					continue
+				}
+				recordCreated += count
+			}
+			if recordCreated > 0 {
+				records++
+				created += recordCreated
+			}
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	log.Printf("✅ %d miniaturas generadas para %d records", created, records)
+	if failed > 0 {
+		return fmt.Errorf("no se pudieron generar las miniaturas de %d imágenes", failed)
+	}
+	return nil
+}
+
+// runPalette ejecuta `vinilo palette [-force]`, que calcula los colores del
+// arte local de los records que aún no los tienen, p. ej. los subidos antes
+// de que existiera la paleta. Con -force se recalculan todos.
+func runPalette(args []string) error {
+	flags := flag.NewFlagSet("palette", flag.ExitOnError)
+	force := flags.Bool("force", false, "recalcula también los colores existentes")
+	flags.Parse(args)
+
+	db, err := openDB()
+	if err != nil {
+		return fmt.Errorf("error conectando a la base de datos: %w", err)
+	}
+	defer db.Close()
+
+	store, err := storage.NewLocalStore(getEnv("MEDIA_DIR", "./data/media"))
+	if err != nil {
+		return err
+	}
+	art := artwork.New(store)
+	repo := repository.NewRecordRepository(db)
+
+	const batchSize = 100
+	var updated, failed int
+	for offset := 0; ; offset += batchSize {
+		batch, err := repo.GetAll(batchSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, record := range batch {
+			if !artwork.IsLocal(record.ArteURL.String) || (record.HasPalette() && !*force) {
+				continue
+			}
+			palette, err := art.Palette(record.ArteURL.String)
+			if err != nil {
+				log.Printf("⚠️ %s: %v", record.GetDisplayTitle(), err)
+				failed++
+				continue
+			}
+			if palette.Dominant == "" {
+				continue
+			}
+			if err := repo.UpdatePalette(record.ID, palette); err != nil {
+				return err
+			}
+			updated++
+		}
+		if len(batch) < batchSize {
+			break
+		}
+	}
+
+	log.Printf("✅ Colores calculados para %d records", updated)
+	if failed > 0 {
+		return fmt.Errorf("no se pudieron calcular los colores de %d records", failed)
+	}
+	return nil
+}
+
+// getEnv obtiene una variable de entorno o retorna un valor por defecto
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -3,6 +3,7 @@ module github.com/rodrwan/vinilo
 go 1.24.0
 
 require (
+	github.com/chai2010/webp v1.4.0
 	github.com/go-chi/chi/v5 v5.2.2
 	github.com/go-chi/cors v1.2.2
 	github.com/google/uuid v1.6.0
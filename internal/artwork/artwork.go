@@ -0,0 +1,136 @@
+// Package artwork guarda el arte de los records en el almacenamiento de
+// archivos. Cada imagen se guarda con el hash de su contenido como nombre,
+// de modo que subir dos veces la misma imagen no la duplica y las URLs se
+// pueden cachear indefinidamente.
+package artwork
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // decodificadores para validar las imágenes subidas
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	_ "github.com/chai2010/webp" // registra el decodificador WebP
+	"github.com/rodrwan/vinilo/internal/storage"
+)
+
+// MaxSize es el tamaño máximo de una imagen de arte
+const MaxSize = 10 << 20
+
+// maxDimension es el ancho o alto máximo de una imagen, para rechazar
+// archivos que ocuparían demasiada memoria al procesarlos
+const maxDimension = 8000
+
+// URLPrefix es la ruta desde la que se sirven los archivos del almacenamiento
+const URLPrefix = "/media/"
+
+// keyPrefix agrupa el arte dentro del almacenamiento
+const keyPrefix = "artwork/"
+
+// ErrTooLarge indica que la imagen supera el tamaño o las dimensiones máximas
+var ErrTooLarge = errors.New("la imagen supera el tamaño máximo")
+
+// ErrInvalidImage indica que el archivo no es una imagen soportada
+var ErrInvalidImage = errors.New("el archivo no es una imagen JPEG, PNG, GIF o WebP válida")
+
+// extensions asocia los tipos de imagen soportados a su extensión
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Artwork guarda imágenes de arte en un almacenamiento de archivos
+type Artwork struct {
+	store storage.Store
+}
+
+// New crea un gestor de arte sobre el almacenamiento indicado
+func New(store storage.Store) *Artwork {
+	return &Artwork{store: store}
+}
+
+// Save valida y guarda una imagen junto con sus miniaturas, y retorna la
+// URL local con la que se sirve (p. ej. "/media/artwork/3f9a….jpg")
+func (a *Artwork) Save(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("error leyendo imagen: %w", err)
+	}
+	if len(data) > MaxSize {
+		return "", fmt.Errorf("%w de %d MB", ErrTooLarge, MaxSize>>20)
+	}
+
+	ext, err := Validate(data)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	key := keyPrefix + hex.EncodeToString(sum[:]) + ext
+
+	exists, err := a.store.Exists(key)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if err := a.store.Put(key, data); err != nil {
+			return "", err
+		}
+	}
+
+	url := URLPrefix + key
+	if _, err := a.Thumbnails(url, false); err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+// Validate verifica que el contenido sea una imagen soportada y retorna
+// la extensión que le corresponde. Los errores de validación envuelven
+// ErrInvalidImage o ErrTooLarge.
+func Validate(data []byte) (string, error) {
+	ext, ok := extensions[http.DetectContentType(data)]
+	if !ok {
+		return "", ErrInvalidImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrInvalidImage
+	}
+	if config.Width > maxDimension || config.Height > maxDimension {
+		return "", fmt.Errorf("%w de %dx%d píxeles", ErrTooLarge, maxDimension, maxDimension)
+	}
+	return ext, nil
+}
+
+// ContentType retorna el tipo MIME de un archivo del almacenamiento según
+// su extensión
+func ContentType(key string) string {
+	for contentType, ext := range extensions {
+		if strings.HasSuffix(key, ext) {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// IsLocal indica si la URL apunta a arte guardado en el almacenamiento local
+func IsLocal(url string) bool {
+	return strings.HasPrefix(url, URLPrefix+keyPrefix)
+}
+
+// IsValidationError indica si el error se debe a la imagen y no al almacenamiento
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrInvalidImage) || errors.Is(err, ErrTooLarge)
+}
@@ -0,0 +1,181 @@
+package artwork
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/rodrwan/vinilo/internal/storage"
+)
+
+// Palette son los colores que se extraen del arte de un record, en formato
+// "#rrggbb". Un valor vacío indica que no se pudieron calcular.
+type Palette struct {
+	Dominant string // color que ocupa más superficie
+	Accent   string // color llamativo que contrasta con el dominante
+}
+
+// colorPattern valida un color "#rrggbb"
+var colorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// IsColor indica si el valor es un color "#rrggbb", como los que retorna Palette
+func IsColor(value string) bool {
+	return colorPattern.MatchString(value)
+}
+
+// paletteSampleWidth es el ancho al que se reduce la imagen antes de
+// contar colores; basta con unos pocos miles de píxeles
+const paletteSampleWidth = 64
+
+// Palette calcula los colores dominante y de acento de una URL de arte
+// local. Para el arte externo retorna una paleta vacía.
+func (a *Artwork) Palette(url string) (Palette, error) {
+	key, ok := localKey(url)
+	if !ok {
+		return Palette{}, nil
+	}
+
+	// La miniatura más chica da el mismo resultado y es mucho más rápida
+	// de decodificar; el original se usa si aún no se generó
+	file, err := a.store.Open(thumbnailKey(key, SizeThumb, formatJPEG))
+	if errors.Is(err, storage.ErrNotFound) {
+		file, err = a.store.Open(key)
+	}
+	if err != nil {
+		return Palette{}, fmt.Errorf("error abriendo %s: %w", key, err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return Palette{}, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	return extractPalette(img), nil
+}
+
+// colorBucket acumula los píxeles de colores parecidos
+type colorBucket struct {
+	r, g, b, n int
+}
+
+// average retorna el color promedio del grupo
+func (c *colorBucket) average() (r, g, b float64) {
+	n := float64(c.n)
+	return float64(c.r) / n, float64(c.g) / n, float64(c.b) / n
+}
+
+// extractPalette agrupa los píxeles en colores parecidos (4 bits por
+// canal). El dominante es el grupo más grande; el acento, el grupo que
+// mejor combina tamaño y saturación entre los que se distinguen del
+// dominante. Si no hay ninguno, el acento es una variante del dominante.
+func extractPalette(img image.Image) Palette {
+	sample := resize(flatten(img), paletteSampleWidth)
+
+	buckets := map[int]*colorBucket{}
+	pix := sample.Pix
+	for i := 0; i+3 < len(pix); i += 4 {
+		r, g, b := int(pix[i]), int(pix[i+1]), int(pix[i+2])
+		key := r>>4<<8 | g>>4<<4 | b>>4
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &colorBucket{}
+			buckets[key] = bucket
+		}
+		bucket.r += r
+		bucket.g += g
+		bucket.b += b
+		bucket.n++
+	}
+
+	// Se recorren en orden para que los empates den siempre el mismo color
+	keys := make([]int, 0, len(buckets))
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	var dominant *colorBucket
+	for _, key := range keys {
+		bucket := buckets[key]
+		if dominant == nil || bucket.n > dominant.n {
+			dominant = bucket
+		}
+	}
+	if dominant == nil {
+		return Palette{}
+	}
+	dr, dg, db := dominant.average()
+
+	var accent *colorBucket
+	var accentScore float64
+	for _, key := range keys {
+		bucket := buckets[key]
+		r, g, b := bucket.average()
+		_, s, l := toHSL(r, g, b)
+		if s < 0.3 || l < 0.2 || l > 0.85 || colorDistance(r, g, b, dr, dg, db) < 80 {
+			continue
+		}
+		if score := float64(bucket.n) * s; score > accentScore {
+			accent, accentScore = bucket, score
+		}
+	}
+
+	palette := Palette{Dominant: hexColor(dr, dg, db)}
+	if accent != nil {
+		palette.Accent = hexColor(accent.average())
+	} else {
+		palette.Accent = hexColor(shade(dr, dg, db))
+	}
+	return palette
+}
+
+// shade aclara los colores oscuros y oscurece los claros, para derivar un
+// acento legible de un color
+func shade(r, g, b float64) (float64, float64, float64) {
+	_, _, l := toHSL(r, g, b)
+	target, amount := 255.0, 0.45
+	if l > 0.5 {
+		target = 0
+	}
+	return r + (target-r)*amount, g + (target-g)*amount, b + (target-b)*amount
+}
+
+// toHSL retorna el tono, la saturación y la luminosidad de un color RGB,
+// las dos últimas entre 0 y 1
+func toHSL(r, g, b float64) (h, s, l float64) {
+	r, g, b = r/255, g/255, b/255
+	high, low := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (high + low) / 2
+	if high == low {
+		return 0, 0, l
+	}
+
+	d := high - low
+	if l > 0.5 {
+		s = d / (2 - high - low)
+	} else {
+		s = d / (high + low)
+	}
+	switch high {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+// colorDistance es la distancia euclidiana entre dos colores RGB
+func colorDistance(r1, g1, b1, r2, g2, b2 float64) float64 {
+	return math.Sqrt((r1-r2)*(r1-r2) + (g1-g2)*(g1-g2) + (b1-b2)*(b1-b2))
+}
+
+// hexColor formatea un color RGB como "#rrggbb"
+func hexColor(r, g, b float64) string {
+	return fmt.Sprintf("#%02x%02x%02x", uint8(math.Round(r)), uint8(math.Round(g)), uint8(math.Round(b)))
+}
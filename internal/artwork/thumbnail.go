@@ -0,0 +1,223 @@
+package artwork
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"strings"
+
+	"github.com/chai2010/webp"
+)
+
+// Size es un tamaño de miniatura, identificado por su nombre en la clave
+// del archivo (p. ej. "artwork/3f9a…-card.jpg")
+type Size struct {
+	Name  string
+	Width int
+}
+
+// Tamaños de miniatura que se generan para cada imagen
+var (
+	SizeThumb  = Size{Name: "thumb", Width: 160}
+	SizeCard   = Size{Name: "card", Width: 400}
+	SizeDetail = Size{Name: "detail", Width: 800}
+)
+
+// Sizes lista los tamaños de miniatura, de menor a mayor
+var Sizes = []Size{SizeThumb, SizeCard, SizeDetail}
+
+// thumbnailQuality es la calidad JPEG y WebP de las miniaturas
+const thumbnailQuality = 82
+
+// thumbnailFormat es un formato en el que se guarda cada miniatura
+type thumbnailFormat struct {
+	ext    string
+	encode func(w io.Writer, img image.Image) error
+}
+
+// Formatos de miniatura: JPEG, que muestra cualquier navegador, y WebP, más
+// liviano, para los que lo aceptan
+var (
+	formatJPEG = thumbnailFormat{ext: ".jpg", encode: func(w io.Writer, img image.Image) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: thumbnailQuality})
+	}}
+	formatWebP = thumbnailFormat{ext: ".webp", encode: func(w io.Writer, img image.Image) error {
+		return webp.Encode(w, img, &webp.Options{Quality: thumbnailQuality})
+	}}
+)
+
+// thumbnailFormats lista los formatos en que se genera cada miniatura
+var thumbnailFormats = []thumbnailFormat{formatJPEG, formatWebP}
+
+// resizable indica las extensiones que se pueden decodificar para generar
+// miniaturas
+var resizable = map[string]bool{
+	".jpg":  true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
+// localKey retorna la clave del almacenamiento de una URL de arte local y
+// si admite miniaturas
+func localKey(url string) (string, bool) {
+	key, ok := strings.CutPrefix(url, URLPrefix+keyPrefix)
+	if !ok {
+		return "", false
+	}
+	dot := strings.LastIndex(key, ".")
+	if dot < 0 || !resizable[key[dot:]] || strings.Contains(key[:dot], "-") {
+		return "", false
+	}
+	return keyPrefix + key, true
+}
+
+// thumbnailKey retorna la clave de la miniatura de un tamaño en un formato
+func thumbnailKey(key string, size Size, format thumbnailFormat) string {
+	return key[:strings.LastIndex(key, ".")] + "-" + size.Name + format.ext
+}
+
+// ThumbnailURL retorna la URL de la miniatura JPEG de un tamaño para una URL
+// de arte local, o la misma URL si es externa o no admite miniaturas
+func ThumbnailURL(url string, size Size) string {
+	key, ok := localKey(url)
+	if !ok {
+		return url
+	}
+	return URLPrefix + thumbnailKey(key, size, formatJPEG)
+}
+
+// Srcset retorna el atributo srcset con todas las miniaturas JPEG de una
+// URL de arte local, o vacío si es externa o no admite miniaturas
+func Srcset(url string) string {
+	return srcset(url, formatJPEG)
+}
+
+// SrcsetWebP retorna el srcset con las miniaturas WebP de una URL de arte
+// local, para un <source type="image/webp">, o vacío si no las tiene
+func SrcsetWebP(url string) string {
+	return srcset(url, formatWebP)
+}
+
+// srcset arma el srcset con las miniaturas de un formato
+func srcset(url string, format thumbnailFormat) string {
+	key, ok := localKey(url)
+	if !ok {
+		return ""
+	}
+	entries := make([]string, 0, len(Sizes))
+	for _, size := range Sizes {
+		entries = append(entries, fmt.Sprintf("%s%s %dw", URLPrefix, thumbnailKey(key, size, format), size.Width))
+	}
+	return strings.Join(entries, ", ")
+}
+
+// Thumbnails genera las miniaturas que falten para una URL de arte local,
+// en cada tamaño y formato, y retorna cuántas creó. Con force se regeneran
+// todas. Las URLs externas o que no admiten miniaturas se ignoran.
+func (a *Artwork) Thumbnails(url string, force bool) (int, error) {
+	key, ok := localKey(url)
+	if !ok {
+		return 0, nil
+	}
+
+	missing := make(map[Size][]thumbnailFormat)
+	for _, size := range Sizes {
+		for _, format := range thumbnailFormats {
+			exists, err := a.store.Exists(thumbnailKey(key, size, format))
+			if err != nil {
+				return 0, err
+			}
+			if force || !exists {
+				missing[size] = append(missing[size], format)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	file, err := a.store.Open(key)
+	if err != nil {
+		return 0, fmt.Errorf("error abriendo %s: %w", key, err)
+	}
+	defer file.Close()
+
+	src, _, err := image.Decode(file)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	// Se aplana una sola vez y cada tamaño solo muestrea la copia
+	flat := flatten(src)
+	var created int
+	for _, size := range Sizes {
+		if len(missing[size]) == 0 {
+			continue
+		}
+		thumb := resize(flat, size.Width)
+		for _, format := range missing[size] {
+			var buf bytes.Buffer
+			if err := format.encode(&buf, thumb); err != nil {
+				return created, fmt.Errorf("error codificando miniatura %s%s: %w", size.Name, format.ext, err)
+			}
+			if err := a.store.Put(thumbnailKey(key, size, format), buf.Bytes()); err != nil {
+				return created, err
+			}
+			created++
+		}
+	}
+	return created, nil
+}
+
+// flatten copia la imagen a RGBA con origen en (0, 0), componiendo las
+// transparencias sobre blanco, ya que las miniaturas JPEG no las admiten y
+// las WebP deben verse igual
+func flatten(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+	return flat
+}
+
+// resize escala una imagen aplanada al ancho indicado manteniendo la
+// proporción, con un promedio por área que da buenos resultados al reducir.
+// Las imágenes más angostas no se agrandan y se retornan tal cual.
+func resize(flat *image.RGBA, width int) *image.RGBA {
+	sw, sh := flat.Rect.Dx(), flat.Rect.Dy()
+	if sw <= width {
+		return flat
+	}
+	height := max(1, sh*width/sw)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+
+			var r, g, b, n int
+			for sy := y0; sy < y1; sy++ {
+				row := flat.Pix[sy*flat.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					r += int(row[sx*4])
+					g += int(row[sx*4+1])
+					b += int(row[sx*4+2])
+					n++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
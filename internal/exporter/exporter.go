@@ -0,0 +1,432 @@
+// Package exporter escribe la colección (o la parte que coincide con un
+// filtro) en CSV, JSON por líneas y CSV compatible con la importación de
+// Discogs.
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/importer"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// Formatos de exportación
+const (
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatDiscogs = "discogs"
+)
+
+// Formats lista los formatos de exportación disponibles
+var Formats = []string{FormatCSV, FormatNDJSON, FormatDiscogs}
+
+// batchSize es la cantidad de records que se leen por consulta al exportar
+const batchSize = 200
+
+// IsValidFormat verifica si un formato de exportación es válido
+func IsValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatLabel retorna el nombre legible de un formato de exportación
+func FormatLabel(format string) string {
+	switch format {
+	case FormatCSV:
+		return "CSV"
+	case FormatNDJSON:
+		return "JSON por líneas (NDJSON)"
+	case FormatDiscogs:
+		return "CSV para Discogs"
+	}
+	return format
+}
+
+// ContentType retorna el tipo MIME de un formato de exportación
+func ContentType(format string) string {
+	if format == FormatNDJSON {
+		return "application/x-ndjson; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FileName retorna el nombre sugerido del archivo exportado
+func FileName(format string, now time.Time) string {
+	date := now.Format("2006-01-02")
+	switch format {
+	case FormatNDJSON:
+		return "vinilo-" + date + ".ndjson"
+	case FormatDiscogs:
+		return "vinilo-discogs-" + date + ".csv"
+	}
+	return "vinilo-" + date + ".csv"
+}
+
+// Exporter lee los records y sus datos relacionados para exportarlos
+type Exporter struct {
+	records     *repository.RecordRepository
+	identifiers *repository.IdentifierRepository
+	tags        *repository.TagRepository
+	fields      *repository.CustomFieldRepository
+}
+
+// New crea un nuevo exportador
+func New(records *repository.RecordRepository, identifiers *repository.IdentifierRepository, tags *repository.TagRepository, fields *repository.CustomFieldRepository) *Exporter {
+	return &Exporter{records: records, identifiers: identifiers, tags: tags, fields: fields}
+}
+
+// entry agrupa un record con sus tags e identificadores
+type entry struct {
+	record      *models.Record
+	tags        []string
+	identifiers []*models.Identifier
+}
+
+// identifier retorna los valores de los identificadores de un tipo, separados por comas
+func (e entry) identifier(tipo string) string {
+	var values []string
+	for _, identifier := range e.identifiers {
+		if identifier.Tipo == tipo {
+			values = append(values, identifier.Valor)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// Export escribe los records que coinciden con el filtro en el formato
+// indicado y retorna cuántos se exportaron
+func (e *Exporter) Export(w io.Writer, format string, filter models.RecordFilter) (int, error) {
+	var writer entryWriter
+	switch format {
+	case FormatCSV:
+		fields, err := e.fields.GetAll()
+		if err != nil {
+			return 0, err
+		}
+		writer = newCSVWriter(w, fields)
+	case FormatNDJSON:
+		writer = &ndjsonWriter{encoder: json.NewEncoder(w)}
+	case FormatDiscogs:
+		writer = newDiscogsWriter(w)
+	default:
+		return 0, fmt.Errorf("formato de exportación no soportado: %s", format)
+	}
+
+	count := 0
+	for offset := 0; ; offset += batchSize {
+		records, err := e.records.List(filter, batchSize, offset)
+		if err != nil {
+			return count, err
+		}
+
+		for _, record := range records {
+			entry, err := e.load(record)
+			if err != nil {
+				return count, err
+			}
+			if err := writer.write(entry); err != nil {
+				return count, fmt.Errorf("error escribiendo exportación: %w", err)
+			}
+			count++
+		}
+
+		if len(records) < batchSize {
+			break
+		}
+	}
+
+	if err := writer.flush(); err != nil {
+		return count, fmt.Errorf("error escribiendo exportación: %w", err)
+	}
+	return count, nil
+}
+
+// load obtiene los tags e identificadores de un record
+func (e *Exporter) load(record *models.Record) (entry, error) {
+	tags, err := e.tags.GetByRecord(record.ID)
+	if err != nil {
+		return entry{}, err
+	}
+	identifiers, err := e.identifiers.GetByRecord(record.ID)
+	if err != nil {
+		return entry{}, err
+	}
+
+	result := entry{record: record, identifiers: identifiers}
+	for _, tag := range tags {
+		result.tags = append(result.tags, tag.Nombre)
+	}
+	return result, nil
+}
+
+// entryWriter escribe records en un formato de exportación
+type entryWriter interface {
+	write(e entry) error
+	flush() error
+}
+
+// csvWriter escribe el CSV propio de la colección. Las columnas usan las
+// mismas claves que la importación, de modo que el archivo se puede volver
+// a importar sin reasignar columnas.
+type csvWriter struct {
+	w      *csv.Writer
+	fields []*models.CustomField
+	header bool
+}
+
+// newCSVWriter crea un writer CSV con una columna por campo personalizado
+func newCSVWriter(w io.Writer, fields []*models.CustomField) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), fields: fields}
+}
+
+// csvColumns lista las columnas fijas del CSV de la colección
+var csvColumns = []string{
+	"id", "titulo", "artista", "sello", "catalog_number", "anio", "formato",
+	"generos", "estilos", "pais", "duracion_total", "arte_url", "condicion",
+	"notas", "rating", "review", "tags",
+}
+
+func (c *csvWriter) write(e entry) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	r := e.record
+	row := []string{
+		r.ID,
+		r.Titulo,
+		r.Artista,
+		r.Sello.String,
+		r.CatalogNumber.String,
+		yearText(r),
+		r.Formato.String,
+		strings.Join(r.GetGenerosAsSlice(), ", "),
+		strings.Join(r.GetEstilosAsSlice(), ", "),
+		r.Pais.String,
+		r.DuracionTotal.String,
+		r.ArteURL.String,
+		r.Condicion.String,
+		r.Notas.String,
+		ratingText(r),
+		r.Review.String,
+		strings.Join(e.tags, ", "),
+	}
+	for _, tipo := range models.IdentifierTypes {
+		row = append(row, e.identifier(tipo))
+	}
+	campos := r.GetCampos()
+	for _, field := range c.fields {
+		row = append(row, field.InputValue(campos[field.Clave]))
+	}
+
+	return writeEscaped(c.w, row)
+}
+
+func (c *csvWriter) flush() error {
+	// Sin records igual se escribe el encabezado
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// writeHeader escribe el encabezado la primera vez que se llama
+func (c *csvWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+
+	header := append([]string{}, csvColumns...)
+	for _, tipo := range models.IdentifierTypes {
+		header = append(header, importer.IdentifierTargetPrefix+tipo)
+	}
+	for _, field := range c.fields {
+		header = append(header, models.CustomFieldFilterPrefix+field.Clave)
+	}
+	return c.w.Write(header)
+}
+
+// recordJSON es la representación completa de un record en NDJSON
+type recordJSON struct {
+	ID              string           `json:"id"`
+	Titulo          string           `json:"titulo"`
+	Artista         string           `json:"artista"`
+	Sello           string           `json:"sello,omitempty"`
+	CatalogNumber   string           `json:"catalog_number,omitempty"`
+	Anio            int32            `json:"anio,omitempty"`
+	Formato         string           `json:"formato,omitempty"`
+	Generos         []string         `json:"generos,omitempty"`
+	Estilos         []string         `json:"estilos,omitempty"`
+	Pais            string           `json:"pais,omitempty"`
+	Tracklist       []models.Track   `json:"tracklist,omitempty"`
+	DuracionTotal   string           `json:"duracion_total,omitempty"`
+	ArteURL         string           `json:"arte_url,omitempty"`
+	Condicion       string           `json:"condicion,omitempty"`
+	Notas           string           `json:"notas,omitempty"`
+	LocationID      string           `json:"location_id,omitempty"`
+	Rating          float64          `json:"rating,omitempty"`
+	Review          string           `json:"review,omitempty"`
+	Campos          map[string]any   `json:"campos,omitempty"`
+	Tags            []string         `json:"tags,omitempty"`
+	Identificadores []identifierJSON `json:"identificadores,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// identifierJSON es la representación de un identificador en NDJSON
+type identifierJSON struct {
+	Tipo        string `json:"tipo"`
+	Valor       string `json:"valor"`
+	Descripcion string `json:"descripcion,omitempty"`
+}
+
+// ndjsonWriter escribe un objeto JSON por línea con todos los datos del record
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonWriter) write(e entry) error {
+	r := e.record
+	item := recordJSON{
+		ID:            r.ID,
+		Titulo:        r.Titulo,
+		Artista:       r.Artista,
+		Sello:         r.Sello.String,
+		CatalogNumber: r.CatalogNumber.String,
+		Anio:          r.Anio.Int32,
+		Formato:       r.Formato.String,
+		Generos:       r.GetGenerosAsSlice(),
+		Estilos:       r.GetEstilosAsSlice(),
+		Pais:          r.Pais.String,
+		Tracklist:     r.GetTracklistAsSlice(),
+		DuracionTotal: r.DuracionTotal.String,
+		ArteURL:       r.ArteURL.String,
+		Condicion:     r.Condicion.String,
+		Notas:         r.Notas.String,
+		LocationID:    r.LocationID.String,
+		Rating:        r.Rating.Float64,
+		Review:        r.Review.String,
+		Campos:        r.GetCampos(),
+		Tags:          e.tags,
+		CreatedAt:     r.CreatedAt,
+		UpdatedAt:     r.UpdatedAt,
+	}
+	for _, identifier := range e.identifiers {
+		item.Identificadores = append(item.Identificadores, identifierJSON{
+			Tipo:        identifier.Tipo,
+			Valor:       identifier.Valor,
+			Descripcion: identifier.Descripcion.String,
+		})
+	}
+
+	return n.encoder.Encode(item)
+}
+
+func (n *ndjsonWriter) flush() error {
+	return nil
+}
+
+// discogsColumns son las columnas que reconoce la importación de
+// colecciones de Discogs
+var discogsColumns = []string{
+	"Catalog#", "Artist", "Title", "Label", "Format", "Rating", "Released",
+	"release_id", "Collection Media Condition", "Collection Sleeve Condition",
+	"Collection Notes",
+}
+
+// discogsWriter escribe un CSV con las columnas de la exportación de
+// colecciones de Discogs, que Discogs acepta al importar
+type discogsWriter struct {
+	w *csv.Writer
+}
+
+// newDiscogsWriter crea un writer CSV para Discogs y escribe su encabezado
+func newDiscogsWriter(w io.Writer) *discogsWriter {
+	writer := &discogsWriter{w: csv.NewWriter(w)}
+	writer.w.Write(discogsColumns)
+	return writer
+}
+
+func (d *discogsWriter) write(e entry) error {
+	r := e.record
+	media, sleeve := splitDiscogsCondition(r.Condicion.String)
+
+	rating := ""
+	if r.Rating.Valid {
+		// Discogs solo admite estrellas enteras
+		rating = strconv.Itoa(int(math.Round(r.Rating.Float64)))
+	}
+
+	return writeEscaped(d.w, []string{
+		r.CatalogNumber.String,
+		r.Artista,
+		r.Titulo,
+		r.Sello.String,
+		r.Formato.String,
+		rating,
+		yearText(r),
+		e.identifier(models.IdentifierDiscogs),
+		media,
+		sleeve,
+		r.Notas.String,
+	})
+}
+
+func (d *discogsWriter) flush() error {
+	d.w.Flush()
+	return d.w.Error()
+}
+
+// writeEscaped escribe una fila de datos escapando las celdas que una
+// planilla ejecutaría como fórmula
+func writeEscaped(w *csv.Writer, row []string) error {
+	for i, value := range row {
+		row[i] = importer.EscapeCell(value)
+	}
+	return w.Write(row)
+}
+
+// splitDiscogsCondition separa la condición guardada al importar desde
+// Discogs ("Disco: X · Funda: Y") en el estado del disco y de la funda
+func splitDiscogsCondition(condicion string) (media, sleeve string) {
+	for _, part := range strings.Split(condicion, " · ") {
+		if v, ok := strings.CutPrefix(part, "Disco: "); ok {
+			media = v
+		} else if v, ok := strings.CutPrefix(part, "Funda: "); ok {
+			sleeve = v
+		} else if media == "" {
+			media = part
+		}
+	}
+	return media, sleeve
+}
+
+// yearText retorna el año del record como texto, o vacío si no tiene
+func yearText(r *models.Record) string {
+	if !r.Anio.Valid {
+		return ""
+	}
+	return strconv.Itoa(int(r.Anio.Int32))
+}
+
+// ratingText retorna la calificación del record como texto, o vacío si no tiene
+func ratingText(r *models.Record) string {
+	if !r.Rating.Valid {
+		return ""
+	}
+	return models.FormatRatingValue(r.Rating.Float64)
+}
@@ -0,0 +1,186 @@
+package exporter
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/importer"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// newTestDB crea una base de datos en memoria con todas las migraciones
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("error abriendo BD: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob("../../migrations/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no se encontraron migraciones: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("error leyendo %s: %v", file, err)
+		}
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("error aplicando %s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// formulaRecord crea un record cuyos textos una planilla ejecutaría como
+// fórmulas, con un campo personalizado negativo y un identificador
+func formulaRecord(t *testing.T, db *database.DB) (*models.Record, []*models.CustomField) {
+	t.Helper()
+	fields := repository.NewCustomFieldRepository(db)
+	saldo := models.NewCustomField("Saldo", models.CustomFieldNumber)
+	if err := fields.Create(saldo); err != nil {
+		t.Fatalf("error creando campo: %v", err)
+	}
+
+	record := models.NewRecord()
+	record.Artista = "@Nirvana"
+	record.Titulo = `=HYPERLINK("http://ejemplo.com","Nevermind")`
+	record.Sello = sql.NullString{String: "+DGC", Valid: true}
+	record.CatalogNumber = sql.NullString{String: "-24425", Valid: true}
+	record.Formato = sql.NullString{String: "LP", Valid: true}
+	record.Condicion = sql.NullString{String: "Disco: =1+1 · Funda: VG+", Valid: true}
+	record.Notas = sql.NullString{String: "'=ya escapado", Valid: true}
+	record.Review = sql.NullString{String: "'apóstrofo normal", Valid: true}
+	record.Rating = sql.NullFloat64{Float64: 4, Valid: true}
+	record.SetCampos(map[string]any{"saldo": -3.0})
+	if err := repository.NewRecordRepository(db).Create(record); err != nil {
+		t.Fatalf("error creando record: %v", err)
+	}
+
+	identifier, err := models.NewIdentifier(record.ID, models.IdentifierDiscogs, "367113")
+	if err != nil {
+		t.Fatalf("identificador inválido: %v", err)
+	}
+	if err := repository.NewIdentifierRepository(db).Create(identifier); err != nil {
+		t.Fatalf("error creando identificador: %v", err)
+	}
+	return record, []*models.CustomField{saldo}
+}
+
+// export exporta la colección completa en el formato indicado
+func export(t *testing.T, db *database.DB, format string) []byte {
+	t.Helper()
+	e := New(
+		repository.NewRecordRepository(db),
+		repository.NewIdentifierRepository(db),
+		repository.NewTagRepository(db),
+		repository.NewCustomFieldRepository(db),
+	)
+	var buf bytes.Buffer
+	count, err := e.Export(&buf, format, models.RecordFilter{})
+	if err != nil || count != 1 {
+		t.Fatalf("exportados = %d (%v), se esperaba 1", count, err)
+	}
+	return buf.Bytes()
+}
+
+func TestEscapeCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Nevermind", "Nevermind"},
+		{"", ""},
+		{"=1+1", "'=1+1"},
+		{"+56 9 1234", "'+56 9 1234"},
+		{"-3", "'-3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"'=1", "''=1"},
+		{"'hola", "'hola"},
+		{"a=b", "a=b"},
+	}
+
+	for _, tt := range tests {
+		if got := importer.EscapeCell(tt.value); got != tt.want {
+			t.Errorf("EscapeCell(%q) = %q, se esperaba %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExportCSVRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+	record, fields := formulaRecord(t, db)
+	content := export(t, db, FormatCSV)
+
+	for _, cell := range []string{`"'=HYPERLINK(""http://ejemplo.com"",""Nevermind"")"`, ",'@Nirvana,", ",'+DGC,", ",'-24425,", ",''=ya escapado,", ",'apóstrofo normal,", ",'-3\n"} {
+		if !bytes.Contains(content, []byte(cell)) {
+			t.Errorf("el CSV no tiene la celda %s:\n%s", cell, content)
+		}
+	}
+
+	// Las columnas se asignan solas y los valores vuelven sin el escape
+	table, err := importer.ReadTable(content, importer.DetectFormat(content))
+	if err != nil {
+		t.Fatalf("error leyendo CSV: %v", err)
+	}
+	rows, err := importer.MapTable(table, importer.GuessMapping(table.Columns, fields), fields)
+	if err != nil || len(rows) != 1 || rows[0].Status != "" {
+		t.Fatalf("filas = %+v (%v)", rows, err)
+	}
+	got := rows[0].Record
+	for _, field := range []struct{ name, got, want string }{
+		{"artista", got.Artista, record.Artista},
+		{"título", got.Titulo, record.Titulo},
+		{"sello", got.Sello.String, record.Sello.String},
+		{"catálogo", got.CatalogNumber.String, record.CatalogNumber.String},
+		{"condición", got.Condicion.String, record.Condicion.String},
+		{"notas", got.Notas.String, record.Notas.String},
+		{"reseña", got.Review.String, record.Review.String},
+	} {
+		if field.got != field.want {
+			t.Errorf("%s = %q, se esperaba %q", field.name, field.got, field.want)
+		}
+	}
+	if got.Rating.Float64 != 4 || got.GetCampos()["saldo"] != -3.0 {
+		t.Errorf("calificación %v, campos %v", got.Rating.Float64, got.GetCampos())
+	}
+	if len(rows[0].Identifiers) != 1 || rows[0].Identifiers[0].Valor != "367113" {
+		t.Errorf("identificadores = %+v", rows[0].Identifiers)
+	}
+}
+
+func TestExportDiscogsRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+	record, _ := formulaRecord(t, db)
+	content := export(t, db, FormatDiscogs)
+
+	for _, cell := range []string{"'-24425,'@Nirvana,", ",'+DGC,", ",'=1+1,VG+,"} {
+		if !bytes.Contains(content, []byte(cell)) {
+			t.Errorf("el CSV no tiene la celda %s:\n%s", cell, content)
+		}
+	}
+
+	rows, err := importer.ParseDiscogsCSV(bytes.NewReader(content))
+	if err != nil || len(rows) != 1 || rows[0].Status != "" {
+		t.Fatalf("filas = %+v (%v)", rows, err)
+	}
+	got := rows[0].Record
+	if got.Artista != record.Artista || got.Titulo != record.Titulo || got.Sello != record.Sello || got.CatalogNumber != record.CatalogNumber {
+		t.Errorf("record = %s - %s, %s %s", got.Artista, got.Titulo, got.Sello.String, got.CatalogNumber.String)
+	}
+	if got.Condicion.String != record.Condicion.String || got.Notas.String != record.Notas.String {
+		t.Errorf("condición %q, notas %q", got.Condicion.String, got.Notas.String)
+	}
+	if len(rows[0].Identifiers) != 1 || rows[0].Identifiers[0].Valor != "367113" {
+		t.Errorf("identificadores = %+v", rows[0].Identifiers)
+	}
+}
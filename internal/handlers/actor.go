@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// actorHeaders son los encabezados con los que un proxy con autenticación
+// (p. ej. oauth2-proxy, Authelia o nginx con auth_request) informa el
+// usuario que hace la petición
+var actorHeaders = []string{"Remote-User", "X-Forwarded-User", "X-Auth-Request-User"}
+
+// adminActor es el actor de los cambios hechos desde el admin cuando la
+// petición no trae un usuario autenticado
+const adminActor = "admin"
+
+// proxyUserKey es la clave del contexto con el usuario informado por el proxy
+type proxyUserKey struct{}
+
+// ProxyUser es un middleware que toma el usuario de la petición de los
+// encabezados del proxy con autenticación. Solo se debe usar cuando ese
+// proxy es el único camino al servidor y reemplaza los encabezados que
+// envíe el cliente; sin este middleware los encabezados se ignoran.
+func ProxyUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range actorHeaders {
+			if user := strings.TrimSpace(r.Header.Get(header)); user != "" {
+				r = r.WithContext(context.WithValue(r.Context(), proxyUserKey{}, user))
+				break
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestActor retorna el nombre con el que los cambios de una petición
+// quedan en el historial: el usuario informado por el proxy si se confía en
+// él (ver ProxyUser), el de autenticación básica o, si no hay ninguno,
+// adminActor
+func requestActor(r *http.Request) string {
+	if user, ok := r.Context().Value(proxyUserKey{}).(string); ok {
+		return user
+	}
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		return user
+	}
+	return adminActor
+}
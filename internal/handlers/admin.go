@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/metadata"
 	"github.com/rodrwan/vinilo/internal/models"
 	"github.com/rodrwan/vinilo/internal/repository"
 	"github.com/rodrwan/vinilo/web/templates"
@@ -16,16 +23,24 @@ import (
 // Proporciona endpoints para gestionar la colección de discos de vinilo
 // desde una interfaz administrativa con funcionalidades CRUD completas.
 type AdminHandler struct {
-	repo *repository.RecordRepository
+	repo      *repository.RecordRepository
+	detail    RecordDetailSources
+	conflicts RecordConflicts
+	providers []metadata.Provider
+	artwork   *artwork.Artwork
 }
 
 // NewAdminHandler crea un nuevo handler administrativo
 // Parámetros:
 //   - repo: Repositorio de records para operaciones de base de datos
+//   - detail: Repositorios relacionados (ubicaciones, préstamos, ...)
+//   - conflicts: Repositorios para explicar las ediciones simultáneas de un record
+//   - providers: Proveedores de metadatos para prellenar nuevos records (puede ser vacío)
+//   - art: Almacenamiento del arte subido desde los formularios
 //
 // Retorna: Una instancia configurada de AdminHandler
-func NewAdminHandler(repo *repository.RecordRepository) *AdminHandler {
-	return &AdminHandler{repo: repo}
+func NewAdminHandler(repo *repository.RecordRepository, detail RecordDetailSources, conflicts RecordConflicts, providers []metadata.Provider, art *artwork.Artwork) *AdminHandler {
+	return &AdminHandler{repo: repo, detail: detail, conflicts: conflicts, providers: providers, artwork: art}
 }
 
 // ListHandler maneja el listado administrativo de records
@@ -34,19 +49,23 @@ func NewAdminHandler(repo *repository.RecordRepository) *AdminHandler {
 //
 // Funcionalidad:
 // - Muestra una lista paginada de todos los records en la base de datos
-// - Soporta búsqueda por texto en título y artista
+// - Soporta búsqueda por texto en título, artista, sello, catálogo e identificadores
 // - Implementa paginación con 20 registros por página
 // - Permite navegación entre páginas
 //
 // Parámetros de Query:
 //   - page: Número de página (opcional, default: 1)
 //   - search: Término de búsqueda (opcional)
+//   - min_rating: Calificación mínima, de 0.5 a 5 (opcional)
+//   - tag: Muestra solo los records con ese tag (opcional)
+//   - sort: recent, rating, artista, titulo o anio (opcional, default: recent)
 //
 // Comportamiento:
 // - Si se proporciona search, filtra records que contengan el término
-// - Si no hay search, muestra todos los records
+// - Si no hay filtros, muestra todos los records
 // - Calcula automáticamente el total de registros para paginación
 // - Renderiza la vista RecordsList con datos paginados
+// - Permite seleccionar records y aplicarles acciones masivas (ver BulkHandler)
 //
 // Respuestas:
 //   - 200: Lista de records renderizada correctamente
@@ -57,7 +76,8 @@ func (h *AdminHandler) ListHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Obtener parámetros de query
 		pageStr := r.URL.Query().Get("page")
-		searchTerm := strings.TrimSpace(r.URL.Query().Get("search"))
+		filter := models.ParseRecordFilter(r.URL.Query())
+		filter.Search = strings.TrimSpace(filter.Search)
 
 		// Parsear página
 		page := 1
@@ -71,35 +91,136 @@ func (h *AdminHandler) ListHandler() http.HandlerFunc {
 		limit := 20
 		offset := (page - 1) * limit
 
-		var records []*models.Record
-		var total int
-		var err error
+		// Buscar y filtrar
+		records, err := h.repo.List(filter, limit, offset)
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
+		}
+		total, err := h.repo.CountList(filter)
 
-		// Buscar o obtener todos
-		if searchTerm != "" {
-			records, err = h.repo.Search(searchTerm, limit, offset)
-			if err != nil {
-				http.Error(w, "Error buscando records", http.StatusInternalServerError)
+		if err != nil {
+			http.Error(w, "Error contando records", http.StatusInternalServerError)
+			return
+		}
+
+		locations, err := h.detail.Locations.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo ubicaciones", http.StatusInternalServerError)
+			return
+		}
+
+		bulk := &templates.BulkEditView{
+			Locations: models.FlattenLocationTree(locations),
+			Return:    r.URL.RequestURI(),
+		}
+
+		// Renderizar la página administrativa
+		component := templates.RecordsList(records, total, page, filter, bulk)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// BulkHandler maneja las acciones masivas sobre los records seleccionados
+//
+// Endpoint: POST /admin/records/bulk
+//
+// Funcionalidad:
+// - Fija un campo, agrega o quita un tag o un género, mueve de ubicación o
+//   envía a la papelera todos los records seleccionados
+// - Todos los cambios se aplican en una sola transacción: si uno falla, no
+//   se aplica ninguno
+// - Cada record modificado queda en su historial
+//
+// Parámetros del Formulario:
+//   - record_ids: IDs de los records seleccionados (uno o más)
+//   - accion: campo, agregar_tag, quitar_tag, agregar_genero, quitar_genero, ubicacion o eliminar
+//   - campo: formato, condicion, sello, pais o anio (para accion=campo)
+//   - valor: Valor del campo; vacío lo limpia (para accion=campo)
+//   - tag: Tag a agregar o quitar
+//   - genero: Género a agregar o quitar
+//   - location_id: Ubicación de destino; vacío deja los records sin ubicación
+//   - version_<id>: Versión de cada record seleccionado al cargar el listado (opcional)
+//   - volver: URL del listado desde el que se hizo la acción (opcional)
+//
+// Respuestas:
+//   - 200: Resumen de lo que cambió en cada record
+//   - 400: Sin records seleccionados o acción inválida
+//   - 404: Ubicación no encontrada
+//   - 409: Algún record cambió desde que se cargó el listado; no se aplicó ningún cambio
+//   - 500: Error interno del servidor; no se aplicó ningún cambio
+//
+// Vista: templates.AdminBulkResult
+func (h *AdminHandler) BulkHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		ids := r.Form["record_ids"]
+		if len(ids) == 0 {
+			http.Error(w, "Selecciona al menos un record", http.StatusBadRequest)
+			return
+		}
+
+		action := models.BulkAction{Accion: r.FormValue("accion")}
+		switch action.Accion {
+		case models.BulkSetField:
+			action.Campo = r.FormValue("campo")
+			action.Valor = strings.TrimSpace(r.FormValue("valor"))
+		case models.BulkAddTag, models.BulkRemoveTag:
+			action.Valor = r.FormValue("tag")
+		case models.BulkAddGenre, models.BulkRemoveGenre:
+			action.Valor = r.FormValue("genero")
+		case models.BulkMoveLocation:
+			action.Valor = r.FormValue("location_id")
+		}
+		if err := action.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if action.Accion == models.BulkMoveLocation && action.Valor != "" {
+			if _, err := h.detail.Locations.GetByID(action.Valor); err != nil {
+				http.Error(w, "Ubicación no encontrada", http.StatusNotFound)
 				return
 			}
-			total, err = h.repo.CountSearch(searchTerm)
-		} else {
-			records, err = h.repo.GetAll(limit, offset)
-			if err != nil {
-				http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+		}
+
+		versions := make(map[string]int, len(ids))
+		for _, id := range ids {
+			if version, err := strconv.Atoi(r.FormValue("version_" + id)); err == nil {
+				versions[id] = version
+			}
+		}
+
+		result, err := h.repo.WithActor(requestActor(r)).Bulk(ids, versions, action)
+		if err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				http.Error(w, "Algún record seleccionado cambió desde que se cargó el listado; no se modificó ninguno. Recarga el listado y vuelve a intentarlo", http.StatusConflict)
 				return
 			}
-			total, err = h.repo.Count()
+			http.Error(w, "Error aplicando la acción; no se modificó ningún record", http.StatusInternalServerError)
+			return
 		}
 
+		locations, err := locationIndex(h.detail.Locations)
 		if err != nil {
-			http.Error(w, "Error contando records", http.StatusInternalServerError)
+			http.Error(w, "Error obteniendo ubicaciones", http.StatusInternalServerError)
 			return
 		}
 
-		// Renderizar la página administrativa
-		component := templates.RecordsList(records, total, page, searchTerm)
-		templ.Handler(component).ServeHTTP(w, r)
+		view := templates.BulkResultView{
+			Result:    result,
+			Locations: locations,
+			Return:    "/admin/records",
+		}
+		// Solo se vuelve a listados del admin
+		if back := r.FormValue("volver"); strings.HasPrefix(back, "/admin/records") {
+			view.Return = back
+		}
+		templ.Handler(templates.AdminBulkResult(view)).ServeHTTP(w, r)
 	}
 }
 
@@ -120,6 +241,7 @@ func (h *AdminHandler) ListHandler() http.HandlerFunc {
 // - Valida que el ID esté presente en la URL
 // - Busca el record en la base de datos por ID
 // - Renderiza la vista de detalle con información completa
+// - Responde con la versión del record como ETag, para usarla en If-Match
 //
 // Respuestas:
 //   - 200: Detalle del record renderizado correctamente
@@ -146,8 +268,15 @@ func (h *AdminHandler) DetailHandler() http.HandlerFunc {
 			return
 		}
 
+		view, err := h.detail.Build(record, true)
+		if err != nil {
+			http.Error(w, "Error obteniendo detalle del record", http.StatusInternalServerError)
+			return
+		}
+
 		// Renderizar la página de detalle administrativa
-		component := templates.RecordDetail(record)
+		w.Header().Set("ETag", recordETag(record))
+		component := templates.RecordDetail(view)
 		templ.Handler(component).ServeHTTP(w, r)
 	}
 }
@@ -165,6 +294,7 @@ func (h *AdminHandler) DetailHandler() http.HandlerFunc {
 // Comportamiento:
 // - Obtiene el conteo total de records en la base de datos
 // - Recupera los 5 records más recientes (ordenados por fecha de creación)
+// - Lista los préstamos cuya fecha de devolución ya pasó
 // - Renderiza el dashboard con estadísticas y lista de recientes
 //
 // Respuestas:
@@ -176,6 +306,7 @@ func (h *AdminHandler) DetailHandler() http.HandlerFunc {
 // Datos mostrados:
 //   - Total de records en la colección
 //   - Lista de 5 records más recientes
+//   - Préstamos vencidos con el record y quién lo tiene
 func (h *AdminHandler) HomeHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Obtener estadísticas
@@ -192,8 +323,31 @@ func (h *AdminHandler) HomeHandler() http.HandlerFunc {
 			return
 		}
 
+		// Obtener préstamos vencidos y sus records
+		overdueLoans, err := h.detail.Loans.GetOverdue(time.Now())
+		if err != nil {
+			http.Error(w, "Error obteniendo préstamos vencidos", http.StatusInternalServerError)
+			return
+		}
+
+		recordIDs := make([]string, 0, len(overdueLoans))
+		for _, loan := range overdueLoans {
+			recordIDs = append(recordIDs, loan.RecordID)
+		}
+
+		loanRecords, err := h.repo.GetByIDs(recordIDs)
+		if err != nil {
+			http.Error(w, "Error obteniendo records prestados", http.StatusInternalServerError)
+			return
+		}
+
 		// Renderizar dashboard
-		component := templates.AdminDashboard(totalRecords, recentRecords)
+		component := templates.AdminDashboard(templates.AdminDashboardView{
+			TotalRecords:  totalRecords,
+			RecentRecords: recentRecords,
+			OverdueLoans:  overdueLoans,
+			LoanRecords:   loanRecords,
+		})
 		templ.Handler(component).ServeHTTP(w, r)
 	}
 }
@@ -214,12 +368,22 @@ func (h *AdminHandler) HomeHandler() http.HandlerFunc {
 //   - titulo: Título del disco (requerido)
 //   - artista: Nombre del artista (requerido)
 //   - anio: Año de lanzamiento (opcional)
-//   - generos: Géneros musicales (opcional)
+//   - generos, estilos: Listas separadas por comas (opcional)
+//   - sello, catalog_number, pais, formato, condicion, duracion_total,
+//     arte_url, notas: Datos del disco (opcional)
+//   - arte_archivo: Imagen de arte JPEG, PNG, GIF o WebP; reemplaza a arte_url (opcional)
+//   - tracklist[N][numero|posicion|titulo|duracion]: Canciones del tracklist (opcional)
+//   - identificador.<tipo>: Identificadores que trae la búsqueda de metadatos (opcional)
+//   - location_id: Ubicación física del disco (opcional)
+//   - cf_<clave>: Valor de cada campo personalizado (opcional)
 //
 // Validaciones:
 // - Título y artista son campos obligatorios
 // - El año debe ser un número válido si se proporciona
-// - Los géneros se almacenan como texto libre
+// - Los géneros se separan por comas y se guardan como lista JSON
+// - Los campos personalizados se validan según su tipo
+// - Los identificadores se validan como en el detalle del record
+// - El arte subido debe ser una imagen válida de hasta 10 MB
 //
 // Comportamiento:
 // - Parsea el formulario enviado
@@ -243,8 +407,8 @@ func (h *AdminHandler) CreateRecordHandler() http.HandlerFunc {
 			return
 		}
 
-		// Parsear formulario
-		if err := r.ParseForm(); err != nil {
+		// Parsear formulario, que puede traer el arte como archivo
+		if err := parseUploadForm(r); err != nil {
 			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
 			return
 		}
@@ -254,6 +418,7 @@ func (h *AdminHandler) CreateRecordHandler() http.HandlerFunc {
 		artista := r.FormValue("artista")
 		anioStr := r.FormValue("anio")
 		generos := r.FormValue("generos")
+		locationID := r.FormValue("location_id")
 
 		if titulo == "" || artista == "" {
 			http.Error(w, "Título y artista son requeridos", http.StatusBadRequest)
@@ -273,12 +438,59 @@ func (h *AdminHandler) CreateRecordHandler() http.HandlerFunc {
 		record.Titulo = titulo
 		record.Artista = artista
 		record.Anio = anio
-		if generos != "" {
-			record.Generos = sql.NullString{String: generos, Valid: true}
+		record.SetGeneros(splitCommaList(generos))
+		record.SetEstilos(splitCommaList(r.FormValue("estilos")))
+		record.Sello = formString(r.FormValue("sello"))
+		record.CatalogNumber = formString(r.FormValue("catalog_number"))
+		record.Pais = formString(r.FormValue("pais"))
+		record.Formato = formString(r.FormValue("formato"))
+		record.Condicion = formString(r.FormValue("condicion"))
+		record.DuracionTotal = formString(r.FormValue("duracion_total"))
+		record.ArteURL = formString(r.FormValue("arte_url"))
+		record.Notas = formString(r.FormValue("notas"))
+		record.SetTracklist(parseTracklistForm(r))
+		if locationID != "" {
+			if _, err := h.detail.Locations.GetByID(locationID); err != nil {
+				http.Error(w, "Ubicación no encontrada", http.StatusBadRequest)
+				return
+			}
+			record.LocationID = sql.NullString{String: locationID, Valid: true}
+		}
+
+		identifiers, err := parseIdentifierFields(r, record.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fields, err := h.detail.Fields.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo campos personalizados", http.StatusInternalServerError)
+			return
 		}
+		campos, err := parseCustomFields(r, fields)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		record.SetCampos(campos)
 
-		// Guardar en base de datos
-		if err := h.repo.Create(record); err != nil {
+		arte, err := saveArtworkUpload(r, h.artwork)
+		if err != nil {
+			if artwork.IsValidationError(err) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "Error guardando arte", http.StatusInternalServerError)
+			return
+		}
+		if arte != "" {
+			record.ArteURL = formString(arte)
+		}
+		setArtworkPalette(record, h.artwork)
+
+		// Guardar en base de datos junto a sus identificadores
+		if err := h.repo.WithActor(requestActor(r)).CreateMany([]*models.Record{record}, identifiers); err != nil {
 			http.Error(w, "Error creando record", http.StatusInternalServerError)
 			return
 		}
@@ -296,10 +508,18 @@ func (h *AdminHandler) CreateRecordHandler() http.HandlerFunc {
 // - Muestra el formulario para crear un nuevo record
 // - Proporciona una interfaz de usuario para ingresar datos del disco
 // - Permite al administrador agregar nuevos records a la colección
+// - Si hay proveedores de metadatos configurados, permite buscar el disco y prellenar el formulario
+//
+// Parámetros de Query:
+//   - provider: Proveedor de metadatos a usar (opcional, default: el primero)
+//   - q_artista, q_titulo, q_catalog, q_barcode: Criterios de búsqueda (opcional)
+//   - release: ID del release con el que prellenar el formulario (opcional)
 //
 // Comportamiento:
 // - Renderiza el formulario de creación de records
-// - El formulario incluye campos para título, artista, año y géneros
+// - Con criterios de búsqueda, muestra los releases candidatos
+// - Con un release, completa título, artista, sello, tracklist, arte e identificadores
+// - El administrador revisa los datos prellenados antes de guardar
 // - Al enviar el formulario, se procesa en CreateRecordHandler
 //
 // Respuestas:
@@ -312,9 +532,262 @@ func (h *AdminHandler) CreateRecordHandler() http.HandlerFunc {
 //   - Artista (texto, requerido)
 //   - Año (número, opcional)
 //   - Géneros (texto, opcional)
+//   - Ubicación (selector, opcional)
 func (h *AdminHandler) NewRecordHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		component := templates.NewRecordForm()
-		templ.Handler(component).ServeHTTP(w, r)
+		locations, err := h.detail.Locations.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo ubicaciones", http.StatusInternalServerError)
+			return
+		}
+
+		fields, err := h.detail.Fields.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo campos personalizados", http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		view := templates.NewRecordView{
+			Locations: models.FlattenLocationTree(locations),
+			Fields:    fields,
+			Providers: h.providers,
+			Lookup: metadata.Query{
+				Artista:       query.Get("q_artista"),
+				Titulo:        query.Get("q_titulo"),
+				CatalogNumber: query.Get("q_catalog"),
+				Barcode:       query.Get("q_barcode"),
+			},
+		}
+
+		provider := metadata.Find(h.providers, query.Get("provider"))
+		if provider == nil && len(h.providers) > 0 {
+			provider = h.providers[0]
+		}
+
+		if provider != nil {
+			view.Provider = provider.Name()
+
+			ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+			defer cancel()
+
+			switch {
+			case query.Get("release") != "":
+				view.Release, err = provider.Release(ctx, query.Get("release"))
+			case !view.Lookup.IsEmpty():
+				view.Searched = true
+				view.Candidates, err = provider.Search(ctx, view.Lookup)
+			}
+			if err != nil {
+				// Un proveedor caído no impide cargar el disco a mano
+				view.LookupError = err.Error()
+			}
+		}
+
+		templ.Handler(templates.NewRecordForm(view)).ServeHTTP(w, r)
+	}
+}
+
+// ArtworkHandler maneja el reemplazo del arte de un record por una imagen subida
+//
+// Endpoint: POST /admin/records/{id}/artwork
+//
+// Parámetros del Formulario (multipart):
+//   - arte_archivo: Imagen JPEG, PNG, GIF o WebP de hasta 10 MB (requerido)
+//   - version: Versión del record que se editó (requerido, o como If-Match)
+//
+// Comportamiento:
+// - Guarda la imagen en el almacenamiento local con el hash de su contenido como nombre
+// - Reemplaza la URL de arte del record por la copia local
+// - Si el record cambió desde la versión indicada no lo modifica y muestra el conflicto
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Sin archivo o imagen inválida
+//   - 404: Record no encontrado
+//   - 409: El record cambió desde la versión del formulario
+//   - 412: El record cambió desde la versión de If-Match
+//   - 428: No se indicó la versión del record
+//   - 500: Error interno del servidor
+func (h *AdminHandler) ArtworkHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := h.repo.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		if err := parseUploadForm(r); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		expected, fromHeader, ok := expectedVersion(w, r, record)
+		if !ok {
+			return
+		}
+
+		arte, err := saveArtworkUpload(r, h.artwork)
+		if err != nil {
+			if artwork.IsValidationError(err) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "Error guardando arte", http.StatusInternalServerError)
+			return
+		}
+		if arte == "" {
+			http.Error(w, "Selecciona una imagen", http.StatusBadRequest)
+			return
+		}
+
+		record.Version = expected
+		record.ArteURL = formString(arte)
+		setArtworkPalette(record, h.artwork)
+		record.UpdatedAt = time.Now()
+		if err := h.repo.WithActor(requestActor(r)).Update(record); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				h.conflicts.Write(w, r, record, expected, fromHeader)
+				return
+			}
+			http.Error(w, "Error actualizando record", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+record.ID, http.StatusSeeOther)
+	}
+}
+
+// LocationHandler maneja la ubicación física de un record
+//
+// Endpoint: POST /admin/records/{id}/location
+//
+// Parámetros del Formulario:
+//   - location_id: Ubicación del record; vacío lo deja sin ubicación (opcional)
+//   - version: Versión del record que se editó (requerido, o como If-Match)
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Ubicación no encontrada
+//   - 404: Record no encontrado
+//   - 409: El record cambió desde la versión del formulario
+//   - 412: El record cambió desde la versión de If-Match
+//   - 428: No se indicó la versión del record
+//   - 500: Error interno del servidor
+func (h *AdminHandler) LocationHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		record, err := h.repo.GetByID(recordID)
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		expected, fromHeader, ok := expectedVersion(w, r, record)
+		if !ok {
+			return
+		}
+
+		locationID := r.FormValue("location_id")
+		if locationID != "" {
+			if _, err := h.detail.Locations.GetByID(locationID); err != nil {
+				http.Error(w, "Ubicación no encontrada", http.StatusBadRequest)
+				return
+			}
+		}
+		record.LocationID = sql.NullString{String: locationID, Valid: locationID != ""}
+
+		if err := h.repo.WithActor(requestActor(r)).UpdateLocation(record.ID, expected, record.LocationID); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				h.conflicts.Write(w, r, record, expected, fromHeader)
+				return
+			}
+			http.Error(w, "Error guardando ubicación", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+record.ID, http.StatusSeeOther)
+	}
+}
+
+// RateHandler maneja la calificación y reseña personal de un record
+//
+// Endpoint: POST /admin/records/{id}/rating
+//
+// Funcionalidad:
+// - Guarda la calificación (0.5 a 5 estrellas) y reseña del disco
+// - Guarda la calificación y reseña de cada track del tracklist
+//
+// Parámetros del Formulario:
+//   - rating: Calificación del record; vacío la elimina (opcional)
+//   - review: Reseña libre (opcional)
+//   - track_rating_N: Calificación del track en la posición N (opcional)
+//   - track_review_N: Reseña del track en la posición N (opcional)
+//   - version: Versión del record que se editó (requerido, o como If-Match)
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Calificación fuera de rango o no múltiplo de media estrella
+//   - 404: Record no encontrado
+//   - 409: El record cambió desde la versión del formulario
+//   - 412: El record cambió desde la versión de If-Match
+//   - 428: No se indicó la versión del record
+//   - 500: Error interno del servidor
+func (h *AdminHandler) RateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		record, err := h.repo.GetByID(recordID)
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		expected, fromHeader, ok := expectedVersion(w, r, record)
+		if !ok {
+			return
+		}
+
+		rating, ok := parseRatingField(r.FormValue("rating"))
+		if !ok {
+			http.Error(w, "Calificación inválida", http.StatusBadRequest)
+			return
+		}
+
+		tracklist := record.GetTracklistAsSlice()
+		for i := range tracklist {
+			trackRating, ok := parseRatingField(r.FormValue(fmt.Sprintf("track_rating_%d", i)))
+			if !ok {
+				http.Error(w, "Calificación de track inválida", http.StatusBadRequest)
+				return
+			}
+			tracklist[i].Rating = trackRating.Float64
+			tracklist[i].Review = strings.TrimSpace(r.FormValue(fmt.Sprintf("track_review_%d", i)))
+		}
+		record.SetTracklist(tracklist)
+		record.Rating = rating
+		record.Review = formString(r.FormValue("review"))
+
+		if err := h.repo.WithActor(requestActor(r)).UpdateRating(record.ID, expected, record.Rating, record.Review, record.Tracklist); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				h.conflicts.Write(w, r, record, expected, fromHeader)
+				return
+			}
+			http.Error(w, "Error guardando calificación", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+record.ID, http.StatusSeeOther)
 	}
 }
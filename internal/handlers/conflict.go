@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// RecordConflicts agrupa los repositorios con los que se explica un
+// conflicto de edición: qué guardó otra persona mientras se editaba el
+// record. Se comparte entre los handlers que editan records.
+type RecordConflicts struct {
+	Records   *repository.RecordRepository
+	Versions  *repository.RecordVersionRepository
+	Locations *repository.LocationRepository
+}
+
+// recordETag retorna el ETag de un record, que cambia con cada escritura
+func recordETag(record *models.Record) string {
+	return fmt.Sprintf(`"%d"`, record.Version)
+}
+
+// expectedVersion retorna la versión del record sobre la que se hizo una
+// edición, tomada del encabezado If-Match o del campo version del
+// formulario. fromHeader indica si vino de If-Match, y un valor inválido
+// retorna -1 para que la edición se rechace. Sin ninguno de los dos no hay
+// con qué detectar una edición simultánea: responde 428 y retorna ok false.
+func expectedVersion(w http.ResponseWriter, r *http.Request, record *models.Record) (version int, fromHeader, ok bool) {
+	if match := strings.TrimSpace(r.Header.Get("If-Match")); match != "" {
+		if match == "*" {
+			return record.Version, true, true
+		}
+		return parseVersion(strings.Trim(strings.TrimPrefix(match, "W/"), `"`)), true, true
+	}
+	if value := r.FormValue("version"); value != "" {
+		return parseVersion(value), false, true
+	}
+	http.Error(w, "Falta la versión del record: envía el campo version o el encabezado If-Match", http.StatusPreconditionRequired)
+	return 0, false, false
+}
+
+// parseVersion interpreta un número de versión, o retorna -1 si no es válido
+func parseVersion(value string) int {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return -1
+	}
+	return version
+}
+
+// Write responde a una edición rechazada porque el record cambió desde la
+// versión expected. Muestra los cambios que se guardaron entretanto y los
+// que no se guardaron de attempted, con la opción de reenviar el formulario
+// sobre la versión actual. extra son cambios intentados que no son campos
+// del record, como la fusión en otro. Responde 412 si la versión vino de
+// If-Match y 409 si vino del formulario.
+func (c RecordConflicts) Write(w http.ResponseWriter, r *http.Request, attempted *models.Record, expected int, fromHeader bool, extra ...models.FieldChange) {
+	current, err := c.Records.GetByID(attempted.ID)
+	if err != nil {
+		http.Error(w, "Record no encontrado", http.StatusNotFound)
+		return
+	}
+
+	versions, err := c.Versions.GetSince(current.ID, expected)
+	if err != nil {
+		http.Error(w, "Error obteniendo historial", http.StatusInternalServerError)
+		return
+	}
+
+	locations, err := locationIndex(c.Locations)
+	if err != nil {
+		http.Error(w, "Error obteniendo ubicaciones", http.StatusInternalServerError)
+		return
+	}
+
+	view := templates.RecordConflictView{
+		Record:    current,
+		Versions:  versions,
+		Changes:   append(models.DiffHistory(current, attempted), extra...),
+		Locations: locations,
+		Action:    r.URL.Path,
+	}
+	// Los formularios con archivos no se pueden reenviar sin volver a elegirlos
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		view.Form = r.PostForm
+	}
+
+	status := http.StatusConflict
+	if fromHeader {
+		status = http.StatusPreconditionFailed
+	}
+	w.Header().Set("ETag", recordETag(current))
+	templ.Handler(templates.AdminRecordConflict(view), templ.WithStatus(status)).ServeHTTP(w, r)
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// newTestDB crea una base de datos en memoria con todas las migraciones
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("error abriendo BD: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob("../../migrations/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no se encontraron migraciones: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("error leyendo %s: %v", file, err)
+		}
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("error aplicando %s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// newConflictServer crea un router con las rutas de edición del detalle del
+// admin y un record en su versión 1
+func newConflictServer(t *testing.T) (http.Handler, *repository.RecordRepository, *models.Record) {
+	t.Helper()
+	db := newTestDB(t)
+	records := repository.NewRecordRepository(db)
+	locations := repository.NewLocationRepository(db)
+	detail := RecordDetailSources{
+		Locations:   locations,
+		Loans:       repository.NewLoanRepository(db),
+		Plays:       repository.NewPlayRepository(db),
+		Tags:        repository.NewTagRepository(db),
+		Fields:      repository.NewCustomFieldRepository(db),
+		Identifiers: repository.NewIdentifierRepository(db),
+		Metadata:    repository.NewMetadataSyncRepository(db),
+		Images:      repository.NewRecordImageRepository(db),
+	}
+	conflicts := RecordConflicts{
+		Records:   records,
+		Versions:  repository.NewRecordVersionRepository(db),
+		Locations: locations,
+	}
+	admin := NewAdminHandler(records, detail, conflicts, nil, nil)
+
+	r := chi.NewRouter()
+	r.Get("/admin/records/{id}", admin.DetailHandler())
+	r.Post("/admin/records/{id}/rating", admin.RateHandler())
+
+	record := models.NewRecord()
+	record.Artista = "Nirvana"
+	record.Titulo = "Nevermind"
+	if err := records.Create(record); err != nil {
+		t.Fatalf("error creando record: %v", err)
+	}
+	return r, records, record
+}
+
+// postRating envía el formulario de calificación con el If-Match indicado
+func postRating(handler http.Handler, recordID string, form url.Values, ifMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/admin/records/"+recordID+"/rating", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDetailETag(t *testing.T) {
+	handler, _, record := newConflictServer(t)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/records/"+record.ID, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("código = %d, se esperaba 200", rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got != `"1"` {
+		t.Errorf("ETag = %s, se esperaba \"1\"", got)
+	}
+}
+
+func TestEditVersionChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		form     url.Values
+		ifMatch  string
+		wantCode int
+		wantETag string // ETag de la respuesta, con la versión actual
+		saved    bool
+	}{
+		{name: "versión actual en el formulario", form: url.Values{"version": {"2"}}, wantCode: http.StatusSeeOther, saved: true},
+		{name: "versión actual en If-Match", ifMatch: `"2"`, wantCode: http.StatusSeeOther, saved: true},
+		{name: "If-Match comodín", ifMatch: "*", wantCode: http.StatusSeeOther, saved: true},
+		{name: "formulario desactualizado", form: url.Values{"version": {"1"}}, wantCode: http.StatusConflict, wantETag: `"2"`},
+		{name: "If-Match desactualizado", ifMatch: `"1"`, wantCode: http.StatusPreconditionFailed, wantETag: `"2"`},
+		{name: "If-Match tiene prioridad sobre el formulario", form: url.Values{"version": {"2"}}, ifMatch: `W/"1"`, wantCode: http.StatusPreconditionFailed, wantETag: `"2"`},
+		{name: "versión inválida", form: url.Values{"version": {"abc"}}, wantCode: http.StatusConflict, wantETag: `"2"`},
+		{name: "sin versión", wantCode: http.StatusPreconditionRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, records, record := newConflictServer(t)
+
+			// Otra persona califica el record, que pasa a la versión 2
+			first := postRating(handler, record.ID, url.Values{"version": {"1"}, "rating": {"3"}}, "")
+			if first.Code != http.StatusSeeOther {
+				t.Fatalf("primera edición: código %d", first.Code)
+			}
+
+			form := url.Values{"rating": {"5"}, "review": {"Un clásico"}}
+			for key, values := range tt.form {
+				form[key] = values
+			}
+			rec := postRating(handler, record.ID, form, tt.ifMatch)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("código = %d, se esperaba %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, se esperaba %q", got, tt.wantETag)
+			}
+			if tt.wantCode == http.StatusConflict || tt.wantCode == http.StatusPreconditionFailed {
+				// La página del conflicto muestra lo que no se guardó
+				if body := rec.Body.String(); !strings.Contains(body, "Un clásico") {
+					t.Error("la respuesta no muestra la reseña que no se guardó")
+				}
+			}
+
+			got, err := records.GetByID(record.ID)
+			if err != nil {
+				t.Fatalf("error obteniendo record: %v", err)
+			}
+			if saved := got.Rating.Float64 == 5; saved != tt.saved {
+				t.Errorf("calificación = %v, guardada = %v, se esperaba %v", got.Rating.Float64, saved, tt.saved)
+			}
+			wantVersion := 2
+			if tt.saved {
+				wantVersion = 3
+			}
+			if got.Version != wantVersion {
+				t.Errorf("versión = %d, se esperaba %d", got.Version, wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// CratesHandler maneja los crates inteligentes: selecciones de records
+// definidas por un filtro guardado ("LPs anteriores a 1980 con tag jazz")
+// que se recalculan en cada visita.
+type CratesHandler struct {
+	repo    *repository.SmartCrateRepository
+	records *repository.RecordRepository
+	tags    *repository.TagRepository
+}
+
+// NewCratesHandler crea un nuevo handler de crates inteligentes
+// Parámetros:
+//   - repo: Repositorio de crates inteligentes
+//   - records: Repositorio de records, usado para evaluar los filtros
+//   - tags: Repositorio de tags, para sugerirlos al crear crates
+//
+// Retorna: Una instancia configurada de CratesHandler
+func NewCratesHandler(repo *repository.SmartCrateRepository, records *repository.RecordRepository, tags *repository.TagRepository) *CratesHandler {
+	return &CratesHandler{repo: repo, records: records, tags: tags}
+}
+
+// withCounts calcula cuántos records contiene cada crate. Los crates con
+// un filtro que ya no es válido quedan con 0 records.
+func (h *CratesHandler) withCounts(crates []*models.SmartCrate) error {
+	for _, crate := range crates {
+		filter, err := crate.RecordFilter()
+		if err != nil {
+			continue
+		}
+		count, err := h.records.CountList(filter)
+		if err != nil {
+			return err
+		}
+		crate.RecordCount = count
+	}
+	return nil
+}
+
+// ListHandler maneja el listado público de crates
+//
+// Endpoint: GET /crates
+//
+// Respuestas:
+//   - 200: Crates renderizados correctamente
+//   - 500: Error interno del servidor
+//
+// Vista: templates.CratesList
+func (h *CratesHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		crates, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo crates", http.StatusInternalServerError)
+			return
+		}
+
+		if err := h.withCounts(crates); err != nil {
+			http.Error(w, "Error contando records", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.CratesList(crates)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// DetailHandler maneja la vista de los records de un crate
+//
+// Endpoint: GET /crates/{id}
+//
+// Funcionalidad:
+// - Evalúa el filtro guardado del crate sobre la colección actual
+// - Pagina los resultados con 12 registros por página
+//
+// Parámetros de Query:
+//   - page: Número de página (opcional, default: 1)
+//
+// Respuestas:
+//   - 200: Crate renderizado correctamente
+//   - 404: Crate no encontrado
+//   - 500: Filtro inválido o error interno del servidor
+//
+// Vista: templates.CrateDetail
+func (h *CratesHandler) DetailHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		crate, err := h.repo.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Crate no encontrado", http.StatusNotFound)
+			return
+		}
+
+		page := 1
+		if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+			page = p
+		}
+
+		limit := 12
+		offset := (page - 1) * limit
+
+		filter, err := crate.RecordFilter()
+		if err != nil {
+			http.Error(w, "El filtro del crate es inválido: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		records, err := h.records.List(filter, limit, offset)
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
+		}
+
+		total, err := h.records.CountList(filter)
+		if err != nil {
+			http.Error(w, "Error contando records", http.StatusInternalServerError)
+			return
+		}
+		crate.RecordCount = total
+
+		component := templates.CrateDetail(crate, records, page)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// AdminListHandler maneja la gestión de crates y tags
+//
+// Endpoint: GET /admin/crates
+//
+// Funcionalidad:
+// - Lista los crates con su filtro y cantidad de records
+// - Lista los tags existentes con su uso
+// - Incluye el formulario para crear nuevos crates
+//
+// Respuestas:
+//   - 200: Vista renderizada correctamente
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminCrates
+func (h *CratesHandler) AdminListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		crates, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo crates", http.StatusInternalServerError)
+			return
+		}
+
+		if err := h.withCounts(crates); err != nil {
+			http.Error(w, "Error contando records", http.StatusInternalServerError)
+			return
+		}
+
+		tags, err := h.tags.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo tags", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.AdminCrates(crates, tags)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// CreateHandler maneja la creación de crates inteligentes
+//
+// Endpoint: POST /admin/crates
+//
+// Parámetros del Formulario:
+//   - nombre: Nombre del crate (requerido)
+//   - filtro: Expresión de filtro, p. ej. formato=LP and anio<1980 and tag=jazz (requerido)
+//   - descripcion: Descripción libre (opcional)
+//
+// Respuestas:
+//   - 303: Redirección a /admin/crates después de crear
+//   - 400: Datos inválidos o expresión de filtro mal formada
+//   - 500: Error interno del servidor
+func (h *CratesHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		nombre := strings.TrimSpace(r.FormValue("nombre"))
+		filtro := strings.TrimSpace(r.FormValue("filtro"))
+		descripcion := strings.TrimSpace(r.FormValue("descripcion"))
+
+		if nombre == "" || filtro == "" {
+			http.Error(w, "Nombre y filtro son requeridos", http.StatusBadRequest)
+			return
+		}
+
+		conditions, err := models.ParseFilterExpression(filtro)
+		if err != nil {
+			http.Error(w, "Filtro inválido: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		crate := models.NewSmartCrate()
+		crate.Nombre = nombre
+		crate.Filtro = models.FormatFilterExpression(conditions)
+		crate.Descripcion = sql.NullString{String: descripcion, Valid: descripcion != ""}
+
+		if err := h.repo.Create(crate); err != nil {
+			http.Error(w, "Error creando crate", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/crates", http.StatusSeeOther)
+	}
+}
+
+// DeleteHandler maneja la eliminación de un crate inteligente
+//
+// Endpoint: POST /admin/crates/{id}/delete
+//
+// Respuestas:
+//   - 303: Redirección a /admin/crates
+//   - 404: Crate no encontrado
+func (h *CratesHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.repo.Delete(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, "Crate no encontrado", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/crates", http.StatusSeeOther)
+	}
+}
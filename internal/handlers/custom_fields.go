@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// CustomFieldsHandler maneja los campos personalizados: su definición
+// desde el panel administrativo y los valores de cada record
+type CustomFieldsHandler struct {
+	repo      *repository.CustomFieldRepository
+	records   *repository.RecordRepository
+	conflicts RecordConflicts
+}
+
+// NewCustomFieldsHandler crea un nuevo handler de campos personalizados
+// Parámetros:
+//   - repo: Repositorio de campos personalizados
+//   - records: Repositorio de records, donde se guardan los valores
+//   - conflicts: Repositorios para explicar las ediciones simultáneas de un record
+//
+// Retorna: Una instancia configurada de CustomFieldsHandler
+func NewCustomFieldsHandler(repo *repository.CustomFieldRepository, records *repository.RecordRepository, conflicts RecordConflicts) *CustomFieldsHandler {
+	return &CustomFieldsHandler{repo: repo, records: records, conflicts: conflicts}
+}
+
+// ListHandler maneja el listado de campos personalizados
+//
+// Endpoint: GET /admin/fields
+//
+// Respuestas:
+//   - 200: Listado renderizado correctamente
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminCustomFields
+func (h *CustomFieldsHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fields, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo campos personalizados", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.AdminCustomFields(fields)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// CreateHandler maneja la creación de campos personalizados
+//
+// Endpoint: POST /admin/fields
+//
+// Parámetros del Formulario:
+//   - nombre: Nombre visible del campo (requerido)
+//   - tipo: text, number, bool, date o enum (requerido)
+//   - opciones: Opciones separadas por comas (requerido para enum)
+//
+// Validaciones:
+// - La clave derivada del nombre no puede estar en uso
+//
+// Respuestas:
+//   - 303: Redirección a /admin/fields después de crear
+//   - 400: Datos inválidos
+//   - 409: Ya existe un campo con la misma clave
+func (h *CustomFieldsHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		tipo := r.FormValue("tipo")
+		if !models.IsValidCustomFieldType(tipo) {
+			http.Error(w, "Tipo de campo inválido", http.StatusBadRequest)
+			return
+		}
+
+		field := models.NewCustomField(r.FormValue("nombre"), tipo)
+		if field.Clave == "" {
+			http.Error(w, "El nombre es requerido", http.StatusBadRequest)
+			return
+		}
+
+		if tipo == models.CustomFieldEnum {
+			opciones := splitCommaList(r.FormValue("opciones"))
+			if len(opciones) == 0 {
+				http.Error(w, "Una lista de opciones necesita al menos una opción", http.StatusBadRequest)
+				return
+			}
+			field.SetOpciones(opciones)
+		}
+
+		if err := h.repo.Create(field); err != nil {
+			if strings.Contains(err.Error(), "UNIQUE") {
+				http.Error(w, "Ya existe un campo con la clave "+field.Clave, http.StatusConflict)
+				return
+			}
+			http.Error(w, "Error creando campo personalizado", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/fields", http.StatusSeeOther)
+	}
+}
+
+// DeleteHandler maneja la eliminación de un campo personalizado
+//
+// Endpoint: POST /admin/fields/{id}/delete
+//
+// Funcionalidad:
+// - Elimina la definición del campo y su valor en todos los records
+//
+// Respuestas:
+//   - 303: Redirección a /admin/fields
+//   - 404: Campo no encontrado
+func (h *CustomFieldsHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.repo.WithActor(requestActor(r)).Delete(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, "Campo personalizado no encontrado", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/fields", http.StatusSeeOther)
+	}
+}
+
+// UpdateRecordHandler maneja los valores de los campos personalizados de un record
+//
+// Endpoint: POST /admin/records/{id}/fields
+//
+// Parámetros del Formulario:
+//   - cf_<clave>: Valor de cada campo personalizado; vacío lo elimina
+//   - version: Versión del record que se editó (requerido, o como If-Match)
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Algún valor no corresponde al tipo del campo
+//   - 404: Record no encontrado
+//   - 409: El record cambió desde la versión del formulario
+//   - 412: El record cambió desde la versión de If-Match
+//   - 428: No se indicó la versión del record
+//   - 500: Error interno del servidor
+func (h *CustomFieldsHandler) UpdateRecordHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		record, err := h.records.GetByID(recordID)
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		expected, fromHeader, ok := expectedVersion(w, r, record)
+		if !ok {
+			return
+		}
+
+		fields, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo campos personalizados", http.StatusInternalServerError)
+			return
+		}
+
+		campos, err := parseCustomFields(r, fields)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		record.SetCampos(campos)
+
+		if err := h.records.WithActor(requestActor(r)).UpdateCampos(record.ID, expected, record.Campos); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				h.conflicts.Write(w, r, record, expected, fromHeader)
+				return
+			}
+			http.Error(w, "Error guardando campos personalizados", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
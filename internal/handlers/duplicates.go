@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// DuplicatesHandler maneja la búsqueda de records duplicados, su revisión
+// lado a lado y la fusión en un solo record
+type DuplicatesHandler struct {
+	repo        *repository.DuplicateRepository
+	records     *repository.RecordRepository
+	identifiers *repository.IdentifierRepository
+	conflicts   RecordConflicts
+}
+
+// NewDuplicatesHandler crea un nuevo handler de duplicados
+// Parámetros:
+//   - repo: Repositorio de duplicados, que busca candidatos y fusiona records
+//   - records: Repositorio de records
+//   - identifiers: Repositorio de identificadores, mostrados al comparar dos records
+//   - conflicts: Explica las fusiones rechazadas porque uno de los records cambió
+//
+// Retorna: Una instancia configurada de DuplicatesHandler
+func NewDuplicatesHandler(repo *repository.DuplicateRepository, records *repository.RecordRepository, identifiers *repository.IdentifierRepository, conflicts RecordConflicts) *DuplicatesHandler {
+	return &DuplicatesHandler{repo: repo, records: records, identifiers: identifiers, conflicts: conflicts}
+}
+
+// ListHandler maneja la lista de posibles duplicados
+//
+// Endpoint: GET /admin/duplicates
+//
+// Funcionalidad:
+// - Compara los records por artista, título, número de catálogo, código de barras e IDs de Discogs/MusicBrainz normalizados
+// - Lista los pares que alcanzan el umbral, de mayor a menor puntaje
+// - Omite los pares marcados como distintos
+//
+// Respuestas:
+//   - 200: Lista de posibles duplicados
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminDuplicates
+func (h *DuplicatesHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		candidates, err := h.repo.GetCandidates()
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
+		}
+
+		dismissed, err := h.repo.GetDismissed()
+		if err != nil {
+			http.Error(w, "Error obteniendo pares descartados", http.StatusInternalServerError)
+			return
+		}
+
+		var pairs []*models.DuplicatePair
+		for _, pair := range models.FindDuplicatePairs(candidates) {
+			if !dismissed[models.DuplicatePairKey(pair.A.ID, pair.B.ID)] {
+				pairs = append(pairs, pair)
+			}
+		}
+
+		view := templates.DuplicatesView{
+			Pairs:     pairs,
+			Records:   len(candidates),
+			Dismissed: len(dismissed),
+		}
+		templ.Handler(templates.AdminDuplicates(view)).ServeHTTP(w, r)
+	}
+}
+
+// ReviewHandler maneja la comparación lado a lado de dos records
+//
+// Endpoint: GET /admin/duplicates/{a}/{b}
+//
+// Funcionalidad:
+// - Muestra los campos de ambos records, destacando los que difieren
+// - Propone, para cada campo, el valor de A salvo que esté vacío y B lo tenga
+// - Cuenta las escuchas, préstamos, imágenes, identificadores, tags y playlists que se traspasarán
+//
+// Respuestas:
+//   - 200: Comparación de los dos records
+//   - 400: Se comparó un record consigo mismo
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminDuplicateReview
+func (h *DuplicatesHandler) ReviewHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, b, ok := h.pair(w, r)
+		if !ok {
+			return
+		}
+
+		view := templates.DuplicateReviewView{A: a, B: b, Rows: models.DiffRecords(a, b)}
+		var err error
+		if view.RelatedA, err = h.repo.CountRelated(a.ID); err != nil {
+			http.Error(w, "Error contando datos del record", http.StatusInternalServerError)
+			return
+		}
+		if view.RelatedB, err = h.repo.CountRelated(b.ID); err != nil {
+			http.Error(w, "Error contando datos del record", http.StatusInternalServerError)
+			return
+		}
+		if view.IdentifiersA, err = h.identifiers.GetByRecord(a.ID); err != nil {
+			http.Error(w, "Error obteniendo identificadores", http.StatusInternalServerError)
+			return
+		}
+		if view.IdentifiersB, err = h.identifiers.GetByRecord(b.ID); err != nil {
+			http.Error(w, "Error obteniendo identificadores", http.StatusInternalServerError)
+			return
+		}
+		view.Score, view.Reasons = models.ScoreDuplicate(
+			models.DuplicateCandidate{Record: a, Identifiers: view.IdentifiersA},
+			models.DuplicateCandidate{Record: b, Identifiers: view.IdentifiersB},
+		)
+
+		templ.Handler(templates.AdminDuplicateReview(view)).ServeHTTP(w, r)
+	}
+}
+
+// MergeHandler maneja la fusión de dos records duplicados
+//
+// Endpoint: POST /admin/duplicates/{a}/{b}/merge
+//
+// Parámetros del Formulario:
+//   - conservar: "a" o "b", el record que se conserva con su ID (requerido)
+//   - campo_<campo>: "a" o "b", de qué record se toma cada campo (por defecto, del que se conserva)
+//   - version_a, version_b: Versión de cada record al compararlos (requeridos)
+//
+// Comportamiento:
+// - Copia los campos elegidos y completa la ubicación, calificación, reseña y campos personalizados vacíos
+// - Traspasa escuchas, préstamos, imágenes, identificadores, tags y playlists del otro record
+// - Envía el otro record a la papelera, todo en una sola transacción
+// - Si alguno de los dos cambió desde que se compararon, no fusiona nada y
+//   muestra lo que cambió, con la opción de fusionar igual
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record conservado
+//   - 400: Parámetros inválidos
+//   - 404: Record no encontrado
+//   - 409: Uno de los records cambió desde que se compararon
+//   - 428: Falta la versión de alguno de los records
+//   - 500: Error interno del servidor
+func (h *DuplicatesHandler) MergeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, b, ok := h.pair(w, r)
+		if !ok {
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		var keep, other *models.Record
+		var keepSide, otherSide string
+		switch r.FormValue("conservar") {
+		case "a":
+			keep, other, keepSide, otherSide = a, b, "a", "b"
+		case "b":
+			keep, other, keepSide, otherSide = b, a, "b", "a"
+		default:
+			http.Error(w, "Elige el record que se conserva", http.StatusBadRequest)
+			return
+		}
+
+		if r.FormValue("version_a") == "" || r.FormValue("version_b") == "" {
+			http.Error(w, "Falta la versión de los records; vuelve a compararlos", http.StatusPreconditionRequired)
+			return
+		}
+		keepVersion := parseVersion(r.FormValue("version_" + keepSide))
+		otherVersion := parseVersion(r.FormValue("version_" + otherSide))
+
+		var fromOther []string
+		for _, campo := range models.MergeFields {
+			switch side := r.FormValue("campo_" + campo); side {
+			case "", keepSide:
+			case "a", "b":
+				fromOther = append(fromOther, campo)
+			default:
+				http.Error(w, "Opción inválida para "+models.MergeFieldLabel(campo), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if err := models.MergeRecords(keep, other, fromOther); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		keep.UpdatedAt = time.Now()
+		keep.Version = keepVersion
+
+		err := h.repo.Merge(keep, other.ID, otherVersion, requestActor(r))
+		if errors.Is(err, repository.ErrConflict) {
+			h.mergeConflict(w, r, keep, keepSide, keepVersion, other, otherSide, otherVersion)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Error fusionando records", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+keep.ID, http.StatusSeeOther)
+	}
+}
+
+// mergeConflict responde a una fusión rechazada con el conflicto del record
+// que cambió: para keep, los campos que la fusión iba a guardar; para
+// other, su envío a la papelera. El formulario reenviado lleva la versión
+// actual de ese record, de modo que si cambiaron los dos el siguiente envío
+// muestra el conflicto del otro.
+func (h *DuplicatesHandler) mergeConflict(w http.ResponseWriter, r *http.Request, keep *models.Record, keepSide string, keepVersion int, other *models.Record, otherSide string, otherVersion int) {
+	current, err := h.records.GetByID(keep.ID)
+	if err != nil {
+		http.Error(w, "Record no encontrado", http.StatusNotFound)
+		return
+	}
+	if current.Version != keepVersion {
+		r.PostForm.Set("version_"+keepSide, strconv.Itoa(current.Version))
+		h.conflicts.Write(w, r, keep, keepVersion, false)
+		return
+	}
+
+	current, err = h.records.GetByID(other.ID)
+	if err != nil {
+		http.Error(w, "Record no encontrado", http.StatusNotFound)
+		return
+	}
+	r.PostForm.Set("version_"+otherSide, strconv.Itoa(current.Version))
+	mergedInto := models.FieldChange{Campo: models.HistoryMergedInto, Despues: keep.ID}
+	h.conflicts.Write(w, r, current, otherVersion, false, mergedInto)
+}
+
+// DismissHandler marca dos records como distintos para que no se vuelvan
+// a proponer como duplicados
+//
+// Endpoint: POST /admin/duplicates/{a}/{b}/dismiss
+//
+// Respuestas:
+//   - 303: Redirección a la lista de posibles duplicados
+//   - 400: Se comparó un record consigo mismo
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+func (h *DuplicatesHandler) DismissHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		a, b, ok := h.pair(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.repo.Dismiss(a.ID, b.ID); err != nil {
+			http.Error(w, "Error descartando par", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/duplicates", http.StatusSeeOther)
+	}
+}
+
+// pair obtiene los dos records de la URL, o responde con el error y
+// retorna false
+func (h *DuplicatesHandler) pair(w http.ResponseWriter, r *http.Request) (*models.Record, *models.Record, bool) {
+	idA, idB := chi.URLParam(r, "a"), chi.URLParam(r, "b")
+	if idA == idB {
+		http.Error(w, "Un record no puede ser duplicado de sí mismo", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	a, err := h.records.GetByID(idA)
+	if err != nil {
+		http.Error(w, "Record no encontrado", http.StatusNotFound)
+		return nil, nil, false
+	}
+	b, err := h.records.GetByID(idB)
+	if err != nil {
+		http.Error(w, "Record no encontrado", http.StatusNotFound)
+		return nil, nil, false
+	}
+	return a, b, true
+}
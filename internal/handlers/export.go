@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/rodrwan/vinilo/internal/exporter"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// ExportHandler maneja la exportación de la colección desde el panel administrativo
+type ExportHandler struct {
+	exporter *exporter.Exporter
+	records  *repository.RecordRepository
+}
+
+// NewExportHandler crea un nuevo handler de exportación
+// Parámetros:
+//   - exp: Exportador que escribe los records en cada formato
+//   - records: Repositorio de records, usado para contar los que coinciden con el filtro
+//
+// Retorna: Una instancia configurada de ExportHandler
+func NewExportHandler(exp *exporter.Exporter, records *repository.RecordRepository) *ExportHandler {
+	return &ExportHandler{exporter: exp, records: records}
+}
+
+// ExportHandler muestra las opciones de exportación o descarga el archivo
+//
+// Endpoint: GET /admin/export
+//
+// Funcionalidad:
+//   - Sin formato, muestra cuántos records coinciden con el filtro y los
+//     formatos disponibles
+//   - Con formato, descarga los records que coinciden con el filtro
+//
+// Parámetros de Query:
+//   - format: csv, ndjson o discogs (opcional)
+//   - search, tag, filter, min_rating, sort: Los mismos filtros del catálogo
+//
+// Respuestas:
+//   - 200: Página de exportación o archivo adjunto
+//   - 400: Formato inválido
+//   - 500: Error interno del servidor
+//
+// Vista: Renderiza el template AdminExport
+func (h *ExportHandler) ExportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := models.ParseRecordFilter(r.URL.Query())
+		filter.Search = strings.TrimSpace(filter.Search)
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			total, err := h.records.CountList(filter)
+			if err != nil {
+				http.Error(w, "Error contando records", http.StatusInternalServerError)
+				return
+			}
+
+			templ.Handler(templates.AdminExport(filter, total)).ServeHTTP(w, r)
+			return
+		}
+
+		if !exporter.IsValidFormat(format) {
+			http.Error(w, "Formato de exportación inválido", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", exporter.ContentType(format))
+		w.Header().Set("Content-Disposition", `attachment; filename="`+exporter.FileName(format, time.Now())+`"`)
+
+		// Una vez iniciada la descarga ya no se puede responder con un error
+		if _, err := h.exporter.Export(w, format, filter); err != nil {
+			log.Printf("❌ Error exportando colección: %v", err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// formString retorna el valor de un campo de texto como NullString,
+// inválido si viene vacío
+func formString(value string) sql.NullString {
+	value = strings.TrimSpace(value)
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// splitCommaList separa un campo "Rock, Jazz, Soul" en sus elementos,
+// descartando los vacíos
+func splitCommaList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseRatingField lee una calificación de un formulario. Un valor vacío
+// es válido y significa "sin calificar"; ok es false si está fuera de rango.
+func parseRatingField(value string) (sql.NullFloat64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return sql.NullFloat64{}, true
+	}
+
+	rating, err := strconv.ParseFloat(value, 64)
+	if err != nil || !models.IsValidRating(rating) {
+		return sql.NullFloat64{}, false
+	}
+	return sql.NullFloat64{Float64: rating, Valid: true}, true
+}
+
+// parseCustomFields lee los valores de los campos personalizados de un
+// formulario, validados según el tipo de cada campo
+func parseCustomFields(r *http.Request, fields []*models.CustomField) (map[string]any, error) {
+	campos := make(map[string]any, len(fields))
+	for _, field := range fields {
+		value, err := field.ParseValue(r.FormValue(field.InputName()))
+		if err != nil {
+			return nil, err
+		}
+		campos[field.Clave] = value
+	}
+	return campos, nil
+}
+
+// trackFieldPattern reconoce los campos del tracklist: tracklist[N][campo]
+var trackFieldPattern = regexp.MustCompile(`^tracklist\[(\d+)\]\[(numero|posicion|titulo|duracion)\]$`)
+
+// parseTracklistForm lee las filas del tracklist del formulario en el orden
+// en que aparecen, descartando las que no tienen título. Las filas sin
+// número se numeran según su posición.
+func parseTracklistForm(r *http.Request) []models.Track {
+	rows := map[int]*models.Track{}
+	for key, values := range r.PostForm {
+		match := trackFieldPattern.FindStringSubmatch(key)
+		if match == nil || len(values) == 0 {
+			continue
+		}
+
+		index, _ := strconv.Atoi(match[1])
+		track, ok := rows[index]
+		if !ok {
+			track = &models.Track{}
+			rows[index] = track
+		}
+
+		value := strings.TrimSpace(values[0])
+		switch match[2] {
+		case "numero":
+			track.Numero, _ = strconv.Atoi(value)
+		case "posicion":
+			track.Posicion = value
+		case "titulo":
+			track.Titulo = value
+		case "duracion":
+			track.Duracion = value
+		}
+	}
+
+	indexes := make([]int, 0, len(rows))
+	for index := range rows {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	var tracklist []models.Track
+	for _, index := range indexes {
+		track := rows[index]
+		if track.Titulo == "" {
+			continue
+		}
+		if track.Numero <= 0 {
+			track.Numero = len(tracklist) + 1
+		}
+		tracklist = append(tracklist, *track)
+	}
+	return tracklist
+}
+
+// parseIdentifierFields lee los identificadores enviados como
+// identificador.<tipo> (p. ej. los que trae un proveedor de metadatos)
+func parseIdentifierFields(r *http.Request, recordID string) ([]*models.Identifier, error) {
+	var identifiers []*models.Identifier
+	for _, tipo := range models.IdentifierTypes {
+		for _, valor := range r.PostForm["identificador."+tipo] {
+			if strings.TrimSpace(valor) == "" {
+				continue
+			}
+			identifier, err := models.NewIdentifier(recordID, tipo, valor)
+			if err != nil {
+				return nil, err
+			}
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	return identifiers, nil
+}
+
+// parseUploadForm parsea un formulario que puede venir como multipart
+// (con archivos) o como campos simples
+func parseUploadForm(r *http.Request) error {
+	err := r.ParseMultipartForm(artwork.MaxSize + 1<<20)
+	if err != nil && err != http.ErrNotMultipart {
+		return err
+	}
+	return nil
+}
+
+// saveArtworkUpload guarda la imagen subida en el campo arte_archivo y
+// retorna su URL local, o vacío si no se subió ninguna
+func saveArtworkUpload(r *http.Request, art *artwork.Artwork) (string, error) {
+	file, _, err := r.FormFile("arte_archivo")
+	if err == http.ErrMissingFile || err == http.ErrNotMultipart {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return art.Save(file)
+}
+
+// setArtworkPalette recalcula los colores del record a partir de su arte.
+// Si no se pueden calcular el record se guarda igual, sin paleta.
+func setArtworkPalette(record *models.Record, art *artwork.Artwork) {
+	palette, err := art.Palette(record.ArteURL.String)
+	if err != nil {
+		log.Printf("⚠️ No se pudo calcular la paleta de %s: %v", record.ID, err)
+	}
+	record.SetPalette(palette)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// HistoryHandler maneja el historial de cambios de un record y la
+// reversión a una versión anterior
+type HistoryHandler struct {
+	records   *repository.RecordRepository
+	versions  *repository.RecordVersionRepository
+	locations *repository.LocationRepository
+}
+
+// NewHistoryHandler crea un nuevo handler de historial
+// Parámetros:
+//   - records: Repositorio de records
+//   - versions: Repositorio del historial de cambios
+//   - locations: Repositorio de ubicaciones, para mostrar sus nombres en los cambios
+//
+// Retorna: Una instancia configurada de HistoryHandler
+func NewHistoryHandler(records *repository.RecordRepository, versions *repository.RecordVersionRepository, locations *repository.LocationRepository) *HistoryHandler {
+	return &HistoryHandler{records: records, versions: versions, locations: locations}
+}
+
+// ListHandler maneja el historial de cambios de un record
+//
+// Endpoint: GET /admin/records/{id}/history
+//
+// Funcionalidad:
+// - Lista las versiones del record, de la más reciente a la más antigua
+// - Muestra quién hizo cada cambio, cuándo y el valor anterior y nuevo de cada campo
+// - Los records en la papelera (p. ej. el descartado en una fusión) también
+//   muestran su historial, sin la opción de revertir
+// - Los records purgados muestran el historial que quedó, con el record tal
+//   como estaba en su última versión
+//
+// Respuestas:
+//   - 200: Historial del record
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminRecordHistory
+func (h *HistoryHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+		versions, err := h.versions.GetByRecord(recordID)
+		if err != nil {
+			http.Error(w, "Error obteniendo historial", http.StatusInternalServerError)
+			return
+		}
+
+		record, err := h.records.GetByIDWithDeleted(recordID)
+		purged := err != nil && len(versions) > 0
+		if purged {
+			// Solo queda el historial: se muestra el record como quedó en su última versión
+			record, err = versions[0].GetRecord()
+		}
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		locations, err := locationIndex(h.locations)
+		if err != nil {
+			http.Error(w, "Error obteniendo ubicaciones", http.StatusInternalServerError)
+			return
+		}
+
+		view := templates.RecordHistoryView{
+			Record:    record,
+			Versions:  versions,
+			Locations: locations,
+			Purged:    purged,
+		}
+		templ.Handler(templates.AdminRecordHistory(view)).ServeHTTP(w, r)
+	}
+}
+
+// RevertHandler devuelve un record a una versión anterior de su historial
+//
+// Endpoint: POST /admin/records/{id}/history/{versionID}/revert
+//
+// Comportamiento:
+// - Copia los campos del record tal como quedaron en esa versión
+// - No cambia las escuchas, préstamos, imágenes, identificadores ni tags
+// - La reversión queda en el historial como una versión nueva
+//
+// Respuestas:
+//   - 303: Redirección al historial del record
+//   - 404: Record o versión no encontrados
+//   - 500: Error interno del servidor
+func (h *HistoryHandler) RevertHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+		version, err := h.versions.GetByID(recordID, chi.URLParam(r, "versionID"))
+		if err != nil {
+			http.Error(w, "Versión no encontrada", http.StatusNotFound)
+			return
+		}
+
+		if _, err := h.records.GetByID(recordID); err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		if err := h.records.WithActor(requestActor(r)).Revert(version); err != nil {
+			http.Error(w, "Error revirtiendo record", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID+"/history", http.StatusSeeOther)
+	}
+}
+
+// locationIndex retorna todas las ubicaciones por ID, para mostrar sus
+// nombres en los cambios del historial
+func locationIndex(repo *repository.LocationRepository) (map[string]*models.Location, error) {
+	locations, err := repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]*models.Location, len(locations))
+	for _, location := range locations {
+		index[location.ID] = location
+	}
+	return index, nil
+}
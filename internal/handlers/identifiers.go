@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// IdentifiersHandler maneja los identificadores de los records (códigos
+// de barras, matrices/runouts e IDs externos) desde el panel administrativo
+type IdentifiersHandler struct {
+	repo    *repository.IdentifierRepository
+	records *repository.RecordRepository
+}
+
+// NewIdentifiersHandler crea un nuevo handler de identificadores
+// Parámetros:
+//   - repo: Repositorio de identificadores
+//   - records: Repositorio de records, usado para validar el record
+//
+// Retorna: Una instancia configurada de IdentifiersHandler
+func NewIdentifiersHandler(repo *repository.IdentifierRepository, records *repository.RecordRepository) *IdentifiersHandler {
+	return &IdentifiersHandler{repo: repo, records: records}
+}
+
+// CreateHandler maneja el registro de un identificador
+//
+// Endpoint: POST /admin/records/{id}/identifiers
+//
+// Parámetros del Formulario:
+//   - tipo: barcode, matrix, discogs o musicbrainz (requerido)
+//   - valor: Valor del identificador (requerido)
+//   - descripcion: Aclaración, p. ej. "Lado A" (opcional)
+//
+// Validaciones:
+// - Los códigos EAN/UPC deben tener 8, 12 o 13 dígitos y dígito de control correcto
+// - Los IDs de Discogs deben ser numéricos y los de MusicBrainz UUIDs
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Identificador inválido
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+func (h *IdentifiersHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.records.GetByID(recordID); err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		identifier, err := models.NewIdentifier(recordID, r.FormValue("tipo"), r.FormValue("valor"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		identifier.Descripcion = formString(r.FormValue("descripcion"))
+
+		if err := h.repo.Create(identifier); err != nil {
+			http.Error(w, "Error guardando identificador", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
+
+// DeleteHandler maneja la eliminación de un identificador
+//
+// Endpoint: POST /admin/records/{id}/identifiers/{identifierID}/delete
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 404: Identificador no encontrado
+func (h *IdentifiersHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := h.repo.Delete(recordID, chi.URLParam(r, "identifierID")); err != nil {
+			http.Error(w, "Identificador no encontrado", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
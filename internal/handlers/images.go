@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// ImagesHandler maneja la galería de imágenes de los records (portada,
+// contraportada, etiquetas, insertos y runout) desde el panel administrativo
+type ImagesHandler struct {
+	repo    *repository.RecordImageRepository
+	records *repository.RecordRepository
+	artwork *artwork.Artwork
+}
+
+// NewImagesHandler crea un nuevo handler de la galería de imágenes
+// Parámetros:
+//   - repo: Repositorio de imágenes de records
+//   - records: Repositorio de records, usado para validar el record y cambiar su arte principal
+//   - art: Almacenamiento del arte, donde se guardan las imágenes subidas
+//
+// Retorna: Una instancia configurada de ImagesHandler
+func NewImagesHandler(repo *repository.RecordImageRepository, records *repository.RecordRepository, art *artwork.Artwork) *ImagesHandler {
+	return &ImagesHandler{repo: repo, records: records, artwork: art}
+}
+
+// CreateHandler maneja la subida de una imagen a la galería
+//
+// Endpoint: POST /admin/records/{id}/images
+//
+// Parámetros del Formulario (multipart):
+//   - arte_archivo: Imagen JPEG, PNG, GIF o WebP de hasta 10 MB (requerido)
+//   - tipo: front, back, label_a, label_b, insert o runout (requerido)
+//   - leyenda: Texto que acompaña a la imagen (opcional)
+//
+// Comportamiento:
+// - Guarda la imagen y sus miniaturas en el almacenamiento local
+// - La agrega al final de la galería
+// - Si el record no tiene arte y la imagen es una portada, pasa a ser su arte principal
+// - No pide la versión del record: solo completa el arte si está vacío y no
+//   pisa ningún campo editado en el formulario
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Sin archivo, imagen inválida o tipo inválido
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+func (h *ImagesHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := h.records.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		if err := parseUploadForm(r); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		if !models.IsValidImageType(r.FormValue("tipo")) {
+			http.Error(w, "Tipo de imagen inválido", http.StatusBadRequest)
+			return
+		}
+
+		url, err := saveArtworkUpload(r, h.artwork)
+		if err != nil {
+			if artwork.IsValidationError(err) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "Error guardando imagen", http.StatusInternalServerError)
+			return
+		}
+		if url == "" {
+			http.Error(w, "Selecciona una imagen", http.StatusBadRequest)
+			return
+		}
+
+		image, err := models.NewRecordImage(record.ID, url, r.FormValue("tipo"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		image.Leyenda = formString(strings.TrimSpace(r.FormValue("leyenda")))
+
+		if err := h.repo.Create(image); err != nil {
+			http.Error(w, "Error guardando imagen", http.StatusInternalServerError)
+			return
+		}
+
+		if image.Tipo == models.ImageFront && (!record.ArteURL.Valid || record.ArteURL.String == "") {
+			record.ArteURL = formString(url)
+			setArtworkPalette(record, h.artwork)
+			record.UpdatedAt = time.Now()
+			if err := h.records.WithActor(requestActor(r)).Update(record); err != nil {
+				http.Error(w, "Error actualizando record", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		http.Redirect(w, r, "/admin/records/"+record.ID, http.StatusSeeOther)
+	}
+}
+
+// UpdateHandler maneja el cambio de tipo y leyenda de una imagen
+//
+// Endpoint: POST /admin/records/{id}/images/{imageID}
+//
+// Parámetros del Formulario:
+//   - tipo: front, back, label_a, label_b, insert o runout (requerido)
+//   - leyenda: Texto que acompaña a la imagen; vacío la quita (opcional)
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Tipo inválido
+//   - 404: Imagen no encontrada
+//   - 500: Error interno del servidor
+func (h *ImagesHandler) UpdateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		image, err := h.repo.GetByID(recordID, chi.URLParam(r, "imageID"))
+		if err != nil {
+			http.Error(w, "Imagen no encontrada", http.StatusNotFound)
+			return
+		}
+
+		if !models.IsValidImageType(r.FormValue("tipo")) {
+			http.Error(w, "Tipo de imagen inválido", http.StatusBadRequest)
+			return
+		}
+		image.Tipo = r.FormValue("tipo")
+		image.Leyenda = formString(strings.TrimSpace(r.FormValue("leyenda")))
+
+		if err := h.repo.Update(image); err != nil {
+			http.Error(w, "Error actualizando imagen", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
+
+// CoverHandler usa una imagen de la galería como arte principal del record,
+// el que se muestra en el catálogo
+//
+// Endpoint: POST /admin/records/{id}/images/{imageID}/cover
+//
+// Comportamiento:
+// - No pide la versión del record: el arte se elige de la galería en un
+//   solo clic y la acción queda en el historial como cualquier edición
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 404: Record o imagen no encontrada
+//   - 500: Error interno del servidor
+func (h *ImagesHandler) CoverHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := h.records.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		image, err := h.repo.GetByID(record.ID, chi.URLParam(r, "imageID"))
+		if err != nil {
+			http.Error(w, "Imagen no encontrada", http.StatusNotFound)
+			return
+		}
+
+		record.ArteURL = formString(image.URL)
+		setArtworkPalette(record, h.artwork)
+		record.UpdatedAt = time.Now()
+		if err := h.records.WithActor(requestActor(r)).Update(record); err != nil {
+			http.Error(w, "Error actualizando record", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+record.ID, http.StatusSeeOther)
+	}
+}
+
+// ReorderHandler maneja el nuevo orden de la galería
+//
+// Endpoint: POST /admin/records/{id}/images/order
+//
+// Parámetros del Formulario:
+//   - image_ids: IDs de las imágenes, en orden (uno o más)
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: No se enviaron imágenes o alguna no pertenece al record
+func (h *ImagesHandler) ReorderHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		ids := r.Form["image_ids"]
+		if len(ids) == 0 {
+			http.Error(w, "No hay imágenes para ordenar", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.repo.Reorder(recordID, ids); err != nil {
+			http.Error(w, "Orden inválido", http.StatusBadRequest)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
+
+// DeleteHandler quita una imagen de la galería
+//
+// Endpoint: POST /admin/records/{id}/images/{imageID}/delete
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 404: Imagen no encontrada
+func (h *ImagesHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := h.repo.Delete(recordID, chi.URLParam(r, "imageID")); err != nil {
+			http.Error(w, "Imagen no encontrada", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
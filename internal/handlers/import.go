@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/importer"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// maxImportSize es el tamaño máximo aceptado para archivos de importación
+const maxImportSize = 10 << 20
+
+// errImportFile indica que no se recibió un archivo legible
+var errImportFile = errors.New("Debe subir un archivo CSV o JSON de hasta 10MB")
+
+// ImportHandler maneja la importación de colecciones desde el panel administrativo
+type ImportHandler struct {
+	importer *importer.Importer
+	profiles *repository.ImportProfileRepository
+	fields   *repository.CustomFieldRepository
+}
+
+// NewImportHandler crea un nuevo handler de importación
+// Parámetros:
+//   - imp: Importador que detecta duplicados y guarda los records
+//   - profiles: Repositorio de perfiles de mapeo de columnas
+//   - fields: Repositorio de campos personalizados, que también se pueden mapear
+//
+// Retorna: Una instancia configurada de ImportHandler
+func NewImportHandler(imp *importer.Importer, profiles *repository.ImportProfileRepository, fields *repository.CustomFieldRepository) *ImportHandler {
+	return &ImportHandler{importer: imp, profiles: profiles, fields: fields}
+}
+
+// PageHandler muestra el formulario de importación
+//
+// Endpoint: GET /admin/import
+//
+// Vista: Renderiza el template AdminImport con los perfiles de mapeo guardados
+func (h *ImportHandler) PageHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profiles, err := h.profiles.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo perfiles de importación", http.StatusInternalServerError)
+			return
+		}
+
+		templ.Handler(templates.AdminImport(nil, "", profiles)).ServeHTTP(w, r)
+	}
+}
+
+// DiscogsHandler importa una exportación de colección de Discogs
+//
+// Endpoint: POST /admin/import/discogs
+//
+// Funcionalidad:
+//   - Lee el CSV subido (o el contenido reenviado desde la vista previa)
+//   - Detecta duplicados por release de Discogs y por artista, título y catálogo
+//   - En modo dry-run solo muestra el reporte; si no, crea los records nuevos
+//     en una sola transacción
+//
+// Parámetros del Formulario:
+//   - file: Archivo CSV exportado desde Discogs
+//   - content: Contenido del CSV, usado al confirmar una vista previa
+//   - dry_run: "on" para generar solo la vista previa
+//
+// Respuestas:
+//   - 200: Reporte de importación
+//   - 400: Archivo ausente o con formato inválido
+//   - 500: Error interno del servidor
+//
+// Vista: Renderiza el template AdminImport con el reporte
+func (h *ImportHandler) DiscogsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+		content, err := readImportFile(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rows, err := importer.ParseDiscogsCSV(bytes.NewReader(content))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		report, err := h.importer.WithActor(requestActor(r)).Run("Discogs", rows, r.FormValue("dry_run") == "on")
+		if err != nil {
+			http.Error(w, "Error importando records", http.StatusInternalServerError)
+			return
+		}
+
+		templ.Handler(templates.AdminImport(report, string(content), nil)).ServeHTTP(w, r)
+	}
+}
+
+// FileHandler importa un CSV o JSON genérico asignando sus columnas a
+// campos del record
+//
+// Endpoint: POST /admin/import/file
+//
+// Funcionalidad:
+//   - Sin mapeo, muestra las columnas del archivo con un campo propuesto
+//     para cada una (según el perfil elegido o el nombre de la columna)
+//   - Con mapeo, valida cada fila y genera la vista previa o, si no es
+//     dry-run, crea todos los records nuevos en una sola transacción. Si
+//     alguna fila tiene errores no se guarda ninguna.
+//   - Opcionalmente guarda el mapeo como perfil reutilizable
+//
+// Parámetros del Formulario:
+//   - file: Archivo CSV (con encabezado) o JSON (arreglo de objetos o NDJSON)
+//   - content: Contenido del archivo, reenviado desde la pantalla de mapeo
+//   - profile: ID del perfil de mapeo a aplicar (opcional)
+//   - mapped: "1" cuando se envía el mapeo
+//   - map_<n>: Campo asignado a la columna n ("" para ignorarla)
+//   - profile_name: Nombre con el que guardar el mapeo como perfil (opcional)
+//   - dry_run: "on" para generar solo la vista previa
+//
+// Respuestas:
+//   - 200: Pantalla de mapeo con el reporte, si se importó
+//   - 400: Archivo ausente, ilegible o mapeo sin título y artista
+//   - 500: Error interno del servidor
+//
+// Vista: Renderiza el template AdminImportMapping
+func (h *ImportHandler) FileHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+		content, err := readImportFile(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		table, err := importer.ReadTable(content, importer.DetectFormat(content))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fields, err := h.fields.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo campos personalizados", http.StatusInternalServerError)
+			return
+		}
+
+		profiles, err := h.profiles.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo perfiles de importación", http.StatusInternalServerError)
+			return
+		}
+
+		view := templates.ImportMappingView{
+			Table:     table,
+			Content:   string(content),
+			Targets:   importer.Targets(fields),
+			Profiles:  profiles,
+			ProfileID: r.FormValue("profile"),
+			DryRun:    true,
+		}
+
+		if r.FormValue("mapped") != "1" {
+			view.Mapping = importer.GuessMapping(table.Columns, fields)
+			if view.ProfileID != "" {
+				profile, err := h.profiles.GetByID(view.ProfileID)
+				if err != nil {
+					http.Error(w, "Perfil de importación no encontrado", http.StatusBadRequest)
+					return
+				}
+				view.Mapping = profile.GetMapeo()
+			}
+			templ.Handler(templates.AdminImportMapping(view)).ServeHTTP(w, r)
+			return
+		}
+
+		view.Mapping = map[string]string{}
+		for i, column := range table.Columns {
+			view.Mapping[column] = r.FormValue("map_" + strconv.Itoa(i))
+		}
+		view.DryRun = r.FormValue("dry_run") == "on"
+
+		rows, err := importer.MapTable(table, view.Mapping, fields)
+		if err != nil {
+			view.Error = err.Error()
+			templ.Handler(templates.AdminImportMapping(view), templ.WithStatus(http.StatusBadRequest)).ServeHTTP(w, r)
+			return
+		}
+
+		if name := strings.TrimSpace(r.FormValue("profile_name")); name != "" {
+			profile := models.NewImportProfile(name, view.Mapping)
+			if err := h.profiles.Save(profile); err != nil {
+				http.Error(w, "Error guardando perfil de importación", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		view.Report, err = h.importer.WithActor(requestActor(r)).RunAll("Archivo "+table.Format, rows, view.DryRun)
+		if err != nil {
+			http.Error(w, "Error importando records", http.StatusInternalServerError)
+			return
+		}
+
+		templ.Handler(templates.AdminImportMapping(view)).ServeHTTP(w, r)
+	}
+}
+
+// DeleteProfileHandler elimina un perfil de mapeo
+//
+// Endpoint: POST /admin/import/profiles/{id}/delete
+//
+// Respuestas:
+//   - 303: Redirección a la página de importación
+//   - 404: Perfil no encontrado
+func (h *ImportHandler) DeleteProfileHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.profiles.Delete(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, "Perfil de importación no encontrado", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/import", http.StatusSeeOther)
+	}
+}
+
+// readImportFile retorna el contenido del archivo subido en el campo file
+// o, si no hay archivo, el texto del campo content
+func readImportFile(r *http.Request) ([]byte, error) {
+	if err := r.ParseMultipartForm(maxImportSize); err != nil && err != http.ErrNotMultipart {
+		return nil, errImportFile
+	}
+
+	file, _, err := r.FormFile("file")
+	if err == nil {
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return nil, errImportFile
+		}
+		return content, nil
+	}
+
+	if content := r.FormValue("content"); content != "" {
+		return []byte(content), nil
+	}
+	return nil, errImportFile
+}
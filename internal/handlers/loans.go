@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// dateLayout es el formato de los inputs type="date" de los formularios
+const dateLayout = "2006-01-02"
+
+// LoansHandler maneja los préstamos de records a otras personas
+// desde el panel administrativo: registrar la salida de un disco y
+// marcar su devolución.
+type LoansHandler struct {
+	repo    *repository.LoanRepository
+	records *repository.RecordRepository
+}
+
+// NewLoansHandler crea un nuevo handler de préstamos
+// Parámetros:
+//   - repo: Repositorio de préstamos
+//   - records: Repositorio de records, usado para validar el disco prestado
+//
+// Retorna: Una instancia configurada de LoansHandler
+func NewLoansHandler(repo *repository.LoanRepository, records *repository.RecordRepository) *LoansHandler {
+	return &LoansHandler{repo: repo, records: records}
+}
+
+// CreateHandler maneja el registro de un nuevo préstamo
+//
+// Endpoint: POST /admin/records/{id}/loans
+//
+// Parámetros del Formulario:
+//   - prestatario: Persona que se lleva el disco (requerido)
+//   - fecha_salida: Fecha del préstamo, YYYY-MM-DD (opcional, default: hoy)
+//   - fecha_vencimiento: Fecha acordada de devolución, YYYY-MM-DD (opcional)
+//   - notas: Notas libres (opcional)
+//
+// Validaciones:
+// - El record debe existir y no estar prestado actualmente
+// - La fecha de vencimiento no puede ser anterior a la de salida
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Datos del formulario inválidos
+//   - 404: Record no encontrado
+//   - 409: El record ya está prestado
+//   - 500: Error interno del servidor
+func (h *LoansHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		prestatario := strings.TrimSpace(r.FormValue("prestatario"))
+		if prestatario == "" {
+			http.Error(w, "El nombre de quien se lleva el disco es requerido", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.records.GetByID(recordID); err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		active, err := h.repo.GetActiveByRecord(recordID)
+		if err != nil {
+			http.Error(w, "Error verificando préstamos", http.StatusInternalServerError)
+			return
+		}
+		if active != nil {
+			http.Error(w, "El record ya está prestado a "+active.Prestatario, http.StatusConflict)
+			return
+		}
+
+		loan := models.NewLoan()
+		loan.RecordID = recordID
+		loan.Prestatario = prestatario
+
+		if v := r.FormValue("fecha_salida"); v != "" {
+			t, err := time.ParseInLocation(dateLayout, v, time.Local)
+			if err != nil {
+				http.Error(w, "Fecha de salida inválida", http.StatusBadRequest)
+				return
+			}
+			loan.FechaSalida = t
+		}
+
+		if v := r.FormValue("fecha_vencimiento"); v != "" {
+			t, err := time.ParseInLocation(dateLayout, v, time.Local)
+			if err != nil {
+				http.Error(w, "Fecha de vencimiento inválida", http.StatusBadRequest)
+				return
+			}
+			// El disco vence al terminar el día acordado
+			t = t.Add(24*time.Hour - time.Second)
+			if t.Before(loan.FechaSalida) {
+				http.Error(w, "La fecha de vencimiento debe ser posterior a la salida", http.StatusBadRequest)
+				return
+			}
+			loan.FechaVencimiento = sql.NullTime{Time: t, Valid: true}
+		}
+
+		if notas := strings.TrimSpace(r.FormValue("notas")); notas != "" {
+			loan.Notas = sql.NullString{String: notas, Valid: true}
+		}
+
+		if err := h.repo.Create(loan); err != nil {
+			http.Error(w, "Error registrando préstamo", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
+
+// ReturnHandler maneja la devolución de un préstamo
+//
+// Endpoint: POST /admin/loans/{id}/return
+//
+// Parámetros del Formulario:
+//   - fecha_devolucion: Fecha de devolución, YYYY-MM-DD (opcional, default: ahora)
+//
+// Validaciones:
+//   - La fecha de devolución no puede ser anterior a la de salida. Si es el
+//     mismo día, la devolución se registra a la hora de salida.
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: Fecha inválida o anterior a la salida
+//   - 404: Préstamo no encontrado o ya devuelto
+func (h *LoansHandler) ReturnHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		loanID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		loan, err := h.repo.GetByID(loanID)
+		if err != nil {
+			http.Error(w, "Préstamo no encontrado", http.StatusNotFound)
+			return
+		}
+
+		returnedAt := time.Now()
+		if v := r.FormValue("fecha_devolucion"); v != "" {
+			t, err := time.ParseInLocation(dateLayout, v, time.Local)
+			if err != nil {
+				http.Error(w, "Fecha de devolución inválida", http.StatusBadRequest)
+				return
+			}
+			if t.Add(24*time.Hour - time.Second).Before(loan.FechaSalida) {
+				http.Error(w, "La fecha de devolución debe ser posterior a la salida", http.StatusBadRequest)
+				return
+			}
+			returnedAt = t
+			if returnedAt.Before(loan.FechaSalida) {
+				returnedAt = loan.FechaSalida
+			}
+		}
+
+		if err := h.repo.MarkReturned(loan.ID, returnedAt); err != nil {
+			http.Error(w, "Préstamo no encontrado o ya devuelto", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+loan.RecordID, http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+func TestReturnDate(t *testing.T) {
+	salida := time.Date(2026, 3, 10, 15, 30, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		fecha    string
+		wantCode int
+		want     time.Time // fecha de devolución registrada
+	}{
+		{name: "día siguiente", fecha: "2026-03-11", wantCode: http.StatusSeeOther, want: time.Date(2026, 3, 11, 0, 0, 0, 0, time.Local)},
+		{name: "mismo día", fecha: "2026-03-10", wantCode: http.StatusSeeOther, want: salida},
+		{name: "antes de la salida", fecha: "2026-03-09", wantCode: http.StatusBadRequest},
+		{name: "fecha inválida", fecha: "10/03/2026", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			records := repository.NewRecordRepository(db)
+			loans := repository.NewLoanRepository(db)
+			handler := NewLoansHandler(loans, records)
+			router := chi.NewRouter()
+			router.Post("/admin/loans/{id}/return", handler.ReturnHandler())
+
+			record := models.NewRecord()
+			record.Artista = "Nirvana"
+			record.Titulo = "Nevermind"
+			if err := records.Create(record); err != nil {
+				t.Fatalf("error creando record: %v", err)
+			}
+			loan := models.NewLoan()
+			loan.RecordID = record.ID
+			loan.Prestatario = "Ana"
+			loan.FechaSalida = salida
+			if err := loans.Create(loan); err != nil {
+				t.Fatalf("error creando préstamo: %v", err)
+			}
+
+			form := url.Values{"fecha_devolucion": {tt.fecha}}
+			req := httptest.NewRequest(http.MethodPost, "/admin/loans/"+loan.ID+"/return", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("código = %d, se esperaba %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+
+			got, err := loans.GetByID(loan.ID)
+			if err != nil {
+				t.Fatalf("error obteniendo préstamo: %v", err)
+			}
+			if got.FechaDevolucion.Valid != !tt.want.IsZero() || !got.FechaDevolucion.Time.Equal(tt.want) {
+				t.Errorf("devolución = %v, se esperaba %v", got.FechaDevolucion, tt.want)
+			}
+		})
+	}
+}
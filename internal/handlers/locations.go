@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// LocationsHandler maneja las ubicaciones físicas de la colección
+// (habitaciones, repisas, cajas y posiciones) y la asignación de
+// records a cada una de ellas desde el panel administrativo.
+type LocationsHandler struct {
+	repo    *repository.LocationRepository
+	records *repository.RecordRepository
+}
+
+// NewLocationsHandler crea un nuevo handler de ubicaciones
+// Parámetros:
+//   - repo: Repositorio de ubicaciones
+//   - records: Repositorio de records, usado para listar y mover discos
+//
+// Retorna: Una instancia configurada de LocationsHandler
+func NewLocationsHandler(repo *repository.LocationRepository, records *repository.RecordRepository) *LocationsHandler {
+	return &LocationsHandler{repo: repo, records: records}
+}
+
+// ListHandler maneja el listado jerárquico de ubicaciones
+//
+// Endpoint: GET /admin/locations
+//
+// Funcionalidad:
+// - Muestra todas las ubicaciones como un árbol indentado
+// - Incluye el formulario para crear nuevas ubicaciones
+//
+// Respuestas:
+//   - 200: Listado renderizado correctamente
+//   - 500: Error interno del servidor al obtener ubicaciones
+//
+// Vista: templates.LocationsList
+func (h *LocationsHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locations, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo ubicaciones", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.LocationsList(models.FlattenLocationTree(locations))
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// CreateHandler maneja la creación de ubicaciones
+//
+// Endpoint: POST /admin/locations
+//
+// Parámetros del Formulario:
+//   - nombre: Nombre de la ubicación (requerido)
+//   - tipo: room, shelf, box o slot (requerido)
+//   - parent_id: Ubicación contenedora (opcional)
+//   - notas: Notas libres (opcional)
+//
+// Respuestas:
+//   - 303: Redirección a /admin/locations después de crear
+//   - 400: Datos del formulario inválidos
+//   - 500: Error interno del servidor al crear la ubicación
+func (h *LocationsHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		location := models.NewLocation()
+		if err := h.applyForm(r, location); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := h.repo.Create(location); err != nil {
+			http.Error(w, "Error creando ubicación", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/locations", http.StatusSeeOther)
+	}
+}
+
+// UpdateHandler maneja la edición de una ubicación
+//
+// Endpoint: POST /admin/locations/{id}
+//
+// Parámetros del Formulario:
+//   - nombre: Nombre de la ubicación (requerido)
+//   - tipo: room, shelf, box o slot (requerido)
+//   - parent_id: Ubicación contenedora (opcional; vacío la deja en la raíz)
+//   - notas: Notas libres (opcional)
+//
+// Comportamiento:
+// - La contenedora no puede ser la misma ubicación ni una de sus
+//   sub-ubicaciones, para que la jerarquía no forme un ciclo
+//
+// Respuestas:
+//   - 303: Redirección al detalle de la ubicación
+//   - 400: Datos del formulario inválidos o contenedora que formaría un ciclo
+//   - 404: Ubicación no encontrada
+//   - 500: Error interno del servidor al guardar la ubicación
+func (h *LocationsHandler) UpdateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		location, err := h.repo.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Ubicación no encontrada", http.StatusNotFound)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.applyForm(r, location); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		location.UpdatedAt = time.Now()
+
+		if err := h.repo.Update(location); err != nil {
+			if errors.Is(err, repository.ErrLocationCycle) {
+				http.Error(w, "La ubicación no puede quedar dentro de sí misma ni de una de sus sub-ubicaciones", http.StatusBadRequest)
+				return
+			}
+			http.Error(w, "Error actualizando ubicación", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/locations/"+location.ID, http.StatusSeeOther)
+	}
+}
+
+// DeleteHandler maneja la eliminación de una ubicación
+//
+// Endpoint: POST /admin/locations/{id}/delete
+//
+// Comportamiento:
+// - Los records guardados en ella quedan sin ubicación, y el cambio queda en
+//   el historial de cada uno
+// - Sus sub-ubicaciones quedan en la raíz, con su contenido
+//
+// Respuestas:
+//   - 303: Redirección a la ubicación contenedora, o al listado si no tenía
+//   - 404: Ubicación no encontrada
+//   - 500: Error interno del servidor al eliminar la ubicación
+func (h *LocationsHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		location, err := h.repo.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Ubicación no encontrada", http.StatusNotFound)
+			return
+		}
+
+		if err := h.repo.WithActor(requestActor(r)).Delete(location.ID); err != nil {
+			http.Error(w, "Error eliminando ubicación", http.StatusInternalServerError)
+			return
+		}
+
+		if location.ParentID.Valid {
+			http.Redirect(w, r, "/admin/locations/"+location.ParentID.String, http.StatusSeeOther)
+			return
+		}
+		http.Redirect(w, r, "/admin/locations", http.StatusSeeOther)
+	}
+}
+
+// applyForm copia en la ubicación los campos del formulario de creación o
+// edición, o retorna el error a mostrar si alguno es inválido
+func (h *LocationsHandler) applyForm(r *http.Request, location *models.Location) error {
+	nombre := strings.TrimSpace(r.FormValue("nombre"))
+	tipo := r.FormValue("tipo")
+	parentID := r.FormValue("parent_id")
+	notas := strings.TrimSpace(r.FormValue("notas"))
+
+	if nombre == "" {
+		return errors.New("El nombre es requerido")
+	}
+
+	if !models.IsValidLocationType(tipo) {
+		return errors.New("Tipo de ubicación inválido")
+	}
+
+	if parentID != "" {
+		if _, err := h.repo.GetByID(parentID); err != nil {
+			return errors.New("Ubicación contenedora no encontrada")
+		}
+	}
+
+	location.Nombre = nombre
+	location.Tipo = tipo
+	location.ParentID = sql.NullString{String: parentID, Valid: parentID != ""}
+	location.Notas = sql.NullString{String: notas, Valid: notas != ""}
+	return nil
+}
+
+// DetailHandler maneja el listado de records de una ubicación
+//
+// Endpoint: GET /admin/locations/{id}
+//
+// Funcionalidad:
+// - Muestra la ruta completa de la ubicación y sus sub-ubicaciones
+// - Lista los records guardados en ella o en cualquiera de sus hijas
+// - Permite buscar records para moverlos a esta ubicación
+// - Incluye el formulario para editar la ubicación
+//
+// Parámetros de Query:
+//   - page: Número de página (opcional, default: 1)
+//   - search: Término para buscar records a mover (opcional)
+//
+// Respuestas:
+//   - 200: Ubicación renderizada correctamente
+//   - 404: Ubicación no encontrada
+//   - 500: Error interno del servidor
+//
+// Vista: templates.LocationDetail
+func (h *LocationsHandler) DetailHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locationID := chi.URLParam(r, "id")
+		searchTerm := strings.TrimSpace(r.URL.Query().Get("search"))
+
+		page := 1
+		if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+			page = p
+		}
+
+		limit := 50
+		offset := (page - 1) * limit
+
+		if _, err := h.repo.GetByID(locationID); err != nil {
+			http.Error(w, "Ubicación no encontrada", http.StatusNotFound)
+			return
+		}
+
+		path, err := h.repo.GetPath(locationID)
+		if err != nil {
+			http.Error(w, "Error obteniendo ubicación", http.StatusInternalServerError)
+			return
+		}
+
+		children, err := h.repo.GetChildren(locationID)
+		if err != nil {
+			http.Error(w, "Error obteniendo sub-ubicaciones", http.StatusInternalServerError)
+			return
+		}
+
+		records, err := h.records.GetByLocation(locationID, limit, offset)
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
+		}
+
+		total, err := h.records.CountByLocation(locationID)
+		if err != nil {
+			http.Error(w, "Error contando records", http.StatusInternalServerError)
+			return
+		}
+
+		var candidates []*models.Record
+		if searchTerm != "" {
+			candidates, err = h.records.Search(searchTerm, limit, 0)
+			if err != nil {
+				http.Error(w, "Error buscando records", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		locations, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo ubicaciones", http.StatusInternalServerError)
+			return
+		}
+		parents := models.WithoutLocationSubtree(models.FlattenLocationTree(locations), locationID)
+
+		component := templates.LocationDetail(path, children, records, total, page, searchTerm, candidates, parents)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// MoveRecordsHandler maneja el movimiento masivo de records a una ubicación
+//
+// Endpoint: POST /admin/locations/{id}/records
+//
+// Funcionalidad:
+// - Asigna la ubicación {id} a todos los records seleccionados
+// - Todos los cambios se aplican en una sola transacción
+// - Los records que no existen o están en la papelera se omiten
+//
+// Parámetros del Formulario:
+//   - record_ids: IDs de los records a mover (uno o más)
+//
+// Respuestas:
+//   - 303: Redirección al detalle de la ubicación
+//   - 400: No se seleccionaron records
+//   - 404: Ubicación no encontrada, o ningún record seleccionado existe
+//   - 500: Error interno del servidor al mover los records
+func (h *LocationsHandler) MoveRecordsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locationID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		ids := r.Form["record_ids"]
+		if len(ids) == 0 {
+			http.Error(w, "Selecciona al menos un record", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.repo.GetByID(locationID); err != nil {
+			http.Error(w, "Ubicación no encontrada", http.StatusNotFound)
+			return
+		}
+
+		moved, missing, err := h.records.WithActor(requestActor(r)).MoveToLocation(ids, locationID)
+		if err != nil {
+			http.Error(w, "Error moviendo records", http.StatusInternalServerError)
+			return
+		}
+		if moved == 0 && missing > 0 {
+			http.Error(w, "Los records seleccionados no existen o están en la papelera", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/admin/locations/%s", locationID), http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/storage"
+)
+
+// MediaHandler sirve los archivos del almacenamiento local (arte subido o
+// copiado de sitios externos)
+//
+// Endpoint: GET /media/*
+//
+// Funcionalidad:
+// - Sirve el archivo guardado con la clave indicada en la ruta
+// - Los nombres derivan del contenido, así que se cachean indefinidamente
+// - Soporta peticiones condicionales y por rangos
+//
+// Respuestas:
+//   - 200: Contenido del archivo
+//   - 404: Archivo no encontrado
+//   - 500: Error interno del servidor
+func MediaHandler(store storage.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := chi.URLParam(r, "*")
+		if !storage.ValidKey(key) {
+			http.NotFound(w, r)
+			return
+		}
+
+		file, err := store.Open(key)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				http.NotFound(w, r)
+				return
+			}
+			http.Error(w, "Error leyendo archivo", http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Content-Type", artwork.ContentType(key))
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		http.ServeContent(w, r, key, time.Time{}, file)
+	}
+}
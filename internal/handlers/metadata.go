@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/internal/resync"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// MetadataHandler maneja la revisión de los cambios de metadatos que
+// propone la re-sincronización periódica con Discogs y MusicBrainz
+type MetadataHandler struct {
+	syncer  *resync.Syncer
+	syncs   *repository.MetadataSyncRepository
+	records *repository.RecordRepository
+}
+
+// NewMetadataHandler crea un nuevo handler de sincronización de metadatos
+// Parámetros:
+//   - syncer: Sincronizador de metadatos en segundo plano
+//   - syncs: Repositorio de sincronizaciones y cambios propuestos
+//   - records: Repositorio de records, usado para mostrar cada cambio
+//
+// Retorna: Una instancia configurada de MetadataHandler
+func NewMetadataHandler(syncer *resync.Syncer, syncs *repository.MetadataSyncRepository, records *repository.RecordRepository) *MetadataHandler {
+	return &MetadataHandler{syncer: syncer, syncs: syncs, records: records}
+}
+
+// ReviewHandler maneja la pantalla de revisión de cambios de metadatos
+//
+// Endpoint: GET /admin/metadata
+//
+// Funcionalidad:
+// - Lista los cambios pendientes agrupados por record, con el valor actual y el propuesto
+// - Muestra el estado de la última sincronización y los records cuyo proveedor falló
+//
+// Respuestas:
+//   - 200: Pantalla de revisión
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminMetadata
+func (h *MetadataHandler) ReviewHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pending, err := h.syncs.GetPending()
+		if err != nil {
+			http.Error(w, "Error obteniendo cambios pendientes", http.StatusInternalServerError)
+			return
+		}
+
+		failed, err := h.syncs.GetFailed(20)
+		if err != nil {
+			http.Error(w, "Error obteniendo sincronizaciones", http.StatusInternalServerError)
+			return
+		}
+
+		var ids []string
+		for _, change := range pending {
+			ids = append(ids, change.RecordID)
+		}
+		for _, sync := range failed {
+			ids = append(ids, sync.RecordID)
+		}
+		records, err := h.records.GetByIDs(ids)
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
+		}
+
+		view := templates.MetadataReviewView{
+			Running:  h.syncer.Running(),
+			Last:     h.syncer.LastResult(),
+			Interval: h.syncer.Interval(),
+			Records:  records,
+			Failed:   failed,
+		}
+		for _, change := range pending {
+			n := len(view.Groups)
+			if n == 0 || view.Groups[n-1].RecordID != change.RecordID {
+				view.Groups = append(view.Groups, templates.MetadataChangeGroup{RecordID: change.RecordID, Provider: change.Provider})
+				n++
+			}
+			view.Groups[n-1].Changes = append(view.Groups[n-1].Changes, change)
+		}
+
+		templ.Handler(templates.AdminMetadata(view)).ServeHTTP(w, r)
+	}
+}
+
+// SyncHandler inicia una sincronización en segundo plano sin esperar el intervalo
+//
+// Endpoint: POST /admin/metadata/sync
+//
+// Respuestas:
+//   - 303: Redirección a la pantalla de revisión
+func (h *MetadataHandler) SyncHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// La sincronización respeta el límite de peticiones de cada
+		// proveedor y puede tardar varios minutos
+		go func() {
+			if _, err := h.syncer.Run(context.Background()); err != nil && !errors.Is(err, resync.ErrRunning) {
+				log.Printf("❌ Error sincronizando metadatos: %v", err)
+			}
+		}()
+
+		http.Redirect(w, r, "/admin/metadata", http.StatusSeeOther)
+	}
+}
+
+// AcceptChangeHandler aplica un cambio propuesto al record
+//
+// Endpoint: POST /admin/metadata/changes/{id}/accept
+//
+// Respuestas:
+//   - 303: Redirección a la pantalla de revisión
+//   - 404: Cambio no encontrado
+//   - 500: Error interno del servidor
+func (h *MetadataHandler) AcceptChangeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		change, err := h.syncs.GetChange(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Cambio no encontrado", http.StatusNotFound)
+			return
+		}
+
+		if err := h.syncer.Accept([]*models.MetadataChange{change}, requestActor(r)); err != nil {
+			http.Error(w, "Error aplicando cambio", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/metadata", http.StatusSeeOther)
+	}
+}
+
+// RejectChangeHandler descarta un cambio propuesto; el mismo valor no se
+// vuelve a proponer en próximas sincronizaciones
+//
+// Endpoint: POST /admin/metadata/changes/{id}/reject
+//
+// Respuestas:
+//   - 303: Redirección a la pantalla de revisión
+//   - 404: Cambio no encontrado
+//   - 500: Error interno del servidor
+func (h *MetadataHandler) RejectChangeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		change, err := h.syncs.GetChange(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Cambio no encontrado", http.StatusNotFound)
+			return
+		}
+
+		if err := h.syncs.Resolve([]string{change.ID}, models.ChangeRejected); err != nil {
+			http.Error(w, "Error descartando cambio", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/metadata", http.StatusSeeOther)
+	}
+}
+
+// AcceptRecordHandler aplica todos los cambios pendientes de un record
+//
+// Endpoint: POST /admin/metadata/records/{id}/accept
+//
+// Respuestas:
+//   - 303: Redirección a la pantalla de revisión
+//   - 500: Error interno del servidor
+func (h *MetadataHandler) AcceptRecordHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		changes, err := h.syncs.GetByRecord(chi.URLParam(r, "id"), models.ChangePending)
+		if err != nil {
+			http.Error(w, "Error obteniendo cambios pendientes", http.StatusInternalServerError)
+			return
+		}
+
+		if err := h.syncer.Accept(changes, requestActor(r)); err != nil {
+			http.Error(w, "Error aplicando cambios", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/metadata", http.StatusSeeOther)
+	}
+}
+
+// RejectRecordHandler descarta todos los cambios pendientes de un record
+//
+// Endpoint: POST /admin/metadata/records/{id}/reject
+//
+// Respuestas:
+//   - 303: Redirección a la pantalla de revisión
+//   - 500: Error interno del servidor
+func (h *MetadataHandler) RejectRecordHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		changes, err := h.syncs.GetByRecord(chi.URLParam(r, "id"), models.ChangePending)
+		if err != nil {
+			http.Error(w, "Error obteniendo cambios pendientes", http.StatusInternalServerError)
+			return
+		}
+
+		ids := make([]string, 0, len(changes))
+		for _, change := range changes {
+			ids = append(ids, change.ID)
+		}
+		if err := h.syncs.Resolve(ids, models.ChangeRejected); err != nil {
+			http.Error(w, "Error descartando cambios", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/metadata", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/mirror"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// MirrorHandler maneja la copia local del arte enlazado desde sitios
+// externos y el reporte de enlaces rotos
+type MirrorHandler struct {
+	mirror  *mirror.Mirror
+	mirrors *repository.ArtworkMirrorRepository
+	records *repository.RecordRepository
+}
+
+// NewMirrorHandler crea un nuevo handler de copia de arte
+// Parámetros:
+//   - m: Copiador de arte en segundo plano
+//   - mirrors: Repositorio de copias de arte
+//   - records: Repositorio de records, usado para mostrar cada enlace roto
+//
+// Retorna: Una instancia configurada de MirrorHandler
+func NewMirrorHandler(m *mirror.Mirror, mirrors *repository.ArtworkMirrorRepository, records *repository.RecordRepository) *MirrorHandler {
+	return &MirrorHandler{mirror: m, mirrors: mirrors, records: records}
+}
+
+// ReportHandler maneja la pantalla de estado de la copia de arte
+//
+// Endpoint: GET /admin/artwork
+//
+// Funcionalidad:
+// - Muestra cuántos records tienen arte externo por copiar y cuántos ya se copiaron
+// - Lista los enlaces rotos que los records todavía usan, con el motivo del error
+//
+// Respuestas:
+//   - 200: Pantalla de copia de arte
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminArtwork
+func (h *MirrorHandler) ReportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pending, err := h.mirrors.CountPending()
+		if err != nil {
+			http.Error(w, "Error obteniendo arte por copiar", http.StatusInternalServerError)
+			return
+		}
+
+		copied, err := h.mirrors.CountCopied()
+		if err != nil {
+			http.Error(w, "Error obteniendo arte copiado", http.StatusInternalServerError)
+			return
+		}
+
+		broken, err := h.mirrors.GetBroken()
+		if err != nil {
+			http.Error(w, "Error obteniendo enlaces rotos", http.StatusInternalServerError)
+			return
+		}
+
+		ids := make([]string, 0, len(broken))
+		for _, mirror := range broken {
+			ids = append(ids, mirror.RecordID)
+		}
+		records, err := h.records.GetByIDs(ids)
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
+		}
+
+		view := templates.ArtworkMirrorView{
+			Running:  h.mirror.Running(),
+			Last:     h.mirror.LastResult(),
+			Interval: h.mirror.Interval(),
+			Pending:  pending,
+			Copied:   copied,
+			Broken:   broken,
+			Records:  records,
+		}
+		templ.Handler(templates.AdminArtwork(view)).ServeHTTP(w, r)
+	}
+}
+
+// RunHandler inicia una copia de arte en segundo plano sin esperar el intervalo
+//
+// Endpoint: POST /admin/artwork/mirror
+//
+// Respuestas:
+//   - 303: Redirección a la pantalla de copia de arte
+func (h *MirrorHandler) RunHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Cada imagen se descarga por separado y la copia puede tardar
+		// varios minutos
+		go func() {
+			if _, err := h.mirror.Run(context.Background()); err != nil && !errors.Is(err, mirror.ErrRunning) {
+				log.Printf("❌ Error copiando arte: %v", err)
+			}
+		}()
+
+		http.Redirect(w, r, "/admin/artwork", http.StatusSeeOther)
+	}
+}
+
+// RetryHandler vuelve a intentar copiar el arte externo de un record, p. ej.
+// después de que el sitio original volvió a estar disponible
+//
+// Endpoint: POST /admin/artwork/{id}/retry
+//
+// Respuestas:
+//   - 303: Redirección a la pantalla de copia de arte
+//   - 400: El record no enlaza su arte desde un sitio externo
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+func (h *MirrorHandler) RetryHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		record, err := h.records.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		if !record.ArteURL.Valid || record.ArteURL.String == "" || artwork.IsLocal(record.ArteURL.String) {
+			http.Error(w, "El record no enlaza su arte desde un sitio externo", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.mirror.Copy(r.Context(), record); err != nil {
+			http.Error(w, "Error copiando arte", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/artwork", http.StatusSeeOther)
+	}
+}
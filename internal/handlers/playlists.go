@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// PlaylistsHandler maneja las playlists: listas ordenadas de records o
+// tracks curadas a mano para sets de DJ y fiestas de escucha. Cada
+// playlist tiene una página pública que se puede compartir.
+type PlaylistsHandler struct {
+	repo    *repository.PlaylistRepository
+	records *repository.RecordRepository
+}
+
+// NewPlaylistsHandler crea un nuevo handler de playlists
+// Parámetros:
+//   - repo: Repositorio de playlists
+//   - records: Repositorio de records, usado para completar y buscar discos
+//
+// Retorna: Una instancia configurada de PlaylistsHandler
+func NewPlaylistsHandler(repo *repository.PlaylistRepository, records *repository.RecordRepository) *PlaylistsHandler {
+	return &PlaylistsHandler{repo: repo, records: records}
+}
+
+// buildView reúne la playlist, sus entradas con sus records y la
+// duración total. Las entradas cuyo record ya no existe se omiten.
+func (h *PlaylistsHandler) buildView(id string) (templates.PlaylistView, error) {
+	playlist, err := h.repo.GetByID(id)
+	if err != nil {
+		return templates.PlaylistView{}, err
+	}
+
+	items, err := h.repo.GetItems(id)
+	if err != nil {
+		return templates.PlaylistView{}, err
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.RecordID
+	}
+
+	records, err := h.records.GetByIDs(ids)
+	if err != nil {
+		return templates.PlaylistView{}, err
+	}
+
+	view := templates.PlaylistView{Playlist: playlist}
+	for _, item := range items {
+		if item.Record = records[item.RecordID]; item.Record != nil {
+			view.Items = append(view.Items, item)
+		}
+	}
+	view.Runtime, view.UnknownDurations = models.PlaylistRuntime(view.Items)
+
+	return view, nil
+}
+
+// PublicHandler maneja la página pública y compartible de una playlist
+//
+// Endpoint: GET /playlists/{id}
+//
+// Funcionalidad:
+// - Muestra las entradas en orden con su duración
+// - Calcula la duración total a partir de los tracklists
+//
+// Respuestas:
+//   - 200: Playlist renderizada correctamente
+//   - 404: Playlist no encontrada
+//
+// Vista: templates.PlaylistPage
+func (h *PlaylistsHandler) PublicHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		view, err := h.buildView(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Playlist no encontrada", http.StatusNotFound)
+			return
+		}
+
+		component := templates.PlaylistPage(view)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// AdminListHandler maneja el listado de playlists
+//
+// Endpoint: GET /admin/playlists
+//
+// Funcionalidad:
+// - Lista las playlists con su cantidad de entradas
+// - Incluye el formulario para crear nuevas playlists
+//
+// Respuestas:
+//   - 200: Listado renderizado correctamente
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminPlaylists
+func (h *PlaylistsHandler) AdminListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlists, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo playlists", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.AdminPlaylists(playlists)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// CreateHandler maneja la creación de playlists
+//
+// Endpoint: POST /admin/playlists
+//
+// Parámetros del Formulario:
+//   - nombre: Nombre de la playlist (requerido)
+//   - descripcion: Descripción libre (opcional)
+//
+// Respuestas:
+//   - 303: Redirección al detalle de la nueva playlist
+//   - 400: Nombre faltante
+//   - 500: Error interno del servidor
+func (h *PlaylistsHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		nombre := strings.TrimSpace(r.FormValue("nombre"))
+		if nombre == "" {
+			http.Error(w, "El nombre es requerido", http.StatusBadRequest)
+			return
+		}
+
+		playlist := models.NewPlaylist()
+		playlist.Nombre = nombre
+		playlist.Descripcion = formString(r.FormValue("descripcion"))
+
+		if err := h.repo.Create(playlist); err != nil {
+			http.Error(w, "Error creando playlist", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/playlists/"+playlist.ID, http.StatusSeeOther)
+	}
+}
+
+// DetailHandler maneja la edición de una playlist
+//
+// Endpoint: GET /admin/playlists/{id}
+//
+// Funcionalidad:
+// - Muestra las entradas con arrastrar y soltar para reordenarlas
+// - Permite buscar records para agregarlos completos o por track
+//
+// Parámetros de Query:
+//   - search: Término para buscar records a agregar (opcional)
+//
+// Respuestas:
+//   - 200: Playlist renderizada correctamente
+//   - 404: Playlist no encontrada
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminPlaylistDetail
+func (h *PlaylistsHandler) DetailHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		searchTerm := strings.TrimSpace(r.URL.Query().Get("search"))
+
+		view, err := h.buildView(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Playlist no encontrada", http.StatusNotFound)
+			return
+		}
+
+		var candidates []*models.Record
+		if searchTerm != "" {
+			candidates, err = h.records.Search(searchTerm, 20, 0)
+			if err != nil {
+				http.Error(w, "Error buscando records", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		component := templates.AdminPlaylistDetail(view, searchTerm, candidates)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// AddItemHandler maneja la incorporación de un record o track a la playlist
+//
+// Endpoint: POST /admin/playlists/{id}/items
+//
+// Parámetros del Formulario:
+//   - record_id: Record a agregar (requerido)
+//   - track: Número de track; vacío agrega el disco completo (opcional)
+//   - notas: Notas de la entrada, p. ej. "mezclar con el siguiente" (opcional)
+//
+// Respuestas:
+//   - 303: Redirección al detalle de la playlist
+//   - 400: Datos inválidos o el track no existe en el record
+//   - 404: Playlist o record no encontrado
+//   - 500: Error interno del servidor
+func (h *PlaylistsHandler) AddItemHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.repo.GetByID(playlistID); err != nil {
+			http.Error(w, "Playlist no encontrada", http.StatusNotFound)
+			return
+		}
+
+		record, err := h.records.GetByID(r.FormValue("record_id"))
+		if err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		item := models.NewPlaylistItem()
+		item.PlaylistID = playlistID
+		item.RecordID = record.ID
+		item.Record = record
+		item.Notas = formString(r.FormValue("notas"))
+
+		if v := r.FormValue("track"); v != "" {
+			numero, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, "Número de track inválido", http.StatusBadRequest)
+				return
+			}
+			item.TrackNumero = sql.NullInt32{Int32: int32(numero), Valid: true}
+			if item.Track() == nil {
+				http.Error(w, "El track no existe en el record", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if err := h.repo.AddItem(item); err != nil {
+			http.Error(w, "Error agregando a la playlist", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/playlists/"+playlistID, http.StatusSeeOther)
+	}
+}
+
+// RemoveItemHandler maneja la eliminación de una entrada de la playlist
+//
+// Endpoint: POST /admin/playlists/{id}/items/{itemID}/delete
+//
+// Respuestas:
+//   - 303: Redirección al detalle de la playlist
+//   - 404: Entrada no encontrada
+func (h *PlaylistsHandler) RemoveItemHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID := chi.URLParam(r, "id")
+
+		if err := h.repo.RemoveItem(playlistID, chi.URLParam(r, "itemID")); err != nil {
+			http.Error(w, "Entrada no encontrada", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/playlists/"+playlistID, http.StatusSeeOther)
+	}
+}
+
+// ReorderHandler maneja el nuevo orden de las entradas
+//
+// Endpoint: POST /admin/playlists/{id}/order
+//
+// Funcionalidad:
+// - Recibe los IDs de las entradas en el orden deseado
+// - Todos los cambios se aplican en una sola transacción
+//
+// Parámetros del Formulario:
+//   - item_ids: IDs de las entradas, en orden (uno o más)
+//
+// Respuestas:
+//   - 303: Redirección al detalle de la playlist
+//   - 400: No se enviaron entradas o alguna no pertenece a la playlist
+func (h *PlaylistsHandler) ReorderHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playlistID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		ids := r.Form["item_ids"]
+		if len(ids) == 0 {
+			http.Error(w, "No hay entradas para ordenar", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.repo.Reorder(playlistID, ids); err != nil {
+			http.Error(w, "Orden inválido", http.StatusBadRequest)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/playlists/"+playlistID, http.StatusSeeOther)
+	}
+}
+
+// DeleteHandler maneja la eliminación de una playlist
+//
+// Endpoint: POST /admin/playlists/{id}/delete
+//
+// Respuestas:
+//   - 303: Redirección a /admin/playlists
+//   - 404: Playlist no encontrada
+func (h *PlaylistsHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.repo.Delete(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, "Playlist no encontrada", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/playlists", http.StatusSeeOther)
+	}
+}
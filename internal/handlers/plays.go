@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// dateTimeLayout es el formato de los inputs type="datetime-local"
+const dateTimeLayout = "2006-01-02T15:04"
+
+// PlaysHandler maneja el registro de escucha: cada vez que se pone un
+// disco en la bandeja y las vistas de más/menos escuchados.
+type PlaysHandler struct {
+	repo    *repository.PlayRepository
+	records *repository.RecordRepository
+}
+
+// NewPlaysHandler crea un nuevo handler del registro de escucha
+// Parámetros:
+//   - repo: Repositorio de sesiones de escucha
+//   - records: Repositorio de records
+//
+// Retorna: Una instancia configurada de PlaysHandler
+func NewPlaysHandler(repo *repository.PlayRepository, records *repository.RecordRepository) *PlaysHandler {
+	return &PlaysHandler{repo: repo, records: records}
+}
+
+// CreateHandler maneja el registro de una sesión de escucha
+//
+// Endpoint: POST /admin/records/{id}/plays
+//
+// Funcionalidad:
+// - Registra que el record se escuchó, por defecto en este momento
+// - Es la acción del botón de play de la vista de detalle
+//
+// Parámetros del Formulario:
+//   - escuchado_en: Fecha y hora, YYYY-MM-DDTHH:MM (opcional, default: ahora)
+//   - lados: Lados escuchados, por ejemplo "A, B" (opcional)
+//   - notas: Notas de la sesión (opcional)
+//   - next: Ruta a la que volver después de registrar (opcional)
+//
+// Respuestas:
+//   - 303: Redirección a next o al detalle administrativo del record
+//   - 400: Fecha inválida
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+func (h *PlaysHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.records.GetByID(recordID); err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		play := models.NewPlay()
+		play.RecordID = recordID
+		play.Lados = formString(r.FormValue("lados"))
+		play.Notas = formString(r.FormValue("notas"))
+
+		if v := r.FormValue("escuchado_en"); v != "" {
+			t, err := time.ParseInLocation(dateTimeLayout, v, time.Local)
+			if err != nil {
+				http.Error(w, "Fecha de escucha inválida", http.StatusBadRequest)
+				return
+			}
+			play.EscuchadoEn = t
+		}
+
+		if err := h.repo.Create(play); err != nil {
+			http.Error(w, "Error registrando escucha", http.StatusInternalServerError)
+			return
+		}
+
+		next := r.FormValue("next")
+		if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") {
+			next = "/admin/records/" + recordID
+		}
+		http.Redirect(w, r, next, http.StatusSeeOther)
+	}
+}
+
+// ListHandler maneja las vistas del registro de escucha
+//
+// Endpoint: GET /admin/plays
+//
+// Funcionalidad:
+// - "most": records ordenados por cantidad de escuchas
+// - "never": records que nunca se han escuchado
+// - "stale": records que no se escuchan hace más de un año
+//
+// Parámetros de Query:
+//   - view: most, never o stale (opcional, default: most)
+//   - page: Número de página (opcional, default: 1)
+//
+// Respuestas:
+//   - 200: Vista renderizada correctamente
+//   - 500: Error interno del servidor
+//
+// Vista: templates.PlaysList
+func (h *PlaysHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		view := r.URL.Query().Get("view")
+
+		page := 1
+		if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+			page = p
+		}
+
+		limit := 50
+		offset := (page - 1) * limit
+
+		var records []*models.Record
+		var err error
+
+		switch view {
+		case models.PlaysViewNeverPlayed:
+			records, err = h.records.GetNeverPlayed(limit, offset)
+		case models.PlaysViewStale:
+			records, err = h.records.GetNotPlayedSince(time.Now().AddDate(-1, 0, 0), limit, offset)
+		default:
+			view = models.PlaysViewMostPlayed
+			records, err = h.records.GetMostPlayed(limit, offset)
+		}
+
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.PlaysList(view, records, page, limit)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
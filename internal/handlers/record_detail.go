@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// RecordDetailSources agrupa los repositorios que aportan información
+// relacionada a la vista de detalle de un record. Se comparte entre el
+// catálogo público y el panel administrativo.
+type RecordDetailSources struct {
+	Locations   *repository.LocationRepository
+	Loans       *repository.LoanRepository
+	Plays       *repository.PlayRepository
+	Tags        *repository.TagRepository
+	Fields      *repository.CustomFieldRepository
+	Identifiers *repository.IdentifierRepository
+	Metadata    *repository.MetadataSyncRepository
+	Images      *repository.RecordImageRepository
+}
+
+// Build reúne el record y sus datos relacionados para la vista de detalle.
+// Con admin en true se incluye la información privada (p. ej. a quién se
+// prestó el disco) y los formularios de gestión.
+func (s RecordDetailSources) Build(record *models.Record, admin bool) (templates.RecordDetailView, error) {
+	view := templates.RecordDetailView{Record: record, Admin: admin}
+
+	if record.LocationID.Valid {
+		path, err := s.Locations.GetPath(record.LocationID.String)
+		if err != nil {
+			return view, err
+		}
+		view.LocationPath = path
+	}
+
+	activeLoan, err := s.Loans.GetActiveByRecord(record.ID)
+	if err != nil {
+		return view, err
+	}
+	view.ActiveLoan = activeLoan
+
+	lastPlay, err := s.Plays.GetLastByRecord(record.ID)
+	if err != nil {
+		return view, err
+	}
+	view.LastPlay = lastPlay
+
+	tags, err := s.Tags.GetByRecord(record.ID)
+	if err != nil {
+		return view, err
+	}
+	view.Tags = tags
+
+	fields, err := s.Fields.GetAll()
+	if err != nil {
+		return view, err
+	}
+	view.CustomFields = fields
+
+	identifiers, err := s.Identifiers.GetByRecord(record.ID)
+	if err != nil {
+		return view, err
+	}
+	view.Identifiers = identifiers
+
+	images, err := s.Images.GetByRecord(record.ID)
+	if err != nil {
+		return view, err
+	}
+	view.Images = images
+
+	if admin {
+		loans, err := s.Loans.GetByRecord(record.ID)
+		if err != nil {
+			return view, err
+		}
+		view.Loans = loans
+
+		plays, err := s.Plays.GetByRecord(record.ID, 10)
+		if err != nil {
+			return view, err
+		}
+		view.Plays = plays
+
+		sync, err := s.Metadata.GetSync(record.ID)
+		if err != nil {
+			return view, err
+		}
+		view.MetadataSync = sync
+
+		pending, err := s.Metadata.GetByRecord(record.ID, models.ChangePending)
+		if err != nil {
+			return view, err
+		}
+		view.PendingChanges = len(pending)
+
+		locations, err := s.Locations.GetAll()
+		if err != nil {
+			return view, err
+		}
+		view.Locations = models.FlattenLocationTree(locations)
+	}
+
+	return view, nil
+}
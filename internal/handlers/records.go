@@ -15,16 +15,18 @@ import (
 // Proporciona endpoints públicos para visualizar la colección de discos
 // de vinilo con funcionalidades de listado, búsqueda y detalle.
 type RecordsHandler struct {
-	repo *repository.RecordRepository
+	repo   *repository.RecordRepository
+	detail RecordDetailSources
 }
 
 // NewRecordsHandler crea un nuevo handler de records
 // Parámetros:
 //   - repo: Repositorio de records para operaciones de base de datos
+//   - detail: Repositorios que completan la vista de detalle
 //
 // Retorna: Una instancia configurada de RecordsHandler
-func NewRecordsHandler(repo *repository.RecordRepository) *RecordsHandler {
-	return &RecordsHandler{repo: repo}
+func NewRecordsHandler(repo *repository.RecordRepository, detail RecordDetailSources) *RecordsHandler {
+	return &RecordsHandler{repo: repo, detail: detail}
 }
 
 // ListHandler maneja el listado de records con búsqueda y paginación
@@ -33,17 +35,21 @@ func NewRecordsHandler(repo *repository.RecordRepository) *RecordsHandler {
 //
 // Funcionalidad:
 // - Muestra una lista paginada de todos los records disponibles
-// - Soporta búsqueda por texto en título y artista
+// - Soporta búsqueda por texto en título, artista, sello, catálogo e identificadores
 // - Implementa paginación con 12 registros por página (optimizado para vista pública)
 // - Permite navegación entre páginas
 //
 // Parámetros de Query:
 //   - page: Número de página (opcional, default: 1)
 //   - search: Término de búsqueda (opcional)
+//   - min_rating: Calificación mínima, de 0.5 a 5 (opcional)
+//   - tag: Muestra solo los records con ese tag (opcional)
+//   - filter: Expresión de filtro, p. ej. formato=LP and anio<1980 (opcional)
+//   - sort: recent, rating, artista, titulo o anio (opcional, default: recent)
 //
 // Comportamiento:
 // - Si se proporciona search, filtra records que contengan el término
-// - Si no hay search, muestra todos los records
+// - Si no hay filtros, muestra todos los records
 // - Calcula automáticamente el total de registros para paginación
 // - Renderiza la vista RecordsList con datos paginados
 // - Utiliza un límite de 12 registros por página (vs 20 en admin)
@@ -62,7 +68,8 @@ func (h *RecordsHandler) ListHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Obtener parámetros de query
 		pageStr := r.URL.Query().Get("page")
-		searchTerm := strings.TrimSpace(r.URL.Query().Get("search"))
+		filter := models.ParseRecordFilter(r.URL.Query())
+		filter.Search = strings.TrimSpace(filter.Search)
 
 		// Parsear página
 		page := 1
@@ -76,26 +83,13 @@ func (h *RecordsHandler) ListHandler() http.HandlerFunc {
 		limit := 12
 		offset := (page - 1) * limit
 
-		var records []*models.Record
-		var total int
-		var err error
-
-		// Buscar o obtener todos
-		if searchTerm != "" {
-			records, err = h.repo.Search(searchTerm, limit, offset)
-			if err != nil {
-				http.Error(w, "Error buscando records", http.StatusInternalServerError)
-				return
-			}
-			total, err = h.repo.CountSearch(searchTerm)
-		} else {
-			records, err = h.repo.GetAll(limit, offset)
-			if err != nil {
-				http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
-				return
-			}
-			total, err = h.repo.Count()
+		// Buscar y filtrar
+		records, err := h.repo.List(filter, limit, offset)
+		if err != nil {
+			http.Error(w, "Error obteniendo records", http.StatusInternalServerError)
+			return
 		}
+		total, err := h.repo.CountList(filter)
 
 		if err != nil {
 			http.Error(w, "Error contando records", http.StatusInternalServerError)
@@ -103,7 +97,7 @@ func (h *RecordsHandler) ListHandler() http.HandlerFunc {
 		}
 
 		// Renderizar la página
-		component := templates.RecordsList(records, total, page, searchTerm)
+		component := templates.RecordsList(records, total, page, filter, nil)
 		templ.Handler(component).ServeHTTP(w, r)
 	}
 }
@@ -157,8 +151,14 @@ func (h *RecordsHandler) DetailHandler() http.HandlerFunc {
 			return
 		}
 
+		view, err := h.detail.Build(record, false)
+		if err != nil {
+			http.Error(w, "Error obteniendo detalle del record", http.StatusInternalServerError)
+			return
+		}
+
 		// Renderizar la página de detalle
-		component := templates.RecordDetail(record)
+		component := templates.RecordDetail(view)
 		templ.Handler(component).ServeHTTP(w, r)
 	}
 }
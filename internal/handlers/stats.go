@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// StatsHandler maneja las estadísticas agregadas de la colección
+type StatsHandler struct {
+	repo *repository.StatsRepository
+}
+
+// NewStatsHandler crea un nuevo handler de estadísticas
+// Parámetros:
+//   - repo: Repositorio de estadísticas
+//
+// Retorna: Una instancia configurada de StatsHandler
+func NewStatsHandler(repo *repository.StatsRepository) *StatsHandler {
+	return &StatsHandler{repo: repo}
+}
+
+// HomeHandler maneja la página de estadísticas
+//
+// Endpoint: GET /admin/stats
+//
+// Funcionalidad:
+// - Calificación promedio por artista, sello y género
+// - Cada tabla muestra los 20 grupos mejor calificados
+//
+// Respuestas:
+//   - 200: Estadísticas renderizadas correctamente
+//   - 500: Error interno del servidor al calcular estadísticas
+//
+// Vista: templates.StatsPage
+func (h *StatsHandler) HomeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		byArtist, err := h.repo.RatingsByArtist(20)
+		if err != nil {
+			http.Error(w, "Error calculando estadísticas por artista", http.StatusInternalServerError)
+			return
+		}
+
+		byLabel, err := h.repo.RatingsByLabel(20)
+		if err != nil {
+			http.Error(w, "Error calculando estadísticas por sello", http.StatusInternalServerError)
+			return
+		}
+
+		byGenre, err := h.repo.RatingsByGenre(20)
+		if err != nil {
+			http.Error(w, "Error calculando estadísticas por género", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.StatsPage(templates.StatsView{
+			RatingsByArtist: byArtist,
+			RatingsByLabel:  byLabel,
+			RatingsByGenre:  byGenre,
+		})
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// TagsHandler maneja la asignación de tags libres a los records desde
+// el panel administrativo
+type TagsHandler struct {
+	repo    *repository.TagRepository
+	records *repository.RecordRepository
+}
+
+// NewTagsHandler crea un nuevo handler de tags
+// Parámetros:
+//   - repo: Repositorio de tags
+//   - records: Repositorio de records, usado para validar el record etiquetado
+//
+// Retorna: Una instancia configurada de TagsHandler
+func NewTagsHandler(repo *repository.TagRepository, records *repository.RecordRepository) *TagsHandler {
+	return &TagsHandler{repo: repo, records: records}
+}
+
+// AddHandler maneja la asignación de tags a un record
+//
+// Endpoint: POST /admin/records/{id}/tags
+//
+// Funcionalidad:
+// - Crea los tags que no existan y los asigna al record
+// - Los nombres se normalizan a minúsculas
+// - Si los tags del record cambian, el cambio queda en su historial
+//
+// Parámetros del Formulario:
+//   - tags: Nombres de tags separados por comas (requerido)
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 400: No se indicaron tags
+//   - 404: Record no encontrado
+//   - 500: Error interno del servidor
+func (h *TagsHandler) AddHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		nombres := splitCommaList(r.FormValue("tags"))
+		if len(nombres) == 0 {
+			http.Error(w, "Indica al menos un tag", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := h.records.GetByID(recordID); err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		if err := h.repo.WithActor(requestActor(r)).AddToRecord(recordID, nombres); err != nil {
+			http.Error(w, "Error asignando tags", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
+
+// RemoveHandler maneja la eliminación de un tag de un record
+//
+// Endpoint: POST /admin/records/{id}/tags/{tagID}/delete
+//
+// Funcionalidad:
+// - Quita el tag del record y registra el cambio en su historial
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 404: El tag no está asignado al record
+func (h *TagsHandler) RemoveHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recordID := chi.URLParam(r, "id")
+		tagID := chi.URLParam(r, "tagID")
+
+		if err := h.repo.WithActor(requestActor(r)).RemoveFromRecord(recordID, tagID); err != nil {
+			http.Error(w, "Tag no encontrado", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+recordID, http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/internal/trash"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// TrashHandler maneja la papelera de records: enviar un record a la
+// papelera, restaurarlo o eliminarlo definitivamente
+type TrashHandler struct {
+	records *repository.RecordRepository
+	purger  *trash.Purger
+}
+
+// NewTrashHandler crea un nuevo handler de papelera
+// Parámetros:
+//   - records: Repositorio de records
+//   - purger: Purgador de la papelera, que define la retención
+//
+// Retorna: Una instancia configurada de TrashHandler
+func NewTrashHandler(records *repository.RecordRepository, purger *trash.Purger) *TrashHandler {
+	return &TrashHandler{records: records, purger: purger}
+}
+
+// ListHandler maneja la lista de records en la papelera
+//
+// Endpoint: GET /admin/trash?page=N
+//
+// Funcionalidad:
+// - Lista los records en la papelera, los eliminados más recientemente primero
+// - Muestra cuándo se purgará cada uno según la retención configurada
+//
+// Respuestas:
+//   - 200: Lista de records en la papelera
+//   - 500: Error interno del servidor
+//
+// Vista: templates.AdminTrash
+func (h *TrashHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+			page = p
+		}
+
+		limit := 50
+		offset := (page - 1) * limit
+
+		records, err := h.records.GetDeleted(limit, offset)
+		if err != nil {
+			http.Error(w, "Error obteniendo papelera", http.StatusInternalServerError)
+			return
+		}
+
+		total, err := h.records.CountDeleted()
+		if err != nil {
+			http.Error(w, "Error contando papelera", http.StatusInternalServerError)
+			return
+		}
+
+		view := templates.TrashView{
+			Records: records,
+			Total:   total,
+			Page:    page,
+			Limit:   limit,
+			Purger:  h.purger,
+		}
+		templ.Handler(templates.AdminTrash(view)).ServeHTTP(w, r)
+	}
+}
+
+// DeleteHandler envía un record a la papelera
+//
+// Endpoint: POST /admin/records/{id}/delete
+//
+// Comportamiento:
+// - El record deja de aparecer en la colección, las búsquedas y las estadísticas
+// - Sus escuchas, préstamos, imágenes, identificadores y tags se conservan hasta que se purga
+//
+// Respuestas:
+//   - 303: Redirección a la lista de records del admin
+//   - 404: Record no encontrado
+func (h *TrashHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.records.WithActor(requestActor(r)).Delete(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, "Record no encontrado", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records", http.StatusSeeOther)
+	}
+}
+
+// RestoreHandler devuelve un record de la papelera a la colección
+//
+// Endpoint: POST /admin/trash/{id}/restore
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del record
+//   - 404: Record no encontrado en la papelera
+func (h *TrashHandler) RestoreHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		if err := h.records.WithActor(requestActor(r)).Restore(id); err != nil {
+			http.Error(w, "Record no encontrado en la papelera", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+id, http.StatusSeeOther)
+	}
+}
+
+// PurgeHandler elimina definitivamente un record de la papelera
+//
+// Endpoint: POST /admin/trash/{id}/purge
+//
+// Comportamiento:
+// - Elimina el record con sus escuchas, préstamos, imágenes, identificadores, tags y entradas de playlists
+// - El historial del record se conserva, con la purga a nombre de quien la hizo
+// - No se puede deshacer
+//
+// Respuestas:
+//   - 303: Redirección a la papelera
+//   - 404: Record no encontrado en la papelera
+func (h *TrashHandler) PurgeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.records.WithActor(requestActor(r)).Purge(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, "Record no encontrado en la papelera", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/trash", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/go-chi/chi/v5"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/web/templates"
+)
+
+// WantlistHandler maneja la lista de discos buscados, separada de la
+// colección, y su conversión en records una vez comprados.
+type WantlistHandler struct {
+	repo *repository.WantlistRepository
+}
+
+// NewWantlistHandler crea un nuevo handler de wantlist
+// Parámetros:
+//   - repo: Repositorio de wantlist
+//
+// Retorna: Una instancia configurada de WantlistHandler
+func NewWantlistHandler(repo *repository.WantlistRepository) *WantlistHandler {
+	return &WantlistHandler{repo: repo}
+}
+
+// ListHandler maneja la vista pública de la wantlist
+//
+// Endpoint: GET /wantlist
+//
+// Funcionalidad:
+// - Muestra los discos buscados ordenados por prioridad
+// - Incluye precio máximo y prensaje preferido de cada entrada
+//
+// Respuestas:
+//   - 200: Wantlist renderizada correctamente
+//   - 500: Error interno del servidor al obtener la wantlist
+//
+// Vista: templates.Wantlist
+func (h *WantlistHandler) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo wantlist", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.Wantlist(items)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// AdminListHandler maneja la gestión de la wantlist
+//
+// Endpoint: GET /admin/wantlist
+//
+// Funcionalidad:
+// - Lista las entradas con acciones de "Lo compré" y eliminar
+// - Incluye el formulario para agregar nuevas entradas
+//
+// Respuestas:
+//   - 200: Wantlist administrativa renderizada correctamente
+//   - 500: Error interno del servidor al obtener la wantlist
+//
+// Vista: templates.AdminWantlist
+func (h *WantlistHandler) AdminListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := h.repo.GetAll()
+		if err != nil {
+			http.Error(w, "Error obteniendo wantlist", http.StatusInternalServerError)
+			return
+		}
+
+		component := templates.AdminWantlist(items)
+		templ.Handler(component).ServeHTTP(w, r)
+	}
+}
+
+// CreateHandler maneja el alta de entradas en la wantlist
+//
+// Endpoint: POST /admin/wantlist
+//
+// Parámetros del Formulario:
+//   - titulo, artista: Datos del disco buscado (requeridos)
+//   - sello, catalog_number, anio, formato, pais, arte_url: Metadatos (opcionales)
+//   - generos, estilos: Listas separadas por comas (opcionales)
+//   - precio_maximo: Precio máximo a pagar (opcional)
+//   - prensaje_preferido: Prensaje o edición preferida (opcional)
+//   - prioridad: 1 alta, 2 media, 3 baja (opcional, default: 2)
+//   - notas: Notas libres (opcional)
+//
+// Respuestas:
+//   - 303: Redirección a /admin/wantlist después de crear
+//   - 400: Datos del formulario inválidos
+//   - 500: Error interno del servidor
+func (h *WantlistHandler) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Error parseando formulario", http.StatusBadRequest)
+			return
+		}
+
+		item := models.NewWantlistItem()
+		item.Titulo = strings.TrimSpace(r.FormValue("titulo"))
+		item.Artista = strings.TrimSpace(r.FormValue("artista"))
+
+		if item.Titulo == "" || item.Artista == "" {
+			http.Error(w, "Título y artista son requeridos", http.StatusBadRequest)
+			return
+		}
+
+		item.Sello = formString(r.FormValue("sello"))
+		item.CatalogNumber = formString(r.FormValue("catalog_number"))
+		item.Formato = formString(r.FormValue("formato"))
+		item.Pais = formString(r.FormValue("pais"))
+		item.ArteURL = formString(r.FormValue("arte_url"))
+		item.Notas = formString(r.FormValue("notas"))
+		item.PrensajePreferido = formString(r.FormValue("prensaje_preferido"))
+		item.SetGeneros(splitCommaList(r.FormValue("generos")))
+		item.SetEstilos(splitCommaList(r.FormValue("estilos")))
+
+		if v := r.FormValue("anio"); v != "" {
+			anio, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, "Año inválido", http.StatusBadRequest)
+				return
+			}
+			item.Anio = sql.NullInt32{Int32: int32(anio), Valid: true}
+		}
+
+		if v := r.FormValue("precio_maximo"); v != "" {
+			precio, err := strconv.ParseFloat(v, 64)
+			if err != nil || precio < 0 {
+				http.Error(w, "Precio máximo inválido", http.StatusBadRequest)
+				return
+			}
+			item.PrecioMaximo = sql.NullFloat64{Float64: precio, Valid: true}
+		}
+
+		if v := r.FormValue("prioridad"); v != "" {
+			prioridad, err := strconv.Atoi(v)
+			if err != nil || prioridad < models.WantPriorityHigh || prioridad > models.WantPriorityLow {
+				http.Error(w, "Prioridad inválida", http.StatusBadRequest)
+				return
+			}
+			item.Prioridad = prioridad
+		}
+
+		if err := h.repo.Create(item); err != nil {
+			http.Error(w, "Error agregando a la wantlist", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/wantlist", http.StatusSeeOther)
+	}
+}
+
+// PurchaseHandler maneja la acción "Lo compré"
+//
+// Endpoint: POST /admin/wantlist/{id}/purchase
+//
+// Funcionalidad:
+// - Crea un record en la colección con los metadatos de la entrada
+// - Elimina la entrada de la wantlist en la misma transacción
+//
+// Respuestas:
+//   - 303: Redirección al detalle administrativo del nuevo record
+//   - 404: Entrada no encontrada
+//   - 500: Error interno del servidor al convertir la entrada
+func (h *WantlistHandler) PurchaseHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		item, err := h.repo.GetByID(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Entrada de wantlist no encontrada", http.StatusNotFound)
+			return
+		}
+
+		record, err := h.repo.WithActor(requestActor(r)).Purchase(item)
+		if err != nil {
+			http.Error(w, "Error agregando el disco a la colección", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/records/"+record.ID, http.StatusSeeOther)
+	}
+}
+
+// DeleteHandler maneja la eliminación de entradas de la wantlist
+//
+// Endpoint: POST /admin/wantlist/{id}/delete
+//
+// Respuestas:
+//   - 303: Redirección a /admin/wantlist
+//   - 404: Entrada no encontrada
+func (h *WantlistHandler) DeleteHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.repo.Delete(chi.URLParam(r, "id")); err != nil {
+			http.Error(w, "Entrada de wantlist no encontrada", http.StatusNotFound)
+			return
+		}
+
+		http.Redirect(w, r, "/admin/wantlist", http.StatusSeeOther)
+	}
+}
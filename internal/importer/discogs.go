@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// discogsArtistSuffix es el sufijo numérico que Discogs agrega para
+// distinguir artistas homónimos, p. ej. "Nirvana (2)"
+var discogsArtistSuffix = regexp.MustCompile(`\s+\(\d+\)$`)
+
+// csvHeader indexa las columnas de un CSV por nombre, sin distinguir mayúsculas
+type csvHeader map[string]int
+
+// newCSVHeader construye el índice a partir de la primera fila del archivo
+func newCSVHeader(columns []string) csvHeader {
+	header := csvHeader{}
+	for i, column := range columns {
+		column = strings.TrimPrefix(column, utf8BOM)
+		header[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	return header
+}
+
+// get retorna el valor de la primera columna existente entre los nombres
+// indicados, sin el escape de fórmulas de la exportación
+func (h csvHeader) get(row []string, names ...string) string {
+	for _, name := range names {
+		if i, ok := h[strings.ToLower(name)]; ok && i < len(row) {
+			return strings.TrimSpace(unescapeCell(row[i]))
+		}
+	}
+	return ""
+}
+
+// has verifica que exista una columna
+func (h csvHeader) has(name string) bool {
+	_, ok := h[strings.ToLower(name)]
+	return ok
+}
+
+// ParseDiscogsCSV lee una exportación de colección de Discogs y convierte
+// cada fila en un record. Las filas inválidas se marcan con error en vez
+// de interrumpir la lectura.
+func ParseDiscogsCSV(r io.Reader) ([]*Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	columns, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo encabezado del CSV: %w", err)
+	}
+
+	header := newCSVHeader(columns)
+	for _, required := range []string{"Artist", "Title"} {
+		if !header.has(required) {
+			return nil, fmt.Errorf("el CSV no es una exportación de Discogs: falta la columna %s", required)
+		}
+	}
+
+	var rows []*Row
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo línea %d del CSV: %w", line, err)
+		}
+
+		rows = append(rows, discogsRow(header, values, line))
+	}
+
+	return rows, nil
+}
+
+// discogsRow convierte una fila de la exportación de Discogs en un record
+func discogsRow(header csvHeader, values []string, line int) *Row {
+	record := models.NewRecord()
+	row := &Row{Line: line, Record: record}
+
+	record.Artista = discogsArtistSuffix.ReplaceAllString(header.get(values, "Artist"), "")
+	record.Titulo = header.get(values, "Title")
+	if record.Artista == "" || record.Titulo == "" {
+		row.Status = StatusError
+		row.Message = "artista y título son requeridos"
+		return row
+	}
+
+	record.CatalogNumber = nullString(header.get(values, "Catalog#"))
+	record.Sello = nullString(header.get(values, "Label"))
+	record.Formato = nullString(header.get(values, "Format"))
+	record.Notas = nullString(header.get(values, "Collection Notes", "Notes"))
+	record.Condicion = discogsCondition(
+		header.get(values, "Collection Media Condition"),
+		header.get(values, "Collection Sleeve Condition"),
+	)
+
+	if released := header.get(values, "Released"); len(released) >= 4 {
+		if year, err := strconv.Atoi(released[:4]); err == nil && year > 0 {
+			record.Anio = sql.NullInt32{Int32: int32(year), Valid: true}
+		}
+	}
+
+	if v := header.get(values, "Rating"); v != "" {
+		rating, err := strconv.ParseFloat(v, 64)
+		if err != nil || (rating != 0 && !models.IsValidRating(rating)) {
+			row.Status = StatusError
+			row.Message = "calificación inválida: " + v
+			return row
+		}
+		if rating > 0 {
+			record.Rating = sql.NullFloat64{Float64: rating, Valid: true}
+		}
+	}
+
+	if v := header.get(values, "release_id"); v != "" {
+		identifier, err := models.NewIdentifier(record.ID, models.IdentifierDiscogs, v)
+		if err != nil {
+			row.Status = StatusError
+			row.Message = err.Error()
+			return row
+		}
+		row.Identifiers = append(row.Identifiers, identifier)
+	}
+
+	return row
+}
+
+// discogsCondition combina el estado del disco y de la funda en un solo texto
+func discogsCondition(media, sleeve string) sql.NullString {
+	switch {
+	case media != "" && sleeve != "":
+		return nullString("Disco: " + media + " · Funda: " + sleeve)
+	case sleeve != "":
+		return nullString("Funda: " + sleeve)
+	}
+	return nullString(media)
+}
+
+// nullString retorna un NullString inválido para textos vacíos
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+func TestParseDiscogsCSV(t *testing.T) {
+	file, err := os.Open("testdata/discogs_collection.csv")
+	if err != nil {
+		t.Fatalf("error abriendo fixture: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := ParseDiscogsCSV(file)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	tests := []struct {
+		line      int
+		artista   string
+		titulo    string
+		formato   string
+		condicion string
+		anio      int32
+		rating    float64
+		releaseID string
+		message   string // error esperado, vacío si la fila es válida
+	}{
+		{
+			line: 2, artista: "Nirvana", titulo: "Nevermind", formato: "Vinyl, LP, Album, RE",
+			condicion: "Disco: Near Mint (NM or M-) · Funda: Very Good Plus (VG+)",
+			anio:      1991, rating: 5, releaseID: "367113",
+		},
+		{line: 3, artista: "Miles Davis", titulo: "Kind Of Blue", formato: "CD, Album, RM", condicion: "Mint (M)", anio: 1959},
+		{line: 4, artista: "The Beatles", titulo: "Abbey Road", formato: "Vinyl, LP, Album", condicion: "Funda: Good (G)"},
+		{line: 5, message: "artista y título son requeridos"},
+		{line: 6, message: "calificación inválida: diez"},
+		{line: 7, message: "ID de release de Discogs inválido: abc"},
+		{line: 8, artista: "Portishead", titulo: "Dummy"},
+	}
+
+	if len(rows) != len(tests) {
+		t.Fatalf("filas = %d, se esperaban %d", len(rows), len(tests))
+	}
+	for i, tt := range tests {
+		row := rows[i]
+		if row.Line != tt.line {
+			t.Errorf("fila %d en la línea %d, se esperaba %d", i, row.Line, tt.line)
+		}
+
+		if tt.message != "" {
+			if row.Status != StatusError || !strings.Contains(row.Message, tt.message) {
+				t.Errorf("línea %d = %s: %q, se esperaba un error con %q", tt.line, row.Status, row.Message, tt.message)
+			}
+			continue
+		}
+		if row.Status != "" {
+			t.Errorf("línea %d = %s: %q, se esperaba una fila válida", tt.line, row.Status, row.Message)
+			continue
+		}
+
+		record := row.Record
+		if record.Artista != tt.artista || record.Titulo != tt.titulo {
+			t.Errorf("línea %d = %s - %s, se esperaba %s - %s", tt.line, record.Artista, record.Titulo, tt.artista, tt.titulo)
+		}
+		if record.Formato.String != tt.formato || record.Formato.Valid != (tt.formato != "") {
+			t.Errorf("línea %d: formato = %q, se esperaba %q", tt.line, record.Formato.String, tt.formato)
+		}
+		if record.Condicion.String != tt.condicion {
+			t.Errorf("línea %d: condición = %q, se esperaba %q", tt.line, record.Condicion.String, tt.condicion)
+		}
+		if record.Anio.Int32 != tt.anio || record.Rating.Float64 != tt.rating || record.Rating.Valid != (tt.rating > 0) {
+			t.Errorf("línea %d: año %d, calificación %v", tt.line, record.Anio.Int32, record.Rating)
+		}
+
+		var releaseID string
+		for _, identifier := range row.Identifiers {
+			if identifier.Tipo == models.IdentifierDiscogs && identifier.RecordID == record.ID {
+				releaseID = identifier.Valor
+			}
+		}
+		if releaseID != tt.releaseID {
+			t.Errorf("línea %d: release de Discogs = %q, se esperaba %q", tt.line, releaseID, tt.releaseID)
+		}
+	}
+
+	if notas := rows[0].Record.Notas.String; notas != "Primera copia" {
+		t.Errorf("notas = %q", notas)
+	}
+}
+
+func TestParseDiscogsCSVInvalidFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{name: "vacío", content: "", message: "encabezado"},
+		{name: "sin columna Title", content: "Artist,Album\nNirvana,Nevermind\n", message: "falta la columna Title"},
+		{name: "comillas sin cerrar", content: "Artist,Title\nNirvana,Nevermind\nNirvana,\"Bleach\n", message: "línea 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDiscogsCSV(strings.NewReader(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("error = %v, se esperaba uno con %q", err, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Package importer convierte archivos externos (exportaciones de Discogs,
+// CSV y JSON genéricos) en records de la colección, detectando duplicados
+// y generando un reporte fila por fila.
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// Estados de una fila importada
+const (
+	StatusNew       = "nuevo"
+	StatusDuplicate = "duplicado"
+	StatusError     = "error"
+)
+
+// Row es una fila del archivo importado convertida en record
+type Row struct {
+	Line        int
+	Record      *models.Record
+	Identifiers []*models.Identifier
+	Status      string
+	Message     string
+	DuplicateOf string // ID del record existente cuando la fila es un duplicado
+}
+
+// Report resume el resultado de una importación
+type Report struct {
+	Source     string
+	DryRun     bool
+	Rows       []*Row
+	Created    int
+	Duplicates int
+	Errors     int
+	Rejected   bool // la importación se canceló porque había filas con errores
+}
+
+// Summary retorna un resumen de una línea del reporte
+func (r *Report) Summary() string {
+	if r.Rejected {
+		return fmt.Sprintf("%d filas: importación cancelada, %d con errores (no se guardó ningún record)",
+			len(r.Rows), r.Errors)
+	}
+	verb := "importados"
+	if r.DryRun {
+		verb = "por importar"
+	}
+	return fmt.Sprintf("%d filas: %d %s, %d duplicados, %d con errores",
+		len(r.Rows), r.Created, verb, r.Duplicates, r.Errors)
+}
+
+// WriteText escribe el reporte como texto plano, una línea por fila
+// omitida, para la línea de comandos
+func (r *Report) WriteText(w io.Writer) {
+	for _, row := range r.Rows {
+		if row.Status == StatusNew {
+			continue
+		}
+		label := ""
+		if row.Record != nil {
+			label = row.Record.Artista + " - " + row.Record.Titulo + ": "
+		}
+		fmt.Fprintf(w, "línea %d [%s] %s%s\n", row.Line, row.Status, label, row.Message)
+	}
+	fmt.Fprintln(w, r.Summary())
+}
+
+// Importer detecta duplicados y guarda las filas nuevas de una importación
+type Importer struct {
+	records     *repository.RecordRepository
+	identifiers *repository.IdentifierRepository
+}
+
+// New crea un nuevo importador
+func New(records *repository.RecordRepository, identifiers *repository.IdentifierRepository) *Importer {
+	return &Importer{records: records, identifiers: identifiers}
+}
+
+// WithActor retorna una copia del importador que registra los records
+// creados en el historial a nombre del actor indicado
+func (im *Importer) WithActor(actor string) *Importer {
+	return &Importer{records: im.records.WithActor(actor), identifiers: im.identifiers}
+}
+
+// Run marca los duplicados de las filas y, si no es dry-run, crea todas
+// las filas nuevas en una sola transacción
+func (im *Importer) Run(source string, rows []*Row, dryRun bool) (*Report, error) {
+	report := &Report{Source: source, DryRun: dryRun, Rows: rows}
+	seen := map[string]int{}
+
+	var records []*models.Record
+	var identifiers []*models.Identifier
+	for _, row := range rows {
+		if row.Status == "" {
+			if err := im.checkDuplicate(row, seen); err != nil {
+				return nil, err
+			}
+		}
+
+		switch row.Status {
+		case StatusError:
+			report.Errors++
+		case StatusDuplicate:
+			report.Duplicates++
+		default:
+			row.Status = StatusNew
+			report.Created++
+			records = append(records, row.Record)
+			identifiers = append(identifiers, row.Identifiers...)
+		}
+	}
+
+	if dryRun || len(records) == 0 {
+		return report, nil
+	}
+
+	if err := im.records.CreateMany(records, identifiers); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// RunAll es como Run, pero guarda todas las filas nuevas o ninguna: si
+// alguna fila tiene errores, la importación se cancela y solo se reportan
+func (im *Importer) RunAll(source string, rows []*Row, dryRun bool) (*Report, error) {
+	rejected := false
+	for _, row := range rows {
+		if row.Status == StatusError {
+			rejected = true
+			break
+		}
+	}
+
+	report, err := im.Run(source, rows, dryRun || rejected)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = dryRun
+	report.Rejected = rejected && !dryRun
+	return report, nil
+}
+
+// checkDuplicate marca la fila como duplicada si ya existe en la colección
+// (por identificador o por artista, título y catálogo) o si ya apareció
+// antes en el mismo archivo
+func (im *Importer) checkDuplicate(row *Row, seen map[string]int) error {
+	keys := []string{duplicateKey(row.Record)}
+	for _, identifier := range row.Identifiers {
+		if identifier.Tipo == models.IdentifierMatrix {
+			continue
+		}
+		keys = append(keys, identifier.Tipo+":"+identifier.Valor)
+
+		recordID, err := im.identifiers.FindRecordID(identifier.Tipo, identifier.Valor)
+		if err != nil {
+			return err
+		}
+		if recordID != "" {
+			row.Status = StatusDuplicate
+			row.DuplicateOf = recordID
+			row.Message = fmt.Sprintf("ya existe en la colección (%s %s)", identifier.GetTypeLabel(), identifier.Valor)
+			return nil
+		}
+	}
+
+	existing, err := im.records.FindDuplicate(row.Record.Artista, row.Record.Titulo, row.Record.CatalogNumber)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		row.Status = StatusDuplicate
+		row.DuplicateOf = existing.ID
+		row.Message = "ya existe en la colección (mismo artista y título)"
+		return nil
+	}
+
+	for _, key := range keys {
+		if line, ok := seen[key]; ok {
+			row.Status = StatusDuplicate
+			row.Message = fmt.Sprintf("repetido en el archivo (línea %d)", line)
+			return nil
+		}
+	}
+	for _, key := range keys {
+		seen[key] = row.Line
+	}
+	return nil
+}
+
+// duplicateKey identifica un record por artista, título y catálogo
+func duplicateKey(record *models.Record) string {
+	return strings.ToLower(record.Artista + "|" + record.Titulo + "|" + record.CatalogNumber.String)
+}
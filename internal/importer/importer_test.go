@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// newTestDB crea una base de datos en memoria con todas las migraciones
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("error abriendo BD: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob("../../migrations/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no se encontraron migraciones: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("error leyendo %s: %v", file, err)
+		}
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("error aplicando %s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// newTestImporter crea un importador sobre una colección que ya tiene
+// "Nirvana - Nevermind" con su código de barras, y retorna su ID
+func newTestImporter(t *testing.T) (*Importer, *repository.RecordRepository, string) {
+	t.Helper()
+	db := newTestDB(t)
+	records := repository.NewRecordRepository(db)
+	identifiers := repository.NewIdentifierRepository(db)
+
+	row := testRow(0, "Nirvana", "Nevermind", "720642442517")
+	if err := records.CreateMany([]*models.Record{row.Record}, row.Identifiers); err != nil {
+		t.Fatalf("error creando record: %v", err)
+	}
+	return New(records, identifiers), records, row.Record.ID
+}
+
+// testRow crea una fila válida con un código de barras opcional
+func testRow(line int, artista, titulo, barcode string) *Row {
+	record := models.NewRecord()
+	record.Artista = artista
+	record.Titulo = titulo
+	row := &Row{Line: line, Record: record}
+	if barcode != "" {
+		identifier, err := models.NewIdentifier(record.ID, models.IdentifierBarcode, barcode)
+		if err != nil {
+			panic(err)
+		}
+		row.Identifiers = append(row.Identifiers, identifier)
+	}
+	return row
+}
+
+// errorRow crea una fila que no se pudo convertir
+func errorRow(line int) *Row {
+	row := testRow(line, "Nirvana", "", "")
+	row.Status = StatusError
+	row.Message = "artista y título son requeridos"
+	return row
+}
+
+func TestRunAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     func() []*Row
+		dryRun   bool
+		statuses []string
+		messages []string // mensajes esperados por fila, vacío si no importa
+		rejected bool
+		saved    int // records nuevos en la colección
+	}{
+		{
+			name: "filas nuevas",
+			rows: func() []*Row {
+				return []*Row{testRow(2, "Nirvana", "Bleach", ""), testRow(3, "Nirvana", "In Utero", "720642453629")}
+			},
+			statuses: []string{StatusNew, StatusNew},
+			saved:    2,
+		},
+		{
+			name: "ya existe por artista y título",
+			rows: func() []*Row {
+				return []*Row{testRow(2, "NIRVANA", "nevermind", ""), testRow(3, "Nirvana", "Bleach", "")}
+			},
+			statuses: []string{StatusDuplicate, StatusNew},
+			messages: []string{"ya existe en la colección (mismo artista y título)"},
+			saved:    1,
+		},
+		{
+			name: "ya existe por identificador",
+			rows: func() []*Row {
+				return []*Row{testRow(2, "Nirvana", "Nevermind (Remastered)", "720642442517")}
+			},
+			statuses: []string{StatusDuplicate},
+			messages: []string{"ya existe en la colección (Código de barras 720642442517)"},
+		},
+		{
+			name: "repetido en el archivo por artista y título",
+			rows: func() []*Row {
+				return []*Row{testRow(2, "Nirvana", "Bleach", ""), testRow(3, "nirvana", "BLEACH", "")}
+			},
+			statuses: []string{StatusNew, StatusDuplicate},
+			messages: []string{"", "repetido en el archivo (línea 2)"},
+			saved:    1,
+		},
+		{
+			name: "repetido en el archivo por identificador",
+			rows: func() []*Row {
+				return []*Row{testRow(2, "Nirvana", "In Utero", "720642453629"), testRow(3, "Nirvana", "In Utero (Deluxe)", "720642453629")}
+			},
+			statuses: []string{StatusNew, StatusDuplicate},
+			messages: []string{"", "repetido en el archivo (línea 2)"},
+			saved:    1,
+		},
+		{
+			name: "una fila con errores cancela la importación",
+			rows: func() []*Row {
+				return []*Row{testRow(2, "Nirvana", "Bleach", ""), errorRow(3), testRow(4, "Nirvana", "In Utero", "")}
+			},
+			statuses: []string{StatusNew, StatusError, StatusNew},
+			rejected: true,
+		},
+		{
+			name: "dry-run no guarda",
+			rows: func() []*Row {
+				return []*Row{testRow(2, "Nirvana", "Bleach", ""), errorRow(3)}
+			},
+			dryRun:   true,
+			statuses: []string{StatusNew, StatusError},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			im, records, _ := newTestImporter(t)
+
+			report, err := im.RunAll("prueba", tt.rows(), tt.dryRun)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			for i, row := range report.Rows {
+				if row.Status != tt.statuses[i] {
+					t.Errorf("fila %d = %s (%s), se esperaba %s", row.Line, row.Status, row.Message, tt.statuses[i])
+				}
+				if i < len(tt.messages) && tt.messages[i] != "" && row.Message != tt.messages[i] {
+					t.Errorf("mensaje de la fila %d = %q, se esperaba %q", row.Line, row.Message, tt.messages[i])
+				}
+			}
+			if report.Rejected != tt.rejected || report.DryRun != tt.dryRun {
+				t.Errorf("cancelada = %v, dry-run = %v", report.Rejected, report.DryRun)
+			}
+
+			total, err := records.Count()
+			if err != nil {
+				t.Fatalf("error contando records: %v", err)
+			}
+			if total != 1+tt.saved {
+				t.Errorf("records en la colección = %d, se esperaban %d", total, 1+tt.saved)
+			}
+		})
+	}
+}
+
+func TestRunAllDuplicateOf(t *testing.T) {
+	im, _, existingID := newTestImporter(t)
+
+	report, err := im.RunAll("prueba", []*Row{testRow(2, "Nirvana", "Nevermind", "")}, false)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got := report.Rows[0].DuplicateOf; got != existingID {
+		t.Errorf("duplicado de %q, se esperaba %q", got, existingID)
+	}
+	if report.Created != 0 || report.Duplicates != 1 {
+		t.Errorf("reporte: %s", report.Summary())
+	}
+}
+
+func TestRunRollsBackOnSaveError(t *testing.T) {
+	im, records, existingID := newTestImporter(t)
+
+	// La segunda fila choca con el ID de un record existente al guardarse,
+	// después de que la primera ya se insertó en la transacción
+	clash := testRow(3, "Nirvana", "In Utero", "720642453629")
+	clash.Record.ID = existingID
+	clash.Identifiers[0].RecordID = existingID
+	rows := []*Row{testRow(2, "Nirvana", "Bleach", "4006381333931"), clash}
+
+	if _, err := im.RunAll("prueba", rows, false); err == nil {
+		t.Fatal("se esperaba un error al guardar")
+	}
+
+	total, err := records.Count()
+	if err != nil {
+		t.Fatalf("error contando records: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("records en la colección = %d, se esperaba solo el existente", total)
+	}
+	if existing, err := records.FindDuplicate("Nirvana", "Bleach", rows[0].Record.CatalogNumber); err != nil || existing != nil {
+		t.Errorf("la primera fila quedó guardada: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package importer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// IdentifierTargetPrefix antecede al tipo en los campos destino que crean
+// identificadores, p. ej. "identificador.barcode"
+const IdentifierTargetPrefix = "identificador."
+
+// Target es un campo del record al que se puede asignar una columna
+type Target struct {
+	Key   string
+	Label string
+}
+
+// recordTargets lista los campos propios del record en el orden del formulario
+var recordTargets = []Target{
+	{"titulo", "Título"},
+	{"artista", "Artista"},
+	{"sello", "Sello"},
+	{"catalog_number", "Número de catálogo"},
+	{"anio", "Año"},
+	{"formato", "Formato"},
+	{"generos", "Géneros"},
+	{"estilos", "Estilos"},
+	{"pais", "País"},
+	{"tracklist", "Tracklist (JSON)"},
+	{"duracion_total", "Duración total"},
+	{"arte_url", "URL del arte"},
+	{"condicion", "Condición"},
+	{"notas", "Notas"},
+	{"rating", "Calificación"},
+	{"review", "Reseña"},
+}
+
+// targetAliases asigna nombres de columna habituales en planillas a campos
+var targetAliases = map[string]string{
+	"title":        "titulo",
+	"album":        "titulo",
+	"artist":       "artista",
+	"label":        "sello",
+	"catalog":      "catalog_number",
+	"catalogo":     "catalog_number",
+	"cat":          "catalog_number",
+	"year":         "anio",
+	"ano":          "anio",
+	"released":     "anio",
+	"format":       "formato",
+	"genre":        "generos",
+	"genres":       "generos",
+	"genero":       "generos",
+	"style":        "estilos",
+	"styles":       "estilos",
+	"estilo":       "estilos",
+	"country":      "pais",
+	"duration":     "duracion_total",
+	"duracion":     "duracion_total",
+	"cover":        "arte_url",
+	"artwork":      "arte_url",
+	"condition":    "condicion",
+	"notes":        "notas",
+	"nota":         "notas",
+	"calificacion": "rating",
+	"barcode":      IdentifierTargetPrefix + models.IdentifierBarcode,
+	"ean":          IdentifierTargetPrefix + models.IdentifierBarcode,
+	"upc":          IdentifierTargetPrefix + models.IdentifierBarcode,
+	"release_id":   IdentifierTargetPrefix + models.IdentifierDiscogs,
+	"matrix":       IdentifierTargetPrefix + models.IdentifierMatrix,
+	"runout":       IdentifierTargetPrefix + models.IdentifierMatrix,
+	"mbid":         IdentifierTargetPrefix + models.IdentifierMusicBrainz,
+}
+
+// Targets lista los campos a los que se pueden asignar columnas: los del
+// record, los identificadores y los campos personalizados
+func Targets(fields []*models.CustomField) []Target {
+	targets := append([]Target{}, recordTargets...)
+	for _, tipo := range models.IdentifierTypes {
+		targets = append(targets, Target{IdentifierTargetPrefix + tipo, models.IdentifierTypeLabel(tipo)})
+	}
+	for _, field := range fields {
+		targets = append(targets, Target{models.CustomFieldFilterPrefix + field.Clave, field.Nombre})
+	}
+	return targets
+}
+
+// GuessMapping propone un campo para cada columna comparando su nombre con
+// las claves, los nombres y los alias conocidos de los campos
+func GuessMapping(columns []string, fields []*models.CustomField) map[string]string {
+	byName := map[string]string{}
+	for alias, key := range targetAliases {
+		byName[alias] = key
+	}
+	for _, target := range Targets(fields) {
+		byName[models.CustomFieldKey(target.Label)] = target.Key
+		byName[models.CustomFieldKey(target.Key)] = target.Key
+		if i := strings.LastIndex(target.Key, "."); i >= 0 {
+			byName[target.Key[i+1:]] = target.Key
+		}
+	}
+
+	mapping := map[string]string{}
+	used := map[string]bool{}
+	for _, column := range columns {
+		key, ok := byName[models.CustomFieldKey(column)]
+		if ok && !used[key] {
+			mapping[column] = key
+			used[key] = true
+		}
+	}
+	return mapping
+}
+
+// MapTable convierte las filas de la tabla en records según el mapeo de
+// columnas a campos. Cada fila se valida por separado y los errores quedan
+// en la fila en vez de interrumpir la conversión.
+func MapTable(table *Table, mapping map[string]string, fields []*models.CustomField) ([]*Row, error) {
+	assigned := map[string]bool{}
+	for _, key := range mapping {
+		assigned[key] = true
+	}
+	if !assigned["titulo"] || !assigned["artista"] {
+		return nil, fmt.Errorf("debe asignar columnas a Título y Artista")
+	}
+
+	fieldsByKey := map[string]*models.CustomField{}
+	for _, field := range fields {
+		fieldsByKey[field.Clave] = field
+	}
+
+	rows := make([]*Row, 0, len(table.Rows))
+	for i, values := range table.Rows {
+		row := &Row{Line: table.Line(i), Record: models.NewRecord()}
+		campos := map[string]any{}
+
+		var problems []string
+		for c, column := range table.Columns {
+			key := mapping[column]
+			value := ""
+			if c < len(values) {
+				value = strings.TrimSpace(values[c])
+			}
+			if key == "" || value == "" {
+				continue
+			}
+
+			if err := setTarget(row, campos, fieldsByKey, key, value); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+		row.Record.SetCampos(campos)
+
+		if row.Record.Artista == "" || row.Record.Titulo == "" {
+			problems = append(problems, "artista y título son requeridos")
+		}
+		if len(problems) > 0 {
+			row.Status = StatusError
+			row.Message = strings.Join(problems, "; ")
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// setTarget asigna el valor de una celda al campo indicado del record
+func setTarget(row *Row, campos map[string]any, fields map[string]*models.CustomField, key, value string) error {
+	record := row.Record
+
+	if tipo, ok := strings.CutPrefix(key, IdentifierTargetPrefix); ok {
+		identifier, err := models.NewIdentifier(record.ID, tipo, value)
+		if err != nil {
+			return err
+		}
+		row.Identifiers = append(row.Identifiers, identifier)
+		return nil
+	}
+
+	if clave, ok := strings.CutPrefix(key, models.CustomFieldFilterPrefix); ok {
+		field, ok := fields[clave]
+		if !ok {
+			return fmt.Errorf("campo personalizado desconocido: %s", clave)
+		}
+		parsed, err := field.ParseValue(value)
+		if err != nil {
+			return err
+		}
+		campos[clave] = parsed
+		return nil
+	}
+
+	switch key {
+	case "titulo":
+		record.Titulo = value
+	case "artista":
+		record.Artista = value
+	case "sello":
+		record.Sello = nullString(value)
+	case "catalog_number":
+		record.CatalogNumber = nullString(value)
+	case "formato":
+		record.Formato = nullString(value)
+	case "pais":
+		record.Pais = nullString(value)
+	case "duracion_total":
+		record.DuracionTotal = nullString(value)
+	case "arte_url":
+		record.ArteURL = nullString(value)
+	case "condicion":
+		record.Condicion = nullString(value)
+	case "notas":
+		record.Notas = nullString(value)
+	case "review":
+		record.Review = nullString(value)
+	case "generos":
+		record.SetGeneros(splitList(value))
+	case "estilos":
+		record.SetEstilos(splitList(value))
+	case "anio":
+		year, err := strconv.Atoi(value)
+		if err != nil && len(value) > 4 {
+			// Fechas completas como "1973-03-01"
+			year, err = strconv.Atoi(value[:4])
+		}
+		if err != nil || year < 1000 || year > 9999 {
+			return fmt.Errorf("año inválido: %s", value)
+		}
+		record.Anio = sql.NullInt32{Int32: int32(year), Valid: true}
+	case "rating":
+		rating, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+		if err != nil || !models.IsValidRating(rating) {
+			return fmt.Errorf("calificación inválida: %s", value)
+		}
+		record.Rating = sql.NullFloat64{Float64: rating, Valid: true}
+	case "tracklist":
+		var tracklist []models.Track
+		if err := json.Unmarshal([]byte(value), &tracklist); err != nil {
+			return fmt.Errorf("tracklist inválido: debe ser un arreglo JSON de tracks")
+		}
+		record.SetTracklist(tracklist)
+	default:
+		return fmt.Errorf("campo desconocido: %s", key)
+	}
+	return nil
+}
+
+// splitList separa una lista escrita en una celda por comas, punto y coma o barras
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(c rune) bool {
+		return c == ',' || c == ';' || c == '|'
+	}) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// testFields son los campos personalizados de las pruebas de mapeo
+func testFields() []*models.CustomField {
+	copias := models.NewCustomField("Copias", models.CustomFieldNumber)
+	firmado := models.NewCustomField("Firmado", models.CustomFieldBool)
+	return []*models.CustomField{copias, firmado}
+}
+
+func TestGuessMapping(t *testing.T) {
+	columns := []string{"Artist", "Album", "Title", "Año", "EAN", "Copias", "Comentario"}
+	got := GuessMapping(columns, testFields())
+	want := map[string]string{
+		"Artist": "artista",
+		"Album":  "titulo",
+		// Title ya no se asigna: Album tomó el título
+		"Año":    "anio",
+		"EAN":    IdentifierTargetPrefix + models.IdentifierBarcode,
+		"Copias": models.CustomFieldFilterPrefix + "copias",
+	}
+	if len(got) != len(want) {
+		t.Errorf("mapeo = %v, se esperaba %v", got, want)
+	}
+	for column, key := range want {
+		if got[column] != key {
+			t.Errorf("columna %s = %q, se esperaba %q", column, got[column], key)
+		}
+	}
+}
+
+func TestMapTable(t *testing.T) {
+	columns := []string{"artista", "titulo", "anio", "rating", "generos", "barcode", "copias", "firmado", "ignorada"}
+	mapping := map[string]string{
+		"artista": "artista",
+		"titulo":  "titulo",
+		"anio":    "anio",
+		"rating":  "rating",
+		"generos": "generos",
+		"barcode": IdentifierTargetPrefix + models.IdentifierBarcode,
+		"copias":  models.CustomFieldFilterPrefix + "copias",
+		"firmado": models.CustomFieldFilterPrefix + "firmado",
+	}
+
+	tests := []struct {
+		name    string
+		values  []string
+		check   func(t *testing.T, row *Row)
+		message string // errores esperados en la fila, vacío si es válida
+	}{
+		{
+			name:   "fila completa",
+			values: []string{" Nirvana ", "Nevermind", "1991", "4,5", "Rock; Grunge |", "7 20642 44251-7", "2", "sí", "x"},
+			check: func(t *testing.T, row *Row) {
+				record := row.Record
+				if record.Artista != "Nirvana" || record.Titulo != "Nevermind" || record.Anio.Int32 != 1991 || record.Rating.Float64 != 4.5 {
+					t.Errorf("record = %s - %s, %d, %v", record.Artista, record.Titulo, record.Anio.Int32, record.Rating.Float64)
+				}
+				if generos := record.GetGenerosAsSlice(); !slices.Equal(generos, []string{"Rock", "Grunge"}) {
+					t.Errorf("géneros = %v", generos)
+				}
+				if len(row.Identifiers) != 1 || row.Identifiers[0].Valor != "720642442517" || row.Identifiers[0].RecordID != record.ID {
+					t.Errorf("identificadores = %+v", row.Identifiers)
+				}
+				campos := record.GetCampos()
+				if campos["copias"] != 2.0 || campos["firmado"] != true {
+					t.Errorf("campos personalizados = %v", campos)
+				}
+			},
+		},
+		{
+			name:   "año como fecha completa",
+			values: []string{"Pink Floyd", "The Dark Side of the Moon", "1973-03-01"},
+			check: func(t *testing.T, row *Row) {
+				if row.Record.Anio.Int32 != 1973 {
+					t.Errorf("año = %d, se esperaba 1973", row.Record.Anio.Int32)
+				}
+			},
+		},
+		{
+			name:   "celdas faltantes o vacías",
+			values: []string{"Miles Davis", "Kind of Blue", "", ""},
+			check: func(t *testing.T, row *Row) {
+				if row.Record.Anio.Valid || row.Record.Rating.Valid || len(row.Identifiers) != 0 || len(row.Record.GetCampos()) != 0 {
+					t.Errorf("se asignaron celdas vacías: %+v", row.Record)
+				}
+			},
+		},
+		{
+			name:    "sin título",
+			values:  []string{"Nirvana", " "},
+			message: "artista y título son requeridos",
+		},
+		{
+			name:    "varios errores en la misma fila",
+			values:  []string{"Nirvana", "Bleach", "89", "7", "", "123", "muchas", "quizás"},
+			message: "año inválido: 89; calificación inválida: 7; código de barras EAN/UPC inválido: 123; Copias debe ser un número; Firmado debe ser sí o no",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &Table{Format: FormatCSV, Columns: columns, Rows: [][]string{tt.values}}
+			rows, err := MapTable(table, mapping, testFields())
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if len(rows) != 1 || rows[0].Line != 2 {
+				t.Fatalf("filas = %+v, se esperaba una en la línea 2", rows)
+			}
+			row := rows[0]
+
+			if tt.message == "" {
+				if row.Status != "" {
+					t.Fatalf("estado = %s (%s), se esperaba una fila válida", row.Status, row.Message)
+				}
+				tt.check(t, row)
+				return
+			}
+			if row.Status != StatusError || row.Message != tt.message {
+				t.Errorf("fila = %s: %q, se esperaba un error %q", row.Status, row.Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestMapTableRequiresTitleAndArtist(t *testing.T) {
+	table := &Table{Format: FormatJSON, Columns: []string{"artist"}, Rows: [][]string{{"Nirvana"}}}
+	if _, err := MapTable(table, map[string]string{"artist": "artista"}, nil); err == nil {
+		t.Error("se aceptó un mapeo sin título")
+	}
+}
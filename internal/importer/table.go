@@ -0,0 +1,221 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Formatos de archivo soportados por la importación genérica
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// utf8BOM es la marca de orden de bytes que agregan algunas planillas al exportar
+const utf8BOM = "\ufeff"
+
+// formulaPrefixes son los caracteres con los que una planilla interpreta una
+// celda como fórmula
+const formulaPrefixes = "=+-@\t\r"
+
+// isFormula indica si una celda se escapa al exportar: si empieza como una
+// fórmula o si es una celda ya escapada, para que el escape se pueda revertir
+func isFormula(value string) bool {
+	if value == "" {
+		return false
+	}
+	if value[0] == '\'' {
+		return isFormula(value[1:])
+	}
+	return strings.IndexByte(formulaPrefixes, value[0]) >= 0
+}
+
+// EscapeCell antepone un apóstrofo a las celdas que una planilla
+// ejecutaría como fórmula (p. ej. "=HYPERLINK(...)"). La importación de CSV
+// quita el apóstrofo, de modo que los archivos exportados se pueden volver a
+// importar sin cambios.
+func EscapeCell(value string) string {
+	if isFormula(value) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCell revierte EscapeCell
+func unescapeCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && isFormula(value[1:]) {
+		return value[1:]
+	}
+	return value
+}
+
+// Table es un archivo CSV o JSON leído como columnas y filas de texto,
+// antes de asignar las columnas a campos del record
+type Table struct {
+	Format  string
+	Columns []string
+	Rows    [][]string
+}
+
+// DetectFormat deduce el formato del contenido: JSON si empieza con un
+// arreglo u objeto, CSV en cualquier otro caso
+func DetectFormat(content []byte) string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(content, []byte(utf8BOM)), " \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return FormatJSON
+	}
+	return FormatCSV
+}
+
+// ReadTable lee un archivo CSV (con encabezado) o JSON (un arreglo de
+// objetos o un objeto por línea) como tabla de texto
+func ReadTable(content []byte, format string) (*Table, error) {
+	switch format {
+	case FormatCSV:
+		return readCSVTable(content)
+	case FormatJSON:
+		return readJSONTable(content)
+	}
+	return nil, fmt.Errorf("formato de importación no soportado: %s", format)
+}
+
+// readCSVTable lee un CSV usando la primera fila como encabezado
+func readCSVTable(content []byte) (*Table, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte(utf8BOM))))
+	reader.FieldsPerRecord = -1
+
+	columns, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo encabezado del CSV: %w", err)
+	}
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	table := &Table{Format: FormatCSV, Columns: columns}
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo línea %d del CSV: %w", line, err)
+		}
+
+		row := make([]string, len(columns))
+		for i := 0; i < len(row) && i < len(values); i++ {
+			row[i] = unescapeCell(values[i])
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+// readJSONTable lee un arreglo de objetos JSON o una secuencia de objetos
+// (NDJSON). Las columnas son la unión de las claves de todos los objetos.
+func readJSONTable(content []byte) (*Table, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bytes.TrimPrefix(content, []byte(utf8BOM))))
+	decoder.UseNumber()
+
+	var objects []map[string]any
+	for {
+		var value any
+		if err := decoder.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error leyendo JSON: %w", err)
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			objects = append(objects, v)
+		case []any:
+			for i, item := range v {
+				object, ok := item.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("el elemento %d del JSON no es un objeto", i+1)
+				}
+				objects = append(objects, object)
+			}
+		default:
+			return nil, fmt.Errorf("el JSON debe ser un arreglo de objetos")
+		}
+	}
+
+	seen := map[string]bool{}
+	table := &Table{Format: FormatJSON}
+	for _, object := range objects {
+		for key := range object {
+			if !seen[key] {
+				seen[key] = true
+				table.Columns = append(table.Columns, key)
+			}
+		}
+	}
+	sort.Strings(table.Columns)
+
+	for _, object := range objects {
+		row := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			row[i] = jsonText(object[column])
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+// jsonText convierte un valor JSON en el texto de una celda. Las listas de
+// textos se unen con comas; los objetos y otras listas se conservan como JSON.
+func jsonText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				data, _ := json.Marshal(v)
+				return string(data)
+			}
+			items = append(items, text)
+		}
+		return strings.Join(items, ", ")
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// Sample retorna el primer valor no vacío de una columna, como ejemplo
+// para la pantalla de mapeo
+func (t *Table) Sample(column int) string {
+	for _, row := range t.Rows {
+		if column < len(row) && strings.TrimSpace(row[column]) != "" {
+			return row[column]
+		}
+	}
+	return ""
+}
+
+// Line retorna el número con el que se reporta una fila: la línea del
+// archivo en un CSV o la posición del objeto en un JSON
+func (t *Table) Line(index int) int {
+	if t.Format == FormatCSV {
+		return index + 2
+	}
+	return index + 1
+}
@@ -0,0 +1,320 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// DiscogsBaseURL es la URL de la API pública de Discogs
+const DiscogsBaseURL = "https://api.discogs.com"
+
+// discogsInterval es el tiempo mínimo entre peticiones: Discogs permite
+// 60 por minuto con token
+const discogsInterval = time.Second
+
+// discogsArtistSuffix es el sufijo numérico que Discogs agrega para
+// distinguir artistas homónimos, p. ej. "Nirvana (2)"
+var discogsArtistSuffix = regexp.MustCompile(`\s+\(\d+\)$`)
+
+// Discogs busca releases en la API de Discogs usando un token personal
+// (https://www.discogs.com/settings/developers)
+type Discogs struct {
+	token   string
+	baseURL string
+	client  *http.Client
+	limiter *rateLimiter
+}
+
+// NewDiscogs crea un proveedor de Discogs. Un baseURL vacío usa la API pública.
+func NewDiscogs(token, baseURL string) *Discogs {
+	if baseURL == "" {
+		baseURL = DiscogsBaseURL
+	}
+	return &Discogs{
+		token:   token,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: defaultTimeout},
+		limiter: &rateLimiter{interval: discogsInterval},
+	}
+}
+
+// Name identifica al proveedor
+func (d *Discogs) Name() string {
+	return "discogs"
+}
+
+// Label retorna el nombre legible del proveedor
+func (d *Discogs) Label() string {
+	return "Discogs"
+}
+
+// discogsSearchResult es un resultado de /database/search
+type discogsSearchResult struct {
+	ID         int      `json:"id"`
+	Title      string   `json:"title"` // "Artista - Título"
+	Year       string   `json:"year"`
+	Country    string   `json:"country"`
+	Format     []string `json:"format"`
+	Label      []string `json:"label"`
+	Genre      []string `json:"genre"`
+	Style      []string `json:"style"`
+	Catno      string   `json:"catno"`
+	Barcode    []string `json:"barcode"`
+	CoverImage string   `json:"cover_image"`
+	URI        string   `json:"uri"`
+}
+
+// discogsRelease es la respuesta de /releases/{id}
+type discogsRelease struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Year    int    `json:"year"`
+	Country string `json:"country"`
+	URI     string `json:"uri"`
+	Artists []struct {
+		Name string `json:"name"`
+		Join string `json:"join"`
+	} `json:"artists"`
+	Labels []struct {
+		Name  string `json:"name"`
+		Catno string `json:"catno"`
+	} `json:"labels"`
+	Formats []struct {
+		Name         string   `json:"name"`
+		Descriptions []string `json:"descriptions"`
+	} `json:"formats"`
+	Genres    []string `json:"genres"`
+	Styles    []string `json:"styles"`
+	Tracklist []struct {
+		Position string `json:"position"`
+		Type     string `json:"type_"`
+		Title    string `json:"title"`
+		Duration string `json:"duration"`
+	} `json:"tracklist"`
+	Images []struct {
+		Type string `json:"type"`
+		URI  string `json:"uri"`
+	} `json:"images"`
+	Identifiers []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"identifiers"`
+}
+
+// Search busca releases por artista, título, catálogo y código de barras
+func (d *Discogs) Search(ctx context.Context, q Query) ([]*Release, error) {
+	params := url.Values{}
+	params.Set("type", "release")
+	params.Set("per_page", "10")
+	setParam(params, "artist", q.Artista)
+	setParam(params, "release_title", q.Titulo)
+	setParam(params, "catno", q.CatalogNumber)
+	setParam(params, "barcode", models.CompactIdentifier(q.Barcode))
+
+	var response struct {
+		Results []discogsSearchResult `json:"results"`
+	}
+	if err := d.get(ctx, "/database/search?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	releases := make([]*Release, 0, len(response.Results))
+	for _, result := range response.Results {
+		release := &Release{
+			Provider:      d.Name(),
+			ID:            strconv.Itoa(result.ID),
+			CatalogNumber: result.Catno,
+			Pais:          result.Country,
+			Generos:       result.Genre,
+			Estilos:       result.Style,
+			ArteURL:       result.CoverImage,
+			URL:           "https://www.discogs.com" + result.URI,
+		}
+		release.Artista, release.Titulo = splitDiscogsTitle(result.Title)
+		release.Anio, _ = strconv.Atoi(result.Year)
+		if len(result.Label) > 0 {
+			release.Sello = result.Label[0]
+		}
+		if len(result.Format) > 0 {
+			release.Formato = discogsFormat(result.Format[0], result.Format[1:])
+		}
+		release.addIdentifier(models.IdentifierDiscogs, release.ID)
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+// Release obtiene todos los datos de un release, incluido el tracklist
+func (d *Discogs) Release(ctx context.Context, id string) (*Release, error) {
+	if _, err := strconv.Atoi(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	var data discogsRelease
+	if err := d.get(ctx, "/releases/"+id, &data); err != nil {
+		return nil, err
+	}
+
+	release := &Release{
+		Provider: d.Name(),
+		ID:       strconv.Itoa(data.ID),
+		Titulo:   data.Title,
+		Anio:     data.Year,
+		Pais:     data.Country,
+		Generos:  data.Genres,
+		Estilos:  data.Styles,
+		URL:      data.URI,
+	}
+
+	var artista strings.Builder
+	for _, artist := range data.Artists {
+		artista.WriteString(discogsArtistSuffix.ReplaceAllString(artist.Name, ""))
+		switch artist.Join {
+		case "":
+		case ",":
+			artista.WriteString(", ")
+		default:
+			artista.WriteString(" " + artist.Join + " ")
+		}
+	}
+	release.Artista = strings.Join(strings.Fields(artista.String()), " ")
+
+	if len(data.Labels) > 0 {
+		release.Sello = data.Labels[0].Name
+		release.CatalogNumber = data.Labels[0].Catno
+	}
+	if len(data.Formats) > 0 {
+		release.Formato = discogsFormat(data.Formats[0].Name, data.Formats[0].Descriptions)
+	}
+
+	for _, track := range data.Tracklist {
+		// Los encabezados de lado ("heading") e índices no son canciones
+		if track.Type != "" && track.Type != "track" {
+			continue
+		}
+		release.Tracklist = append(release.Tracklist, models.Track{
+			Numero:   len(release.Tracklist) + 1,
+			Posicion: track.Position,
+			Titulo:   track.Title,
+			Duracion: track.Duration,
+		})
+	}
+	release.DuracionTotal = totalDuration(release.Tracklist)
+
+	for _, image := range data.Images {
+		if image.Type == "primary" || release.ArteURL == "" {
+			release.ArteURL = image.URI
+		}
+	}
+
+	release.addIdentifier(models.IdentifierDiscogs, release.ID)
+	for _, identifier := range data.Identifiers {
+		switch identifier.Type {
+		case "Barcode":
+			release.addIdentifier(models.IdentifierBarcode, identifier.Value)
+		case "Matrix / Runout":
+			release.addIdentifier(models.IdentifierMatrix, identifier.Value)
+		}
+	}
+
+	return release, nil
+}
+
+// MarketStats obtiene el precio más bajo y las copias a la venta de un
+// release en el marketplace de Discogs
+func (d *Discogs) MarketStats(ctx context.Context, id string) (*MarketStats, error) {
+	if _, err := strconv.Atoi(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	var data struct {
+		LowestPrice *struct {
+			Value    float64 `json:"value"`
+			Currency string  `json:"currency"`
+		} `json:"lowest_price"`
+		NumForSale int `json:"num_for_sale"`
+	}
+	if err := d.get(ctx, "/marketplace/stats/"+id, &data); err != nil {
+		return nil, err
+	}
+
+	stats := &MarketStats{EnVenta: data.NumForSale}
+	if data.LowestPrice != nil {
+		stats.PrecioMinimo = data.LowestPrice.Value
+		stats.Moneda = data.LowestPrice.Currency
+	}
+	return stats, nil
+}
+
+// get consulta un endpoint de la API de Discogs
+func (d *Discogs) get(ctx context.Context, path string, dest any) error {
+	if err := d.limiter.wait(ctx); err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	if d.token != "" {
+		headers["Authorization"] = "Discogs token=" + d.token
+	}
+
+	err := getJSON(ctx, d.client, d.baseURL+path, headers, dest)
+
+	var status *statusError
+	if errors.As(err, &status) {
+		switch status.Code {
+		case http.StatusNotFound:
+			return ErrNotFound
+		case http.StatusUnauthorized:
+			return fmt.Errorf("token de Discogs inválido o ausente")
+		case http.StatusTooManyRequests:
+			return fmt.Errorf("se alcanzó el límite de peticiones de Discogs, intenta en un minuto")
+		}
+	}
+	return err
+}
+
+// splitDiscogsTitle separa el título "Artista - Título" de los resultados de búsqueda
+func splitDiscogsTitle(title string) (artista, titulo string) {
+	artista, titulo, ok := strings.Cut(title, " - ")
+	if !ok {
+		return "", title
+	}
+	return discogsArtistSuffix.ReplaceAllString(artista, ""), titulo
+}
+
+// discogsFormat traduce el formato de Discogs a los formatos del formulario
+func discogsFormat(name string, descriptions []string) string {
+	switch name {
+	case "CD", "Cassette":
+		return name
+	case "File":
+		return "Digital"
+	case "Vinyl":
+		for _, description := range descriptions {
+			switch description {
+			case "LP", "EP":
+				return description
+			case "Single", `7"`:
+				return "Single"
+			}
+		}
+		return "LP"
+	}
+	return name
+}
+
+// setParam agrega un parámetro de query si no está vacío
+func setParam(params url.Values, key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		params.Set(key, value)
+	}
+}
@@ -0,0 +1,226 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// newTestDiscogs crea un proveedor de Discogs contra un servidor local
+func newTestDiscogs(t *testing.T, token string, api http.HandlerFunc) *Discogs {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	return NewDiscogs(token, server.URL+"/")
+}
+
+func TestDiscogsSearch(t *testing.T) {
+	d := newTestDiscogs(t, "secreto", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/database/search" {
+			t.Errorf("ruta = %q, se esperaba /database/search", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Discogs token=secreto" {
+			t.Errorf("Authorization = %q", got)
+		}
+		query := r.URL.Query()
+		for key, want := range map[string]string{
+			"type":          "release",
+			"artist":        "Nirvana",
+			"release_title": "Nevermind",
+			"barcode":       "720642442517",
+		} {
+			if got := query.Get(key); got != want {
+				t.Errorf("%s = %q, se esperaba %q", key, got, want)
+			}
+		}
+		if query.Has("catno") {
+			t.Errorf("los criterios vacíos no se deben enviar")
+		}
+		w.Write([]byte(`{"results": [{
+			"id": 367113,
+			"title": "Nirvana (2) - Nevermind",
+			"year": "1991",
+			"country": "US",
+			"format": ["Vinyl", "LP", "Album"],
+			"label": ["DGC", "Sub Pop"],
+			"genre": ["Rock"],
+			"style": ["Grunge"],
+			"catno": "DGC-24425",
+			"cover_image": "https://img.discogs.test/nevermind.jpg",
+			"uri": "/release/367113-Nirvana-Nevermind"
+		}, {
+			"id": 2,
+			"title": "Sin separador",
+			"format": ["Vinyl", "7\""]
+		}]}`))
+	})
+
+	releases, err := d.Search(context.Background(), Query{
+		Artista: " Nirvana ",
+		Titulo:  "Nevermind",
+		Barcode: "7 20642 44251 7",
+	})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("se obtuvieron %d releases, se esperaban 2", len(releases))
+	}
+
+	got := releases[0]
+	if got.Provider != "discogs" || got.ID != "367113" || got.Artista != "Nirvana" || got.Titulo != "Nevermind" ||
+		got.Anio != 1991 || got.Sello != "DGC" || got.CatalogNumber != "DGC-24425" || got.Formato != "LP" ||
+		got.Pais != "US" || got.ArteURL != "https://img.discogs.test/nevermind.jpg" ||
+		got.URL != "https://www.discogs.com/release/367113-Nirvana-Nevermind" {
+		t.Errorf("release = %+v", *got)
+	}
+	assertIdentifiers(t, got, []ReleaseIdentifier{{Tipo: models.IdentifierDiscogs, Valor: "367113"}})
+
+	other := releases[1]
+	if other.Artista != "" || other.Titulo != "Sin separador" || other.Formato != "Single" {
+		t.Errorf("release sin separador = %+v", *other)
+	}
+}
+
+const discogsReleaseResponse = `{
+	"id": 367113,
+	"title": "Nevermind",
+	"year": 1991,
+	"country": "US",
+	"uri": "https://www.discogs.com/release/367113-Nirvana-Nevermind",
+	"artists": [
+		{"name": "Nirvana (2)", "join": "&"},
+		{"name": "Invitado", "join": ","},
+		{"name": "Otro", "join": ""}
+	],
+	"labels": [{"name": "DGC", "catno": "DGC-24425"}],
+	"formats": [{"name": "Vinyl", "descriptions": ["LP", "Album"]}],
+	"genres": ["Rock"],
+	"styles": ["Grunge", "Alternative Rock"],
+	"tracklist": [
+		{"position": "", "type_": "heading", "title": "Lado A", "duration": ""},
+		{"position": "A1", "type_": "track", "title": "Smells Like Teen Spirit", "duration": "5:01"},
+		{"position": "A2", "type_": "track", "title": "In Bloom", "duration": "4:14"}
+	],
+	"images": [
+		{"type": "secondary", "uri": "https://img.discogs.test/back.jpg"},
+		{"type": "primary", "uri": "https://img.discogs.test/front.jpg"}
+	],
+	"identifiers": [
+		{"type": "Barcode", "value": "7 20642 44251 7"},
+		{"type": "Barcode", "value": "no es un código"},
+		{"type": "Matrix / Runout", "value": "DGC-24425-A  1"},
+		{"type": "Rights Society", "value": "BIEM"}
+	]
+}`
+
+func TestDiscogsRelease(t *testing.T) {
+	d := newTestDiscogs(t, "", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases/367113" {
+			t.Errorf("ruta = %q, se esperaba /releases/367113", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("sin token no se debe enviar Authorization, se envió %q", got)
+		}
+		w.Write([]byte(discogsReleaseResponse))
+	})
+
+	release, err := d.Release(context.Background(), "367113")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if release.Artista != "Nirvana & Invitado, Otro" {
+		t.Errorf("artista = %q", release.Artista)
+	}
+	if release.Sello != "DGC" || release.CatalogNumber != "DGC-24425" || release.Formato != "LP" || release.Anio != 1991 {
+		t.Errorf("release = %+v", *release)
+	}
+
+	wantTracks := []models.Track{
+		{Numero: 1, Posicion: "A1", Titulo: "Smells Like Teen Spirit", Duracion: "5:01"},
+		{Numero: 2, Posicion: "A2", Titulo: "In Bloom", Duracion: "4:14"},
+	}
+	if len(release.Tracklist) != len(wantTracks) {
+		t.Fatalf("tracklist = %+v, se esperaba %+v", release.Tracklist, wantTracks)
+	}
+	for i, track := range release.Tracklist {
+		if track != wantTracks[i] {
+			t.Errorf("track %d = %+v, se esperaba %+v", i, track, wantTracks[i])
+		}
+	}
+	if release.DuracionTotal != "9:15" {
+		t.Errorf("duración total = %q, se esperaba 9:15", release.DuracionTotal)
+	}
+
+	if release.ArteURL != "https://img.discogs.test/front.jpg" {
+		t.Errorf("arte = %q, se esperaba la imagen primaria", release.ArteURL)
+	}
+
+	assertIdentifiers(t, release, []ReleaseIdentifier{
+		{Tipo: models.IdentifierDiscogs, Valor: "367113"},
+		{Tipo: models.IdentifierBarcode, Valor: "720642442517"},
+		{Tipo: models.IdentifierMatrix, Valor: "DGC-24425-A 1"},
+	})
+}
+
+func TestDiscogsMarketStats(t *testing.T) {
+	d := newTestDiscogs(t, "secreto", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/marketplace/stats/367113" {
+			t.Errorf("ruta = %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"lowest_price": {"value": 24.5, "currency": "USD"}, "num_for_sale": 12}`))
+	})
+
+	stats, err := d.MarketStats(context.Background(), "367113")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if *stats != (MarketStats{PrecioMinimo: 24.5, Moneda: "USD", EnVenta: 12}) {
+		t.Errorf("valores de mercado = %+v", *stats)
+	}
+}
+
+func TestDiscogsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    error
+		message string
+	}{
+		{name: "release inexistente", status: http.StatusNotFound, want: ErrNotFound},
+		{name: "token inválido", status: http.StatusUnauthorized, message: "token de Discogs"},
+		{name: "límite de peticiones", status: http.StatusTooManyRequests, message: "límite de peticiones"},
+		{name: "error del servidor", status: http.StatusBadGateway, message: "502"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDiscogs(t, "secreto", func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "error", tt.status)
+			})
+
+			_, err := d.Release(context.Background(), "367113")
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, se esperaba %v", err, tt.want)
+			}
+			if tt.message != "" && (err == nil || !strings.Contains(err.Error(), tt.message)) {
+				t.Errorf("error = %v, se esperaba que mencionara %q", err, tt.message)
+			}
+		})
+	}
+
+	t.Run("ID inválido no consulta la API", func(t *testing.T) {
+		d := newTestDiscogs(t, "secreto", func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("consulta inesperada: %s", r.URL)
+		})
+		if _, err := d.Release(context.Background(), "abc"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("error = %v, se esperaba ErrNotFound", err)
+		}
+	})
+}
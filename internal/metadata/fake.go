@@ -0,0 +1,128 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// Fake es un proveedor en memoria, útil para desarrollar sin conexión ni
+// token y para probar el flujo de búsqueda
+type Fake struct {
+	releases []*Release
+}
+
+// NewFake crea un proveedor en memoria con los releases indicados. Sin
+// releases usa un pequeño catálogo de ejemplo.
+func NewFake(releases ...*Release) *Fake {
+	if len(releases) == 0 {
+		releases = sampleReleases()
+	}
+	for _, release := range releases {
+		release.Provider = "fake"
+	}
+	return &Fake{releases: releases}
+}
+
+// Name identifica al proveedor
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+// Label retorna el nombre legible del proveedor
+func (f *Fake) Label() string {
+	return "Catálogo de ejemplo"
+}
+
+// Search retorna los releases que contienen cada criterio de la búsqueda
+func (f *Fake) Search(ctx context.Context, q Query) ([]*Release, error) {
+	var results []*Release
+	for _, release := range f.releases {
+		if matches(release.Artista, q.Artista) &&
+			matches(release.Titulo, q.Titulo) &&
+			matches(release.CatalogNumber, q.CatalogNumber) &&
+			hasBarcode(release, q.Barcode) {
+			results = append(results, release)
+		}
+	}
+	return results, nil
+}
+
+// Release retorna un release por su ID
+func (f *Fake) Release(ctx context.Context, id string) (*Release, error) {
+	for _, release := range f.releases {
+		if release.ID == id {
+			return release, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// matches verifica si value contiene el criterio, sin distinguir mayúsculas
+func matches(value, criterio string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(strings.TrimSpace(criterio)))
+}
+
+// hasBarcode verifica si el release tiene el código de barras buscado
+func hasBarcode(release *Release, barcode string) bool {
+	barcode = models.CompactIdentifier(barcode)
+	if barcode == "" {
+		return true
+	}
+	for _, identifier := range release.Identifiers {
+		if identifier.Tipo == models.IdentifierBarcode && identifier.Valor == barcode {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleReleases retorna el catálogo de ejemplo del proveedor en memoria
+func sampleReleases() []*Release {
+	nevermind := &Release{
+		ID:            "1",
+		Titulo:        "Nevermind",
+		Artista:       "Nirvana",
+		Sello:         "DGC",
+		CatalogNumber: "DGC-24425",
+		Anio:          1991,
+		Formato:       "LP",
+		Pais:          "US",
+		Generos:       []string{"Rock"},
+		Estilos:       []string{"Grunge", "Alternative Rock"},
+		Tracklist: []models.Track{
+			{Numero: 1, Titulo: "Smells Like Teen Spirit", Duracion: "5:01"},
+			{Numero: 2, Titulo: "In Bloom", Duracion: "4:14"},
+			{Numero: 3, Titulo: "Come As You Are", Duracion: "3:39"},
+			{Numero: 4, Titulo: "Breed", Duracion: "3:03"},
+		},
+	}
+	nevermind.addIdentifier(models.IdentifierBarcode, "720642442517")
+
+	blueTrain := &Release{
+		ID:            "2",
+		Titulo:        "Blue Train",
+		Artista:       "John Coltrane",
+		Sello:         "Blue Note",
+		CatalogNumber: "BLP 1577",
+		Anio:          1957,
+		Formato:       "LP",
+		Pais:          "US",
+		Generos:       []string{"Jazz"},
+		Estilos:       []string{"Hard Bop"},
+		Tracklist: []models.Track{
+			{Numero: 1, Titulo: "Blue Train", Duracion: "10:43"},
+			{Numero: 2, Titulo: "Moment's Notice", Duracion: "9:10"},
+			{Numero: 3, Titulo: "Locomotion", Duracion: "7:14"},
+			{Numero: 4, Titulo: "I'm Old Fashioned", Duracion: "7:58"},
+			{Numero: 5, Titulo: "Lazy Bird", Duracion: "7:00"},
+		},
+	}
+
+	releases := []*Release{nevermind, blueTrain}
+	for _, release := range releases {
+		release.DuracionTotal = totalDuration(release.Tracklist)
+	}
+	return releases
+}
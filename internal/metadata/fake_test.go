@@ -0,0 +1,64 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFakeSearch(t *testing.T) {
+	fake := NewFake()
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{name: "por artista sin distinguir mayúsculas", query: Query{Artista: "nirvana"}, want: []string{"1"}},
+		{name: "por catálogo", query: Query{CatalogNumber: "BLP 1577"}, want: []string{"2"}},
+		{name: "por código de barras con espacios", query: Query{Barcode: "7 20642 44251 7"}, want: []string{"1"}},
+		{name: "todos los criterios deben coincidir", query: Query{Artista: "Nirvana", Titulo: "Blue Train"}},
+		{name: "sin criterios retorna todo", query: Query{}, want: []string{"1", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			releases, err := fake.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			var ids []string
+			for _, release := range releases {
+				ids = append(ids, release.ID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("releases = %v, se esperaba %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Errorf("releases = %v, se esperaba %v", ids, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFakeRelease(t *testing.T) {
+	fake := NewFake(&Release{ID: "10", Titulo: "Kind of Blue", Artista: "Miles Davis"})
+
+	release, err := fake.Release(context.Background(), "10")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if release.Provider != "fake" || release.Titulo != "Kind of Blue" {
+		t.Errorf("release = %+v", *release)
+	}
+
+	if _, err := fake.Release(context.Background(), "1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, se esperaba ErrNotFound", err)
+	}
+
+	if found := Find([]Provider{NewMusicBrainz("", ""), fake}, "fake"); found != fake {
+		t.Errorf("Find no encontró el proveedor en memoria")
+	}
+}
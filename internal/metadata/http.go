@@ -0,0 +1,88 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// UserAgent identifica a la aplicación ante las APIs externas, que exigen
+// un User-Agent descriptivo con una forma de contacto, con el formato
+// "Aplicación/versión ( contacto )" que pide MusicBrainz
+const UserAgent = "Vinilo/1.0 ( https://github.com/rodrwan/vinilo )"
+
+// defaultTimeout es el tiempo máximo de espera de cada petición
+const defaultTimeout = 10 * time.Second
+
+// statusError es una respuesta HTTP distinta de 200
+type statusError struct {
+	Code int
+	Body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("respuesta HTTP %d: %s", e.Code, e.Body)
+}
+
+// getJSON hace un GET y decodifica la respuesta JSON en dest
+func getJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, dest any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error creando petición: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error consultando %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{Code: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("error decodificando respuesta de %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
+
+// rateLimiter espacia las peticiones a una API que limita cuántas se
+// pueden hacer por segundo
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait bloquea hasta que se pueda hacer la siguiente petición o se
+// cancele el contexto
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	delay := max(l.next.Sub(now), 0)
+	l.next = now.Add(delay + l.interval)
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,334 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// URLs de las APIs públicas de MusicBrainz y Cover Art Archive
+const (
+	MusicBrainzBaseURL = "https://musicbrainz.org/ws/2"
+	CoverArtBaseURL    = "https://coverartarchive.org"
+)
+
+// musicBrainzInterval es el tiempo mínimo entre peticiones: MusicBrainz
+// permite en promedio una por segundo por cliente
+const musicBrainzInterval = time.Second
+
+// maxMusicBrainzGenres es la cantidad de géneros que se toman de un release
+const maxMusicBrainzGenres = 5
+
+// MusicBrainz busca releases en MusicBrainz y su arte en Cover Art
+// Archive. No necesita token, pero respeta el límite de peticiones y el
+// User-Agent que exige el servicio.
+type MusicBrainz struct {
+	baseURL     string
+	coverArtURL string
+	client      *http.Client
+	limiter     *rateLimiter
+}
+
+// NewMusicBrainz crea un proveedor de MusicBrainz. Las URLs vacías usan
+// las APIs públicas.
+func NewMusicBrainz(baseURL, coverArtURL string) *MusicBrainz {
+	if baseURL == "" {
+		baseURL = MusicBrainzBaseURL
+	}
+	if coverArtURL == "" {
+		coverArtURL = CoverArtBaseURL
+	}
+	return &MusicBrainz{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		coverArtURL: strings.TrimSuffix(coverArtURL, "/"),
+		client:      &http.Client{Timeout: defaultTimeout},
+		limiter:     &rateLimiter{interval: musicBrainzInterval},
+	}
+}
+
+// Name identifica al proveedor
+func (m *MusicBrainz) Name() string {
+	return "musicbrainz"
+}
+
+// Label retorna el nombre legible del proveedor
+func (m *MusicBrainz) Label() string {
+	return "MusicBrainz"
+}
+
+// musicBrainzRelease es un release de /release, tanto en la búsqueda
+// como en la consulta por MBID
+type musicBrainzRelease struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Date         string `json:"date"` // "1973-03-01", "1973" o vacío
+	Country      string `json:"country"`
+	Barcode      string `json:"barcode"`
+	ArtistCredit []struct {
+		Name       string `json:"name"`
+		JoinPhrase string `json:"joinphrase"`
+	} `json:"artist-credit"`
+	LabelInfo []struct {
+		CatalogNumber string `json:"catalog-number"`
+		Label         *struct {
+			Name string `json:"name"`
+		} `json:"label"`
+	} `json:"label-info"`
+	ReleaseGroup struct {
+		PrimaryType string `json:"primary-type"`
+	} `json:"release-group"`
+	Media []struct {
+		Format string `json:"format"`
+		Tracks []struct {
+			Position int    `json:"position"`
+			Number   string `json:"number"` // "A1", "B2" o "1"
+			Title    string `json:"title"`
+			Length   int64  `json:"length"` // milisegundos
+		} `json:"tracks"`
+	} `json:"media"`
+	Genres []struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	} `json:"genres"`
+	CoverArtArchive struct {
+		Front bool `json:"front"`
+	} `json:"cover-art-archive"`
+}
+
+// Search busca releases por artista, título, catálogo y código de barras
+func (m *MusicBrainz) Search(ctx context.Context, q Query) ([]*Release, error) {
+	var terms []string
+	addTerm := func(field, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			terms = append(terms, field+":"+luceneQuote(value))
+		}
+	}
+	addTerm("artist", q.Artista)
+	addTerm("release", q.Titulo)
+	addTerm("catno", q.CatalogNumber)
+	addTerm("barcode", models.CompactIdentifier(q.Barcode))
+
+	params := url.Values{}
+	params.Set("query", strings.Join(terms, " AND "))
+	params.Set("limit", "10")
+	params.Set("fmt", "json")
+
+	var response struct {
+		Releases []musicBrainzRelease `json:"releases"`
+	}
+	if err := m.get(ctx, "/release/?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	releases := make([]*Release, 0, len(response.Releases))
+	for _, data := range response.Releases {
+		releases = append(releases, m.convert(data))
+	}
+	return releases, nil
+}
+
+// Release obtiene todos los datos de un release por su MBID, incluido el
+// tracklist y el arte de Cover Art Archive
+func (m *MusicBrainz) Release(ctx context.Context, id string) (*Release, error) {
+	mbid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	params := url.Values{}
+	params.Set("inc", "artist-credits labels recordings release-groups genres")
+	params.Set("fmt", "json")
+
+	var data musicBrainzRelease
+	if err := m.get(ctx, "/release/"+mbid.String()+"?"+params.Encode(), &data); err != nil {
+		return nil, err
+	}
+
+	release := m.convert(data)
+
+	for _, medium := range data.Media {
+		for _, track := range medium.Tracks {
+			posicion := track.Number
+			if _, err := strconv.Atoi(posicion); err == nil {
+				// Las posiciones numéricas ("1", "2") no agregan información
+				posicion = ""
+			}
+			duracion := ""
+			if track.Length > 0 {
+				duracion = models.FormatTrackDuration(time.Duration(track.Length) * time.Millisecond)
+			}
+			release.Tracklist = append(release.Tracklist, models.Track{
+				Numero:   len(release.Tracklist) + 1,
+				Posicion: posicion,
+				Titulo:   track.Title,
+				Duracion: duracion,
+			})
+		}
+	}
+	release.DuracionTotal = totalDuration(release.Tracklist)
+
+	genres := data.Genres
+	sort.SliceStable(genres, func(i, j int) bool { return genres[i].Count > genres[j].Count })
+	for i, genre := range genres {
+		if i == maxMusicBrainzGenres {
+			break
+		}
+		release.Generos = append(release.Generos, capitalizeWords(genre.Name))
+	}
+
+	// Si Cover Art Archive falla el release se retorna sin arte
+	if data.CoverArtArchive.Front {
+		if arte, err := m.frontCover(ctx, release.ID); err == nil {
+			release.ArteURL = arte
+		}
+	}
+
+	return release, nil
+}
+
+// convert traduce un release de MusicBrainz a un Release
+func (m *MusicBrainz) convert(data musicBrainzRelease) *Release {
+	release := &Release{
+		Provider: m.Name(),
+		ID:       data.ID,
+		Titulo:   data.Title,
+		Pais:     data.Country,
+		URL:      "https://musicbrainz.org/release/" + data.ID,
+	}
+
+	var artista strings.Builder
+	for _, credit := range data.ArtistCredit {
+		artista.WriteString(credit.Name + credit.JoinPhrase)
+	}
+	release.Artista = strings.TrimSpace(artista.String())
+
+	if year, _, _ := strings.Cut(data.Date, "-"); year != "" {
+		release.Anio, _ = strconv.Atoi(year)
+	}
+
+	for _, info := range data.LabelInfo {
+		if release.Sello == "" && info.Label != nil {
+			release.Sello = info.Label.Name
+		}
+		if release.CatalogNumber == "" && info.CatalogNumber != "[none]" {
+			release.CatalogNumber = info.CatalogNumber
+		}
+	}
+
+	if len(data.Media) > 0 {
+		release.Formato = musicBrainzFormat(data.Media[0].Format, data.ReleaseGroup.PrimaryType)
+	}
+
+	release.addIdentifier(models.IdentifierMusicBrainz, data.ID)
+	release.addIdentifier(models.IdentifierBarcode, data.Barcode)
+
+	return release
+}
+
+// frontCover retorna la URL de la portada de un release en Cover Art
+// Archive, o vacío si no tiene
+func (m *MusicBrainz) frontCover(ctx context.Context, mbid string) (string, error) {
+	var response struct {
+		Images []struct {
+			Front      bool              `json:"front"`
+			Image      string            `json:"image"`
+			Thumbnails map[string]string `json:"thumbnails"`
+		} `json:"images"`
+	}
+
+	err := getJSON(ctx, m.client, m.coverArtURL+"/release/"+mbid, nil, &response)
+	var status *statusError
+	if errors.As(err, &status) && status.Code == http.StatusNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, image := range response.Images {
+		if !image.Front {
+			continue
+		}
+		// Las miniaturas de 500px bastan para la colección y pesan mucho
+		// menos que el original
+		for _, size := range []string{"500", "large"} {
+			if thumbnail := image.Thumbnails[size]; thumbnail != "" {
+				return thumbnail, nil
+			}
+		}
+		return image.Image, nil
+	}
+	return "", nil
+}
+
+// get consulta un endpoint de la API de MusicBrainz respetando el límite
+// de peticiones
+func (m *MusicBrainz) get(ctx context.Context, path string, dest any) error {
+	if err := m.limiter.wait(ctx); err != nil {
+		return err
+	}
+
+	err := getJSON(ctx, m.client, m.baseURL+path, nil, dest)
+
+	var status *statusError
+	if errors.As(err, &status) {
+		switch status.Code {
+		case http.StatusNotFound, http.StatusBadRequest:
+			return ErrNotFound
+		case http.StatusServiceUnavailable, http.StatusTooManyRequests:
+			return fmt.Errorf("se alcanzó el límite de peticiones de MusicBrainz, intenta en unos segundos")
+		}
+	}
+	return err
+}
+
+// musicBrainzFormat traduce el formato del medio y el tipo del release
+// group de MusicBrainz a los formatos del formulario
+func musicBrainzFormat(format, primaryType string) string {
+	switch {
+	case strings.Contains(format, "Vinyl"):
+		switch {
+		case strings.HasPrefix(format, `7"`) || primaryType == "Single":
+			return "Single"
+		case primaryType == "EP":
+			return "EP"
+		}
+		return "LP"
+	case strings.Contains(format, "CD"):
+		return "CD"
+	case format == "Cassette":
+		return "Cassette"
+	case format == "Digital Media":
+		return "Digital"
+	}
+	return format
+}
+
+// luceneQuote escribe un valor como frase de la sintaxis de búsqueda de
+// MusicBrainz (Lucene)
+func luceneQuote(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return `"` + value + `"`
+}
+
+// capitalizeWords pone en mayúscula la primera letra de cada palabra:
+// MusicBrainz escribe los géneros en minúsculas ("progressive rock")
+func capitalizeWords(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,348 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+const testMBID = "b84ee12a-09ef-421b-82de-0441a926375b"
+
+// newTestMusicBrainz crea un proveedor de MusicBrainz contra dos servidores
+// locales que hacen de MusicBrainz y de Cover Art Archive
+func newTestMusicBrainz(t *testing.T, api, coverArt http.HandlerFunc) *MusicBrainz {
+	t.Helper()
+	apiServer := httptest.NewServer(api)
+	t.Cleanup(apiServer.Close)
+	coverArtServer := httptest.NewServer(coverArt)
+	t.Cleanup(coverArtServer.Close)
+	return NewMusicBrainz(apiServer.URL+"/ws/2/", coverArtServer.URL)
+}
+
+// noCoverArt falla la prueba si se consulta Cover Art Archive
+func noCoverArt(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("consulta inesperada a Cover Art Archive: %s", r.URL)
+		http.NotFound(w, r)
+	}
+}
+
+const musicBrainzSearchResponse = `{
+	"releases": [{
+		"id": "` + testMBID + `",
+		"title": "The Dark Side of the Moon",
+		"date": "1973-03-01",
+		"country": "GB",
+		"barcode": "5099902894027",
+		"artist-credit": [{"name": "Pink Floyd", "joinphrase": ""}],
+		"label-info": [
+			{"catalog-number": "[none]", "label": null},
+			{"catalog-number": "SHVL 804", "label": {"name": "Harvest"}}
+		],
+		"release-group": {"primary-type": "Album"},
+		"media": [{"format": "12\" Vinyl"}]
+	}, {
+		"id": "not-a-uuid",
+		"title": "Sin datos",
+		"artist-credit": [
+			{"name": "Simon", "joinphrase": " & "},
+			{"name": "Garfunkel", "joinphrase": ""}
+		],
+		"release-group": {"primary-type": "Single"},
+		"media": [{"format": "7\" Vinyl"}]
+	}]
+}`
+
+func TestMusicBrainzSearch(t *testing.T) {
+	var query string
+	mb := newTestMusicBrainz(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws/2/release/" {
+			t.Errorf("ruta = %q, se esperaba /ws/2/release/", r.URL.Path)
+		}
+		if got := r.Header.Get("User-Agent"); got != UserAgent {
+			t.Errorf("User-Agent = %q, se esperaba %q", got, UserAgent)
+		}
+		if got := r.URL.Query().Get("fmt"); got != "json" {
+			t.Errorf("fmt = %q, se esperaba json", got)
+		}
+		query = r.URL.Query().Get("query")
+		w.Write([]byte(musicBrainzSearchResponse))
+	}, noCoverArt(t))
+
+	releases, err := mb.Search(context.Background(), Query{
+		Artista: "Pink Floyd",
+		Titulo:  `The "Dark" Side`,
+		Barcode: "5 099902 894027",
+	})
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	wantQuery := `artist:"Pink Floyd" AND release:"The \"Dark\" Side" AND barcode:"5099902894027"`
+	if query != wantQuery {
+		t.Errorf("query = %q, se esperaba %q", query, wantQuery)
+	}
+
+	if len(releases) != 2 {
+		t.Fatalf("se obtuvieron %d releases, se esperaban 2", len(releases))
+	}
+
+	got := releases[0]
+	want := Release{
+		Provider:      "musicbrainz",
+		ID:            testMBID,
+		Titulo:        "The Dark Side of the Moon",
+		Artista:       "Pink Floyd",
+		Sello:         "Harvest",
+		CatalogNumber: "SHVL 804",
+		Anio:          1973,
+		Formato:       "LP",
+		Pais:          "GB",
+		URL:           "https://musicbrainz.org/release/" + testMBID,
+	}
+	if got.Provider != want.Provider || got.ID != want.ID || got.Titulo != want.Titulo ||
+		got.Artista != want.Artista || got.Sello != want.Sello || got.CatalogNumber != want.CatalogNumber ||
+		got.Anio != want.Anio || got.Formato != want.Formato || got.Pais != want.Pais || got.URL != want.URL {
+		t.Errorf("release = %+v, se esperaba %+v", *got, want)
+	}
+	assertIdentifiers(t, got, []ReleaseIdentifier{
+		{Tipo: models.IdentifierMusicBrainz, Valor: testMBID},
+		{Tipo: models.IdentifierBarcode, Valor: "5099902894027"},
+	})
+
+	// Los créditos se unen con su joinphrase y los datos inválidos se omiten
+	other := releases[1]
+	if other.Artista != "Simon & Garfunkel" {
+		t.Errorf("artista = %q, se esperaba %q", other.Artista, "Simon & Garfunkel")
+	}
+	if other.Formato != "Single" || other.Anio != 0 {
+		t.Errorf("formato y año = %q, %d, se esperaba Single y 0", other.Formato, other.Anio)
+	}
+	assertIdentifiers(t, other, nil)
+}
+
+const musicBrainzReleaseResponse = `{
+	"id": "` + testMBID + `",
+	"title": "The Dark Side of the Moon",
+	"date": "1973",
+	"artist-credit": [{"name": "Pink Floyd", "joinphrase": ""}],
+	"release-group": {"primary-type": "Album"},
+	"media": [
+		{"format": "12\" Vinyl", "tracks": [
+			{"position": 1, "number": "A1", "title": "Speak to Me", "length": 90000},
+			{"position": 2, "number": "A2", "title": "Breathe", "length": 163000}
+		]},
+		{"format": "12\" Vinyl", "tracks": [
+			{"position": 1, "number": "1", "title": "Money", "length": 382000}
+		]}
+	],
+	"genres": [
+		{"name": "rock", "count": 2},
+		{"name": "progressive rock", "count": 9},
+		{"name": "art rock", "count": 5},
+		{"name": "psychedelic rock", "count": 4},
+		{"name": "space rock", "count": 3},
+		{"name": "blues rock", "count": 1}
+	],
+	"cover-art-archive": {"front": %t}
+}`
+
+// musicBrainzReleaseHandler responde la consulta de un release con o sin
+// portada en Cover Art Archive
+func musicBrainzReleaseHandler(t *testing.T, front bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ws/2/release/"+testMBID {
+			t.Errorf("ruta = %q, se esperaba el release %s", r.URL.Path, testMBID)
+		}
+		if inc := r.URL.Query().Get("inc"); !strings.Contains(inc, "recordings") {
+			t.Errorf("inc = %q, se esperaba que incluyera recordings", inc)
+		}
+		fmt.Fprintf(w, musicBrainzReleaseResponse, front)
+	}
+}
+
+func TestMusicBrainzRelease(t *testing.T) {
+	mb := newTestMusicBrainz(t, musicBrainzReleaseHandler(t, true), func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/release/"+testMBID {
+			t.Errorf("ruta de Cover Art Archive = %q", r.URL.Path)
+		}
+		w.Write([]byte(`{"images": [
+			{"front": false, "image": "https://caa.test/back.jpg", "thumbnails": {"500": "https://caa.test/back-500.jpg"}},
+			{"front": true, "image": "https://caa.test/front.jpg", "thumbnails": {"250": "https://caa.test/front-250.jpg", "500": "https://caa.test/front-500.jpg"}}
+		]}`))
+	})
+
+	release, err := mb.Release(context.Background(), strings.ToUpper(testMBID))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	wantTracks := []models.Track{
+		{Numero: 1, Posicion: "A1", Titulo: "Speak to Me", Duracion: "1:30"},
+		{Numero: 2, Posicion: "A2", Titulo: "Breathe", Duracion: "2:43"},
+		{Numero: 3, Posicion: "", Titulo: "Money", Duracion: "6:22"},
+	}
+	if len(release.Tracklist) != len(wantTracks) {
+		t.Fatalf("tracklist = %+v, se esperaba %+v", release.Tracklist, wantTracks)
+	}
+	for i, track := range release.Tracklist {
+		if track != wantTracks[i] {
+			t.Errorf("track %d = %+v, se esperaba %+v", i, track, wantTracks[i])
+		}
+	}
+	if release.DuracionTotal != "10:35" {
+		t.Errorf("duración total = %q, se esperaba 10:35", release.DuracionTotal)
+	}
+
+	wantGenres := []string{"Progressive Rock", "Art Rock", "Psychedelic Rock", "Space Rock", "Rock"}
+	if strings.Join(release.Generos, "|") != strings.Join(wantGenres, "|") {
+		t.Errorf("géneros = %v, se esperaba %v", release.Generos, wantGenres)
+	}
+
+	if release.ArteURL != "https://caa.test/front-500.jpg" {
+		t.Errorf("arte = %q, se esperaba la miniatura de 500px de la portada", release.ArteURL)
+	}
+	if release.Anio != 1973 {
+		t.Errorf("año = %d, se esperaba 1973", release.Anio)
+	}
+}
+
+func TestMusicBrainzCoverArtFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		coverArt http.HandlerFunc
+		want     string
+	}{
+		{
+			name: "miniatura large sin 500",
+			coverArt: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"images": [{"front": true, "image": "https://caa.test/front.jpg", "thumbnails": {"large": "https://caa.test/front-large.jpg"}}]}`))
+			},
+			want: "https://caa.test/front-large.jpg",
+		},
+		{
+			name: "imagen original sin miniaturas",
+			coverArt: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"images": [{"front": true, "image": "https://caa.test/front.jpg"}]}`))
+			},
+			want: "https://caa.test/front.jpg",
+		},
+		{
+			name: "sin portada",
+			coverArt: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"images": [{"front": false, "image": "https://caa.test/back.jpg"}]}`))
+			},
+		},
+		{
+			name:     "release sin arte en Cover Art Archive",
+			coverArt: http.NotFound,
+		},
+		{
+			name: "Cover Art Archive caído",
+			coverArt: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "error", http.StatusInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := newTestMusicBrainz(t, musicBrainzReleaseHandler(t, true), tt.coverArt)
+
+			release, err := mb.Release(context.Background(), testMBID)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if release.ArteURL != tt.want {
+				t.Errorf("arte = %q, se esperaba %q", release.ArteURL, tt.want)
+			}
+			if len(release.Tracklist) != 3 {
+				t.Errorf("el release debe retornarse completo aunque falte el arte")
+			}
+		})
+	}
+}
+
+func TestMusicBrainzReleaseWithoutFrontSkipsCoverArt(t *testing.T) {
+	mb := newTestMusicBrainz(t, musicBrainzReleaseHandler(t, false), noCoverArt(t))
+
+	release, err := mb.Release(context.Background(), testMBID)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if release.ArteURL != "" {
+		t.Errorf("arte = %q, se esperaba vacío", release.ArteURL)
+	}
+}
+
+func TestMusicBrainzErrors(t *testing.T) {
+	t.Run("MBID inválido no consulta la API", func(t *testing.T) {
+		mb := newTestMusicBrainz(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("consulta inesperada: %s", r.URL)
+		}, noCoverArt(t))
+		if _, err := mb.Release(context.Background(), "123"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("error = %v, se esperaba ErrNotFound", err)
+		}
+	})
+
+	t.Run("release inexistente", func(t *testing.T) {
+		mb := newTestMusicBrainz(t, http.NotFound, noCoverArt(t))
+		if _, err := mb.Release(context.Background(), testMBID); !errors.Is(err, ErrNotFound) {
+			t.Errorf("error = %v, se esperaba ErrNotFound", err)
+		}
+	})
+
+	t.Run("límite de peticiones", func(t *testing.T) {
+		mb := newTestMusicBrainz(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "slow down", http.StatusServiceUnavailable)
+		}, noCoverArt(t))
+		_, err := mb.Search(context.Background(), Query{Artista: "Pink Floyd"})
+		if err == nil || !strings.Contains(err.Error(), "límite de peticiones") {
+			t.Errorf("error = %v, se esperaba el aviso del límite de peticiones", err)
+		}
+	})
+}
+
+func TestMusicBrainzRateLimit(t *testing.T) {
+	const interval = 50 * time.Millisecond
+
+	var requests []time.Time
+	mb := newTestMusicBrainz(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		w.Write([]byte(`{"releases": []}`))
+	}, noCoverArt(t))
+	mb.limiter = &rateLimiter{interval: interval}
+
+	for i := 0; i < 3; i++ {
+		if _, err := mb.Search(context.Background(), Query{Artista: "Pink Floyd"}); err != nil {
+			t.Fatalf("error inesperado: %v", err)
+		}
+	}
+
+	for i := 1; i < len(requests); i++ {
+		// Un margen pequeño por la resolución del reloj
+		if gap := requests[i].Sub(requests[i-1]); gap < interval-5*time.Millisecond {
+			t.Errorf("petición %d a %v de la anterior, se esperaba al menos %v", i, gap, interval)
+		}
+	}
+}
+
+// assertIdentifiers verifica los identificadores de un release
+func assertIdentifiers(t *testing.T, release *Release, want []ReleaseIdentifier) {
+	t.Helper()
+	if len(release.Identifiers) != len(want) {
+		t.Fatalf("identificadores = %+v, se esperaba %+v", release.Identifiers, want)
+	}
+	for i, identifier := range release.Identifiers {
+		if identifier != want[i] {
+			t.Errorf("identificador %d = %+v, se esperaba %+v", i, identifier, want[i])
+		}
+	}
+}
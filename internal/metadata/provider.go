@@ -0,0 +1,125 @@
+// Package metadata busca datos de releases (tracklist, géneros, sello,
+// año, arte) en servicios externos para completar los records.
+package metadata
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// ErrNotFound indica que el proveedor no tiene el release pedido
+var ErrNotFound = errors.New("release no encontrado")
+
+// Query describe lo que se sabe del disco a buscar. Basta con un campo.
+type Query struct {
+	Artista       string
+	Titulo        string
+	CatalogNumber string
+	Barcode       string
+}
+
+// IsEmpty indica si la búsqueda no tiene ningún criterio
+func (q Query) IsEmpty() bool {
+	return strings.TrimSpace(q.Artista+q.Titulo+q.CatalogNumber+q.Barcode) == ""
+}
+
+// Release es un candidato devuelto por un proveedor. Los resultados de
+// Search pueden venir incompletos (sin tracklist); Release retorna todos
+// los datos.
+type Release struct {
+	Provider      string
+	ID            string
+	Titulo        string
+	Artista       string
+	Sello         string
+	CatalogNumber string
+	Anio          int
+	Formato       string
+	Pais          string
+	Generos       []string
+	Estilos       []string
+	Tracklist     []models.Track
+	DuracionTotal string
+	ArteURL       string
+	URL           string // página del release en el proveedor
+
+	// Identifiers son los identificadores del release, ya normalizados,
+	// que se guardan junto al record
+	Identifiers []ReleaseIdentifier
+}
+
+// ReleaseIdentifier es un identificador de un release (tipo de models.Identifier)
+type ReleaseIdentifier struct {
+	Tipo  string
+	Valor string
+}
+
+// Provider es un servicio que busca releases
+type Provider interface {
+	// Name identifica al proveedor en formularios y URLs
+	Name() string
+	// Label es el nombre legible del proveedor
+	Label() string
+	// Search retorna los releases candidatos para la búsqueda
+	Search(ctx context.Context, q Query) ([]*Release, error)
+	// Release retorna todos los datos de un release por su ID
+	Release(ctx context.Context, id string) (*Release, error)
+}
+
+// MarketStats son los valores de mercado de un release
+type MarketStats struct {
+	PrecioMinimo float64 // precio más bajo a la venta; 0 si no hay copias
+	Moneda       string
+	EnVenta      int
+}
+
+// MarketProvider es un proveedor que además informa valores de mercado
+type MarketProvider interface {
+	// MarketStats retorna los valores de mercado de un release por su ID
+	MarketStats(ctx context.Context, id string) (*MarketStats, error)
+}
+
+// Find retorna el proveedor con el nombre indicado, o nil si no existe
+func Find(providers []Provider, name string) Provider {
+	for _, provider := range providers {
+		if provider.Name() == name {
+			return provider
+		}
+	}
+	return nil
+}
+
+// addIdentifier agrega un identificador al release si es válido
+func (r *Release) addIdentifier(tipo, valor string) {
+	normalized, err := models.NormalizeIdentifier(tipo, valor)
+	if err != nil {
+		return
+	}
+	for _, identifier := range r.Identifiers {
+		if identifier.Tipo == tipo && identifier.Valor == normalized {
+			return
+		}
+	}
+	r.Identifiers = append(r.Identifiers, ReleaseIdentifier{Tipo: tipo, Valor: normalized})
+}
+
+// totalDuration suma las duraciones del tracklist, o retorna vacío si
+// falta alguna
+func totalDuration(tracklist []models.Track) string {
+	var total time.Duration
+	for _, track := range tracklist {
+		d, ok := models.ParseTrackDuration(track.Duracion)
+		if !ok {
+			return ""
+		}
+		total += d
+	}
+	if total == 0 {
+		return ""
+	}
+	return models.FormatTrackDuration(total)
+}
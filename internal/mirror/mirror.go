@@ -0,0 +1,234 @@
+// Package mirror copia al almacenamiento local el arte que los records
+// enlazan desde sitios externos, para que el sitio público no dependa de
+// ellos. Cada URL se descarga una sola vez: si se copia, el record pasa a
+// usar la copia local, y si falla, el enlace queda reportado como roto.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/metadata"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// ErrRunning indica que ya hay una copia de arte en curso
+var ErrRunning = errors.New("ya hay una copia de arte en curso")
+
+// batchSize es la cantidad de records que se leen por consulta
+const batchSize = 50
+
+// downloadTimeout es el tiempo máximo para descargar una imagen
+const downloadTimeout = 30 * time.Second
+
+// Result resume una ejecución de la copia de arte
+type Result struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Copied     int // imágenes copiadas
+	Broken     int // enlaces que no se pudieron copiar
+}
+
+// Mirror copia el arte externo de los records al almacenamiento local
+type Mirror struct {
+	mirrors  *repository.ArtworkMirrorRepository
+	art      *artwork.Artwork
+	client   *http.Client
+	interval time.Duration
+
+	mu      sync.Mutex
+	running bool
+	last    *Result
+}
+
+// New crea un copiador que busca arte externo nuevo una vez por intervalo
+func New(mirrors *repository.ArtworkMirrorRepository, art *artwork.Artwork, interval time.Duration) *Mirror {
+	return &Mirror{
+		mirrors:  mirrors,
+		art:      art,
+		client:   &http.Client{Timeout: downloadTimeout},
+		interval: interval,
+	}
+}
+
+// Interval retorna cada cuánto se busca arte externo nuevo
+func (m *Mirror) Interval() time.Duration {
+	return m.interval
+}
+
+// Running indica si hay una copia en curso
+func (m *Mirror) Running() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running
+}
+
+// LastResult retorna el resumen de la última copia terminada, o nil si aún
+// no se ejecuta ninguna
+func (m *Mirror) LastResult() *Result {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
+
+// Start ejecuta la copia al iniciar y luego una vez por intervalo, hasta
+// que se cancele el contexto
+func (m *Mirror) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Run(ctx); err != nil && !errors.Is(err, ErrRunning) && ctx.Err() == nil {
+			log.Printf("❌ Error copiando arte: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run copia el arte de los records cuya URL externa todavía no se intentó
+// copiar
+func (m *Mirror) Run(ctx context.Context) (Result, error) {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return Result{}, ErrRunning
+	}
+	m.running = true
+	m.mu.Unlock()
+
+	result := Result{StartedAt: time.Now()}
+	defer func() {
+		result.FinishedAt = time.Now()
+		m.mu.Lock()
+		m.running = false
+		m.last = &result
+		m.mu.Unlock()
+	}()
+
+	for {
+		// Cada intento queda registrado, así que el siguiente lote no
+		// repite los records ya procesados
+		records, err := m.mirrors.GetPending(batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		for _, record := range records {
+			mirror, err := m.Copy(ctx, record)
+			if err != nil {
+				return result, err
+			}
+			if mirror.IsBroken() {
+				log.Printf("⚠️ Arte roto en %s: %s", record.ID, mirror.Error.String)
+				result.Broken++
+			} else {
+				result.Copied++
+			}
+		}
+	}
+
+	if result.Copied > 0 || result.Broken > 0 {
+		log.Printf("🖼️ Arte copiado: %d imágenes, %d enlaces rotos", result.Copied, result.Broken)
+	}
+	return result, nil
+}
+
+// Copy descarga el arte externo de un record y, si es una imagen válida,
+// lo guarda en el almacenamiento y actualiza el record. Los enlaces que
+// fallan se registran como rotos; solo retorna error si no se pudo guardar
+// el resultado o se canceló el contexto.
+func (m *Mirror) Copy(ctx context.Context, record *models.Record) (*models.ArtworkMirror, error) {
+	mirror := &models.ArtworkMirror{
+		RecordID:    record.ID,
+		URLOriginal: record.ArteURL.String,
+		Estado:      models.MirrorCopied,
+		IntentadoAt: time.Now(),
+	}
+
+	var localURL string
+	data, err := m.download(ctx, mirror.URLOriginal)
+	if err == nil {
+		localURL, err = m.art.Save(bytes.NewReader(data))
+		// Un error del almacenamiento no es culpa del enlace
+		if err != nil && !artwork.IsValidationError(err) {
+			return nil, err
+		}
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		mirror.Estado = models.MirrorBroken
+		mirror.Error = sql.NullString{String: err.Error(), Valid: true}
+	}
+
+	var palette artwork.Palette
+	if localURL != "" {
+		if palette, err = m.art.Palette(localURL); err != nil {
+			log.Printf("⚠️ No se pudo calcular la paleta de %s: %v", record.ID, err)
+		}
+	}
+
+	if err := m.mirrors.Save(mirror, localURL, palette); err != nil {
+		return nil, err
+	}
+	return mirror, nil
+}
+
+// download descarga una imagen verificando el tipo de contenido y el
+// tamaño que declara el servidor
+func (m *Mirror) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("URL inválida: %w", err)
+	}
+	req.Header.Set("User-Agent", metadata.UserAgent)
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error descargando %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s respondió HTTP %d", req.URL.Host, resp.StatusCode)
+	}
+
+	// Se rechaza antes de descargar lo que el servidor no declara como
+	// imagen; el contenido se vuelve a verificar al guardarlo, y ahí se
+	// rechazan también las imágenes más grandes que artwork.MaxSize
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "image/") {
+		return nil, fmt.Errorf("%s no retornó una imagen sino %q", req.URL.Host, mediaType)
+	}
+	if resp.ContentLength > artwork.MaxSize {
+		return nil, fmt.Errorf("%w de %d MB", artwork.ErrTooLarge, artwork.MaxSize>>20)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, artwork.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error descargando %s: %w", req.URL.Host, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,231 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+	"github.com/rodrwan/vinilo/internal/storage"
+)
+
+// newTestDB crea una base de datos en memoria con todas las migraciones
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("error abriendo BD: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob("../../migrations/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no se encontraron migraciones: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("error leyendo %s: %v", file, err)
+		}
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("error aplicando %s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// testPNG retorna una portada PNG de un solo color
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = 0x20, 0x40, 0xa0, 0xff
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("error codificando PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newTestServer sirve arte válido, inválido y demasiado grande
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	cover := testPNG(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/portada.png", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "image/*" || r.Header.Get("User-Agent") == "" {
+			t.Errorf("encabezados = %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(cover)
+	})
+	mux.HandleFunc("/pagina", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html>Portada</html>"))
+	})
+	mux.HandleFunc("/falsa.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("no es una imagen"))
+	})
+	mux.HandleFunc("/declarada-grande.png", func(w http.ResponseWriter, r *http.Request) {
+		// Se rechaza por el encabezado, sin leer el cuerpo
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", strconv.Itoa(artwork.MaxSize+1))
+		w.WriteHeader(http.StatusOK)
+		w.Write(cover)
+	})
+	mux.HandleFunc("/grande.png", func(w http.ResponseWriter, r *http.Request) {
+		// Sin Content-Length: el límite se aplica al leer
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(cover)
+		w.(http.Flusher).Flush()
+		w.Write(make([]byte, artwork.MaxSize))
+	})
+	mux.HandleFunc("/no-existe.png", http.NotFound)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestMirror crea un copiador con almacenamiento en un directorio temporal
+func newTestMirror(t *testing.T) (*Mirror, *repository.RecordRepository, *repository.ArtworkMirrorRepository) {
+	t.Helper()
+	db := newTestDB(t)
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("error creando almacenamiento: %v", err)
+	}
+	mirrors := repository.NewArtworkMirrorRepository(db)
+	return New(mirrors, artwork.New(store), time.Hour), repository.NewRecordRepository(db), mirrors
+}
+
+// newArtRecord crea un record con el arte en la URL indicada
+func newArtRecord(t *testing.T, records *repository.RecordRepository, url string) *models.Record {
+	t.Helper()
+	record := models.NewRecord()
+	record.Artista = "Nirvana"
+	record.Titulo = "Nevermind"
+	record.ArteURL = sql.NullString{String: url, Valid: true}
+	if err := records.Create(record); err != nil {
+		t.Fatalf("error creando record: %v", err)
+	}
+	return record
+}
+
+func TestCopy(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		path    string
+		wantErr string // error registrado del enlace roto, vacío si se copia
+	}{
+		{path: "/portada.png"},
+		{path: "/pagina", wantErr: `no retornó una imagen sino "text/html"`},
+		{path: "/falsa.png", wantErr: artwork.ErrInvalidImage.Error()},
+		{path: "/declarada-grande.png", wantErr: artwork.ErrTooLarge.Error()},
+		{path: "/grande.png", wantErr: artwork.ErrTooLarge.Error()},
+		{path: "/no-existe.png", wantErr: "respondió HTTP 404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.TrimPrefix(tt.path, "/"), func(t *testing.T) {
+			m, records, _ := newTestMirror(t)
+			record := newArtRecord(t, records, server.URL+tt.path)
+
+			mirror, err := m.Copy(context.Background(), record)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			got, err := records.GetByID(record.ID)
+			if err != nil {
+				t.Fatalf("error obteniendo record: %v", err)
+			}
+
+			if tt.wantErr == "" {
+				if mirror.IsBroken() {
+					t.Fatalf("enlace roto: %s", mirror.Error.String)
+				}
+				if !artwork.IsLocal(got.ArteURL.String) || !got.HasPalette() || got.Version != record.Version+1 {
+					t.Errorf("record: arte %q, paleta %v, versión %d", got.ArteURL.String, got.HasPalette(), got.Version)
+				}
+				return
+			}
+
+			if !mirror.IsBroken() || !strings.Contains(mirror.Error.String, tt.wantErr) {
+				t.Errorf("copia = %s: %q, se esperaba un enlace roto con %q", mirror.Estado, mirror.Error.String, tt.wantErr)
+			}
+			if got.ArteURL.String != record.ArteURL.String || got.Version != record.Version {
+				t.Errorf("el record cambió: arte %q, versión %d", got.ArteURL.String, got.Version)
+			}
+		})
+	}
+}
+
+func TestCopyKeepsChangedURL(t *testing.T) {
+	server := newTestServer(t)
+	m, records, mirrors := newTestMirror(t)
+	record := newArtRecord(t, records, server.URL+"/portada.png")
+
+	// Alguien sube otra portada mientras se descarga la externa
+	changed := *record
+	changed.ArteURL = sql.NullString{String: "/media/artwork/subida.jpg", Valid: true}
+	if err := records.Update(&changed); err != nil {
+		t.Fatalf("error actualizando record: %v", err)
+	}
+
+	mirror, err := m.Copy(context.Background(), record)
+	if err != nil || mirror.IsBroken() {
+		t.Fatalf("copia = %+v (%v)", mirror, err)
+	}
+	got, err := records.GetByID(record.ID)
+	if err != nil {
+		t.Fatalf("error obteniendo record: %v", err)
+	}
+	if got.ArteURL.String != "/media/artwork/subida.jpg" || got.Version != changed.Version {
+		t.Errorf("se reemplazó la portada subida: arte %q, versión %d", got.ArteURL.String, got.Version)
+	}
+	if saved, err := mirrors.GetByRecord(record.ID); err != nil || saved == nil || saved.URLOriginal != record.ArteURL.String {
+		t.Errorf("copia registrada = %+v (%v)", saved, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := newTestServer(t)
+	m, records, mirrors := newTestMirror(t)
+	newArtRecord(t, records, server.URL+"/portada.png")
+	newArtRecord(t, records, server.URL+"/no-existe.png")
+	newArtRecord(t, records, "/media/artwork/local.jpg")
+
+	result, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Copied != 1 || result.Broken != 1 || m.LastResult() == nil || m.Running() {
+		t.Errorf("resultado = %+v", result)
+	}
+	if n, err := mirrors.CountPending(); err != nil || n != 0 {
+		t.Errorf("pendientes = %d (%v), se esperaba 0", n, err)
+	}
+
+	// Cada URL se intenta una sola vez
+	again, err := m.Run(context.Background())
+	if err != nil || again.Copied != 0 || again.Broken != 0 {
+		t.Errorf("segunda ejecución = %+v (%v)", again, err)
+	}
+}
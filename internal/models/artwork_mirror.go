@@ -0,0 +1,27 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Estados de la copia local del arte de un record
+const (
+	MirrorCopied = "copiado"
+	MirrorBroken = "roto"
+)
+
+// ArtworkMirror es el resultado de copiar al almacenamiento local el arte
+// que un record enlazaba desde un sitio externo
+type ArtworkMirror struct {
+	RecordID    string         `json:"record_id" db:"record_id"`
+	URLOriginal string         `json:"url_original" db:"url_original"`
+	Estado      string         `json:"estado" db:"estado"`
+	Error       sql.NullString `json:"error" db:"error"`
+	IntentadoAt time.Time      `json:"intentado_at" db:"intentado_at"`
+}
+
+// IsBroken indica si el enlace original no se pudo copiar
+func (m *ArtworkMirror) IsBroken() bool {
+	return m.Estado == MirrorBroken
+}
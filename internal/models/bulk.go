@@ -0,0 +1,151 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Acciones masivas sobre los records seleccionados en el admin
+const (
+	BulkSetField     = "campo"
+	BulkAddTag       = "agregar_tag"
+	BulkRemoveTag    = "quitar_tag"
+	BulkAddGenre     = "agregar_genero"
+	BulkRemoveGenre  = "quitar_genero"
+	BulkMoveLocation = "ubicacion"
+	BulkDelete       = "eliminar"
+)
+
+// BulkFields lista los campos que se pueden fijar en varios records a la vez
+var BulkFields = []string{"formato", "condicion", "sello", "pais", "anio"}
+
+// BulkAction es una acción masiva: qué hacer y con qué valor. Valor es el
+// valor del campo, el tag, el género o el ID de la ubicación según la
+// acción; vacío limpia el campo o deja los records sin ubicación.
+type BulkAction struct {
+	Accion string
+	Campo  string
+	Valor  string
+}
+
+// Validate verifica que la acción se pueda aplicar
+func (a BulkAction) Validate() error {
+	switch a.Accion {
+	case BulkSetField:
+		if !IsBulkField(a.Campo) {
+			return fmt.Errorf("campo no editable en masa: %s", a.Campo)
+		}
+		if _, err := strconv.Atoi(a.Valor); a.Campo == "anio" && a.Valor != "" && err != nil {
+			return fmt.Errorf("año inválido: %s", a.Valor)
+		}
+	case BulkAddTag, BulkRemoveTag:
+		if NormalizeTag(a.Valor) == "" {
+			return fmt.Errorf("indica el tag")
+		}
+	case BulkAddGenre, BulkRemoveGenre:
+		if strings.TrimSpace(a.Valor) == "" {
+			return fmt.Errorf("indica el género")
+		}
+	case BulkMoveLocation, BulkDelete:
+	default:
+		return fmt.Errorf("acción desconocida: %s", a.Accion)
+	}
+	return nil
+}
+
+// IsBulkField indica si un campo se puede fijar en varios records a la vez
+func IsBulkField(campo string) bool {
+	for _, field := range BulkFields {
+		if field == campo {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLabel retorna una descripción legible de la acción
+func (a BulkAction) GetLabel() string {
+	switch a.Accion {
+	case BulkSetField:
+		if a.Valor == "" {
+			return "Vaciar " + HistoryFieldLabel(a.Campo)
+		}
+		return HistoryFieldLabel(a.Campo) + ": " + a.Valor
+	case BulkAddTag:
+		return "Agregar el tag #" + NormalizeTag(a.Valor)
+	case BulkRemoveTag:
+		return "Quitar el tag #" + NormalizeTag(a.Valor)
+	case BulkAddGenre:
+		return "Agregar el género " + strings.TrimSpace(a.Valor)
+	case BulkRemoveGenre:
+		return "Quitar el género " + strings.TrimSpace(a.Valor)
+	case BulkMoveLocation:
+		if a.Valor == "" {
+			return "Quitar la ubicación"
+		}
+		return "Mover de ubicación"
+	case BulkDelete:
+		return "Enviar a la papelera"
+	}
+	return a.Accion
+}
+
+// ApplyBulk aplica a los campos del record una acción masiva. Los tags y el
+// envío a la papelera no son campos del record y se aplican en el
+// repositorio.
+func (r *Record) ApplyBulk(a BulkAction) error {
+	switch a.Accion {
+	case BulkSetField:
+		switch {
+		case a.Campo == "condicion":
+			r.Condicion = sql.NullString{String: a.Valor, Valid: a.Valor != ""}
+		case a.Valor == "":
+			return r.clearMergeValue(a.Campo)
+		default:
+			return r.SetMetadataValue(a.Campo, a.Valor)
+		}
+	case BulkAddGenre, BulkRemoveGenre:
+		genero := strings.TrimSpace(a.Valor)
+		generos := make([]string, 0, len(r.GetGenerosAsSlice())+1)
+		found := false
+		for _, g := range r.GetGenerosAsSlice() {
+			if strings.EqualFold(g, genero) {
+				found = true
+				if a.Accion == BulkRemoveGenre {
+					continue
+				}
+			}
+			generos = append(generos, g)
+		}
+		// Sin cambios se conserva el JSON guardado tal como está
+		switch {
+		case a.Accion == BulkAddGenre && !found:
+			r.SetGeneros(append(generos, genero))
+		case a.Accion == BulkRemoveGenre && found:
+			r.SetGeneros(generos)
+		}
+	case BulkMoveLocation:
+		r.LocationID = sql.NullString{String: a.Valor, Valid: a.Valor != ""}
+	default:
+		return fmt.Errorf("la acción %s no modifica campos del record", a.Accion)
+	}
+	return nil
+}
+
+// BulkRecordResult es un record modificado por una acción masiva con los
+// campos que cambiaron
+type BulkRecordResult struct {
+	Record  *Record
+	Changes []FieldChange
+}
+
+// BulkResult resume lo que cambió una acción masiva
+type BulkResult struct {
+	Action    BulkAction
+	Selected  int
+	Changed   []BulkRecordResult
+	Unchanged []*Record
+	Missing   int
+}
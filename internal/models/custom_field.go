@@ -0,0 +1,200 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de campo personalizado
+const (
+	CustomFieldText   = "text"
+	CustomFieldNumber = "number"
+	CustomFieldBool   = "bool"
+	CustomFieldDate   = "date"
+	CustomFieldEnum   = "enum"
+)
+
+// CustomFieldTypes lista los tipos de campo personalizado válidos
+var CustomFieldTypes = []string{
+	CustomFieldText,
+	CustomFieldNumber,
+	CustomFieldBool,
+	CustomFieldDate,
+	CustomFieldEnum,
+}
+
+// CustomField representa un campo definido por el administrador para
+// registrar datos propios de cada colección (planta de prensaje, vinilo
+// de color, número de edición limitada, firmado por...)
+type CustomField struct {
+	ID        string         `json:"id" db:"id"`
+	Clave     string         `json:"clave" db:"clave"`
+	Nombre    string         `json:"nombre" db:"nombre"`
+	Tipo      string         `json:"tipo" db:"tipo"`
+	Opciones  sql.NullString `json:"opciones" db:"opciones"` // JSON array, solo para enum
+	Posicion  int            `json:"posicion" db:"posicion"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewCustomField crea un nuevo campo personalizado con ID generado y la
+// clave derivada del nombre
+func NewCustomField(nombre, tipo string) *CustomField {
+	return &CustomField{
+		ID:        uuid.New().String(),
+		Clave:     CustomFieldKey(nombre),
+		Nombre:    strings.TrimSpace(nombre),
+		Tipo:      tipo,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsValidCustomFieldType verifica si un tipo de campo personalizado es válido
+func IsValidCustomFieldType(tipo string) bool {
+	for _, t := range CustomFieldTypes {
+		if t == tipo {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomFieldTypeLabel retorna el nombre legible de un tipo de campo
+func CustomFieldTypeLabel(tipo string) string {
+	switch tipo {
+	case CustomFieldText:
+		return "Texto"
+	case CustomFieldNumber:
+		return "Número"
+	case CustomFieldBool:
+		return "Sí / No"
+	case CustomFieldDate:
+		return "Fecha"
+	case CustomFieldEnum:
+		return "Lista de opciones"
+	}
+	return tipo
+}
+
+// customFieldKeyReplacer quita los acentos al generar claves
+var customFieldKeyReplacer = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+)
+
+// CustomFieldKey deriva la clave de un campo a partir de su nombre:
+// "Planta de prensaje" → "planta_de_prensaje"
+func CustomFieldKey(nombre string) string {
+	nombre = customFieldKeyReplacer.Replace(strings.ToLower(strings.TrimSpace(nombre)))
+
+	var b strings.Builder
+	underscore := false
+	for _, c := range nombre {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+// GetOpciones retorna las opciones de un campo enum como slice
+func (f *CustomField) GetOpciones() []string {
+	if !f.Opciones.Valid || f.Opciones.String == "" {
+		return []string{}
+	}
+
+	var opciones []string
+	if err := json.Unmarshal([]byte(f.Opciones.String), &opciones); err != nil {
+		return []string{}
+	}
+	return opciones
+}
+
+// SetOpciones establece las opciones de un campo enum
+func (f *CustomField) SetOpciones(opciones []string) {
+	f.Opciones = toJSONString(opciones)
+}
+
+// GetTypeLabel retorna el nombre legible del tipo del campo
+func (f *CustomField) GetTypeLabel() string {
+	return CustomFieldTypeLabel(f.Tipo)
+}
+
+// InputName retorna el nombre del input del campo en los formularios
+func (f *CustomField) InputName() string {
+	return "cf_" + f.Clave
+}
+
+// ParseValue convierte el texto de un formulario o de una importación al
+// valor tipado que se guarda en el record. Un texto vacío retorna nil.
+func (f *CustomField) ParseValue(raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	switch f.Tipo {
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s debe ser un número", f.Nombre)
+		}
+		return n, nil
+	case CustomFieldBool:
+		switch strings.ToLower(raw) {
+		case "true", "1", "si", "sí", "yes", "on":
+			return true, nil
+		case "false", "0", "no", "off":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%s debe ser sí o no", f.Nombre)
+	case CustomFieldDate:
+		if _, err := time.Parse("2006-01-02", raw); err != nil {
+			return nil, fmt.Errorf("%s debe ser una fecha AAAA-MM-DD", f.Nombre)
+		}
+		return raw, nil
+	case CustomFieldEnum:
+		for _, opcion := range f.GetOpciones() {
+			if strings.EqualFold(opcion, raw) {
+				return opcion, nil
+			}
+		}
+		return nil, fmt.Errorf("%s no admite el valor %q", f.Nombre, raw)
+	}
+	return raw, nil
+}
+
+// FormatValue convierte un valor guardado en texto para mostrarlo
+func (f *CustomField) FormatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		if v {
+			return "Sí"
+		}
+		return "No"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(value)
+}
+
+// InputValue convierte un valor guardado en el texto que espera el input
+// del formulario
+func (f *CustomField) InputValue(value any) string {
+	if v, ok := value.(bool); ok {
+		return strconv.FormatBool(v)
+	}
+	return f.FormatValue(value)
+}
@@ -0,0 +1,408 @@
+package models
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateThreshold es el puntaje mínimo para considerar que dos records
+// podrían ser el mismo. Artista y título idénticos bastan; con números de
+// catálogo distintos (otra edición) ya no.
+const DuplicateThreshold = 60
+
+// Puntaje que aporta cada coincidencia entre dos records
+const (
+	duplicateArtistScore     = 30  // artista igual; parcial si se parecen
+	duplicateTitleScore      = 40  // título igual; parcial si se parecen
+	duplicateCatalogScore    = 25  // mismo número de catálogo
+	duplicateCatalogPenalty  = -25 // números de catálogo distintos
+	duplicateIdentifierScore = 40  // mismo código de barras o release externo
+	duplicateMaxScore        = 100
+)
+
+// DuplicateCandidate es un record con sus identificadores, lo necesario
+// para compararlo con el resto de la colección
+type DuplicateCandidate struct {
+	Record      *Record
+	Identifiers []*Identifier
+}
+
+// DuplicatePair son dos records que podrían ser el mismo, con el puntaje
+// (0 a 100) y los motivos de la coincidencia
+type DuplicatePair struct {
+	A       *Record
+	B       *Record
+	Score   int
+	Reasons []string
+}
+
+// DuplicatePairKey retorna los IDs de un par en orden, como se guardan los
+// pares descartados
+func DuplicatePairKey(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// DuplicateRelated cuenta lo que cuelga de un record y se traspasa al
+// fusionarlo con su duplicado
+type DuplicateRelated struct {
+	Plays       int
+	Loans       int
+	Images      int
+	Identifiers int
+	Tags        int
+	Playlists   int
+}
+
+// duplicateKeys son los valores normalizados de un candidato
+type duplicateKeys struct {
+	artist      []string
+	title       []string
+	catalog     string
+	identifiers map[string]bool // "tipo:valor" de barcode, discogs y musicbrainz
+}
+
+// keys normaliza los valores que se comparan de un candidato
+func (c DuplicateCandidate) keys() duplicateKeys {
+	keys := duplicateKeys{
+		artist:      normalizedWords(c.Record.Artista),
+		title:       normalizedWords(c.Record.Titulo),
+		catalog:     normalizeCatalogNumber(c.Record.CatalogNumber.String),
+		identifiers: map[string]bool{},
+	}
+	for _, identifier := range c.Identifiers {
+		// Las matrices se comparten entre ediciones y no identifican un release
+		if identifier.Tipo != IdentifierMatrix {
+			keys.identifiers[identifier.Tipo+":"+identifier.Valor] = true
+		}
+	}
+	return keys
+}
+
+// ScoreDuplicate puntúa qué tan probable es que dos records sean el mismo
+// y retorna los motivos
+func ScoreDuplicate(a, b DuplicateCandidate) (int, []string) {
+	return scoreKeys(a.keys(), b.keys())
+}
+
+// scoreKeys puntúa dos candidatos ya normalizados
+func scoreKeys(a, b duplicateKeys) (int, []string) {
+	var score float64
+	var reasons []string
+
+	for key := range a.identifiers {
+		if b.identifiers[key] {
+			tipo, _, _ := strings.Cut(key, ":")
+			score += duplicateIdentifierScore
+			reasons = append(reasons, identifierReason(tipo))
+		}
+	}
+
+	switch {
+	case a.catalog == "" || b.catalog == "":
+	case a.catalog == b.catalog:
+		score += duplicateCatalogScore
+		reasons = append(reasons, "Mismo número de catálogo")
+	default:
+		score += duplicateCatalogPenalty
+		reasons = append(reasons, "Números de catálogo distintos")
+	}
+
+	if similarity := wordSimilarity(a.artist, b.artist); similarity == 1 {
+		score += duplicateArtistScore
+		reasons = append(reasons, "Mismo artista")
+	} else if similarity > 0 {
+		score += duplicateArtistScore * similarity
+		reasons = append(reasons, "Artista parecido")
+	}
+
+	if similarity := wordSimilarity(a.title, b.title); similarity == 1 {
+		score += duplicateTitleScore
+		reasons = append(reasons, "Mismo título")
+	} else if similarity > 0 {
+		score += duplicateTitleScore * similarity
+		reasons = append(reasons, "Título parecido")
+	}
+
+	return min(max(int(score), 0), duplicateMaxScore), reasons
+}
+
+// identifierReason describe la coincidencia de un identificador
+func identifierReason(tipo string) string {
+	if tipo == IdentifierBarcode {
+		return "Mismo código de barras"
+	}
+	return "Mismo release en " + IdentifierTypeLabel(tipo)
+}
+
+// FindDuplicatePairs compara los candidatos y retorna los pares que
+// alcanzan el umbral, de mayor a menor puntaje. Solo se comparan los que
+// comparten una palabra del artista, el número de catálogo o un
+// identificador, así no hace falta comparar todos contra todos.
+func FindDuplicatePairs(candidates []DuplicateCandidate) []*DuplicatePair {
+	keys := make([]duplicateKeys, len(candidates))
+	blocks := map[string][]int{}
+	for i, candidate := range candidates {
+		keys[i] = candidate.keys()
+		seen := map[string]bool{}
+		add := func(block string) {
+			if !seen[block] {
+				seen[block] = true
+				blocks[block] = append(blocks[block], i)
+			}
+		}
+		for _, word := range keys[i].artist {
+			add("artista:" + word)
+		}
+		if keys[i].catalog != "" {
+			add("catalogo:" + keys[i].catalog)
+		}
+		for identifier := range keys[i].identifiers {
+			add(identifier)
+		}
+	}
+
+	compared := map[[2]int]bool{}
+	var pairs []*DuplicatePair
+	for _, block := range blocks {
+		for x := 0; x < len(block); x++ {
+			for y := x + 1; y < len(block); y++ {
+				i, j := block[x], block[y]
+				if compared[[2]int{i, j}] {
+					continue
+				}
+				compared[[2]int{i, j}] = true
+
+				score, reasons := scoreKeys(keys[i], keys[j])
+				if score < DuplicateThreshold {
+					continue
+				}
+				pairs = append(pairs, &DuplicatePair{
+					A:       candidates[i].Record,
+					B:       candidates[j].Record,
+					Score:   score,
+					Reasons: reasons,
+				})
+			}
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Score != pairs[j].Score {
+			return pairs[i].Score > pairs[j].Score
+		}
+		if pairs[i].A.Artista != pairs[j].A.Artista {
+			return pairs[i].A.Artista < pairs[j].A.Artista
+		}
+		return pairs[i].A.ID < pairs[j].A.ID
+	})
+	return pairs
+}
+
+// accentReplacer quita los acentos más comunes, suficientes para comparar
+// nombres de artistas y títulos
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "ä", "a", "â", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ë", "e", "ê", "e",
+	"í", "i", "ì", "i", "ï", "i", "î", "i",
+	"ó", "o", "ò", "o", "ö", "o", "ô", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "ü", "u", "û", "u",
+	"ñ", "n", "ç", "c", "&", " and ",
+)
+
+// ignoredWords son palabras que no distinguen un artista o título
+// ("The Beatles" y "Beatles", "Kind of Blue" y "Kind of Blue (Remastered)")
+var ignoredWords = map[string]bool{
+	"the": true, "a": true, "an": true, "el": true, "la": true, "los": true, "las": true,
+	"and": true, "y": true, "remastered": true, "remaster": true, "edition": true,
+	"deluxe": true, "reissue": true,
+}
+
+// normalizedWords lleva un texto a sus palabras en minúsculas, sin
+// acentos, puntuación ni palabras que no lo distinguen
+func normalizedWords(text string) []string {
+	text = accentReplacer.Replace(strings.ToLower(text))
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make([]string, 0, len(fields))
+	for _, word := range fields {
+		if !ignoredWords[word] {
+			words = append(words, word)
+		}
+	}
+	// Un título formado solo por palabras ignoradas se compara completo
+	if len(words) == 0 {
+		return fields
+	}
+	return words
+}
+
+// normalizeCatalogNumber deja solo letras y dígitos en minúsculas, ya que
+// el mismo número se escribe "PCS 7088", "PCS-7088" o "pcs7088"
+func normalizeCatalogNumber(catalog string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(catalog) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 || b.String() == "none" {
+		return ""
+	}
+	return b.String()
+}
+
+// wordSimilarity es la proporción de palabras compartidas (índice de
+// Jaccard): 1 si son las mismas, 0 si no comparten ninguna
+func wordSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := map[string]bool{}
+	for _, word := range a {
+		set[word] = true
+	}
+	var shared int
+	union := len(set)
+	seen := map[string]bool{}
+	for _, word := range b {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if set[word] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+// MergeFields lista los campos que se eligen de uno u otro record al
+// fusionar duplicados: los de metadatos más la condición y las notas
+var MergeFields = append(append([]string{}, MetadataFields...), "condicion", "notas")
+
+// MergeFieldLabel retorna el nombre legible de un campo fusionable
+func MergeFieldLabel(campo string) string {
+	switch campo {
+	case "condicion":
+		return "Condición"
+	case "notas":
+		return "Notas"
+	}
+	return MetadataFieldLabel(campo)
+}
+
+// MergeValue retorna el valor de un campo fusionable
+func (r *Record) MergeValue(campo string) string {
+	switch campo {
+	case "condicion":
+		return r.Condicion.String
+	case "notas":
+		return r.Notas.String
+	}
+	return r.MetadataValue(campo)
+}
+
+// SetMergeValue copia el valor de un campo fusionable desde otro record
+func (r *Record) SetMergeValue(campo string, from *Record) error {
+	switch campo {
+	case "condicion":
+		r.Condicion = from.Condicion
+	case "notas":
+		r.Notas = from.Notas
+	case "tracklist":
+		// Se copia completo, con calificaciones y reseñas
+		r.Tracklist = from.Tracklist
+	case "arte_url":
+		r.ArteURL = from.ArteURL
+		r.ColorDominante = from.ColorDominante
+		r.ColorAcento = from.ColorAcento
+	default:
+		valor := from.MetadataValue(campo)
+		if valor == "" {
+			return r.clearMergeValue(campo)
+		}
+		return r.SetMetadataValue(campo, valor)
+	}
+	return nil
+}
+
+// clearMergeValue vacía un campo de metadatos, lo que SetMetadataValue no
+// permite para el año y las listas
+func (r *Record) clearMergeValue(campo string) error {
+	switch campo {
+	case "anio":
+		r.Anio = sql.NullInt32{}
+	case "generos":
+		r.Generos = sql.NullString{}
+	case "estilos":
+		r.Estilos = sql.NullString{}
+	default:
+		return r.SetMetadataValue(campo, "")
+	}
+	return nil
+}
+
+// MergeRow es un campo de dos records duplicados, para compararlos lado a lado
+type MergeRow struct {
+	Campo string
+	Label string
+	A     string
+	B     string
+	Same  bool
+	UseB  bool // opción sugerida: el valor de B completa un campo vacío de A
+}
+
+// DiffRecords compara los campos fusionables de dos records
+func DiffRecords(a, b *Record) []MergeRow {
+	rows := make([]MergeRow, 0, len(MergeFields))
+	for _, campo := range MergeFields {
+		va, vb := a.MergeValue(campo), b.MergeValue(campo)
+		rows = append(rows, MergeRow{
+			Campo: campo,
+			Label: MergeFieldLabel(campo),
+			A:     FormatMetadataValue(campo, va),
+			B:     FormatMetadataValue(campo, vb),
+			Same:  SameMetadataValue(campo, va, vb),
+			UseB:  va == "" && vb != "",
+		})
+	}
+	return rows
+}
+
+// MergeRecords combina en keep los datos de other: copia los campos
+// indicados en fromOther y completa con other lo que keep no tenga
+// (ubicación, calificación, reseña y campos personalizados)
+func MergeRecords(keep, other *Record, fromOther []string) error {
+	for _, campo := range fromOther {
+		if err := keep.SetMergeValue(campo, other); err != nil {
+			return err
+		}
+	}
+
+	if !keep.LocationID.Valid {
+		keep.LocationID = other.LocationID
+	}
+	if !keep.Rating.Valid {
+		keep.Rating = other.Rating
+	}
+	if !keep.Review.Valid || keep.Review.String == "" {
+		keep.Review = other.Review
+	}
+
+	campos := other.GetCampos()
+	for key, value := range keep.GetCampos() {
+		campos[key] = value
+	}
+	keep.SetCampos(campos)
+	return nil
+}
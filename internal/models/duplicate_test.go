@@ -0,0 +1,150 @@
+package models
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+)
+
+// duplicateCandidate crea un candidato con los identificadores indicados
+func duplicateCandidate(id, artista, titulo, catalogo string, identifiers ...*Identifier) DuplicateCandidate {
+	record := &Record{ID: id, Artista: artista, Titulo: titulo}
+	record.CatalogNumber = sql.NullString{String: catalogo, Valid: catalogo != ""}
+	return DuplicateCandidate{Record: record, Identifiers: identifiers}
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	barcode := &Identifier{Tipo: IdentifierBarcode, Valor: "720642442517"}
+	matrix := &Identifier{Tipo: IdentifierMatrix, Valor: "DGC-24425-A"}
+
+	tests := []struct {
+		name    string
+		a, b    DuplicateCandidate
+		want    int
+		reasons []string
+	}{
+		{
+			name:    "mismo artista y título",
+			a:       duplicateCandidate("1", "Nirvana", "Nevermind", ""),
+			b:       duplicateCandidate("2", "nirvana", "NEVERMIND", ""),
+			want:    70,
+			reasons: []string{"Mismo artista", "Mismo título"},
+		},
+		{
+			name:    "sin artículos, acentos ni reediciones",
+			a:       duplicateCandidate("1", "The Beatles", "Abbey Road", ""),
+			b:       duplicateCandidate("2", "Beatles", "Abbey Road (Remastered)", ""),
+			want:    70,
+			reasons: []string{"Mismo artista", "Mismo título"},
+		},
+		{
+			name:    "acentos",
+			a:       duplicateCandidate("1", "Víctor Jara", "Canto libre", ""),
+			b:       duplicateCandidate("2", "Victor Jara", "Canto Libre", ""),
+			want:    70,
+			reasons: []string{"Mismo artista", "Mismo título"},
+		},
+		{
+			name:    "mismo catálogo escrito distinto",
+			a:       duplicateCandidate("1", "The Beatles", "Abbey Road", "PCS 7088"),
+			b:       duplicateCandidate("2", "Beatles", "Abbey Road", "pcs-7088"),
+			want:    95,
+			reasons: []string{"Mismo número de catálogo", "Mismo artista", "Mismo título"},
+		},
+		{
+			name:    "otra edición",
+			a:       duplicateCandidate("1", "Nirvana", "Nevermind", "DGC-24425"),
+			b:       duplicateCandidate("2", "Nirvana", "Nevermind", "GEF 24425"),
+			want:    45,
+			reasons: []string{"Números de catálogo distintos", "Mismo artista", "Mismo título"},
+		},
+		{
+			name:    "artista parecido",
+			a:       duplicateCandidate("1", "Miles Davis", "Kind of Blue", ""),
+			b:       duplicateCandidate("2", "Miles Davis Quintet", "Kind of Blue", ""),
+			want:    60,
+			reasons: []string{"Artista parecido", "Mismo título"},
+		},
+		{
+			name:    "el puntaje no pasa de 100",
+			a:       duplicateCandidate("1", "Nirvana", "Nevermind", "DGC-24425", barcode),
+			b:       duplicateCandidate("2", "Nirvana", "Nevermind", "DGC 24425", barcode),
+			want:    100,
+			reasons: []string{"Mismo código de barras", "Mismo número de catálogo", "Mismo artista", "Mismo título"},
+		},
+		{
+			name: "las matrices no cuentan",
+			a:    duplicateCandidate("1", "Nirvana", "Bleach", "", matrix),
+			b:    duplicateCandidate("2", "Mudhoney", "Superfuzz Bigmuff", "", matrix),
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := ScoreDuplicate(tt.a, tt.b)
+			if score != tt.want {
+				t.Errorf("puntaje = %d, se esperaba %d", score, tt.want)
+			}
+			if !slices.Equal(reasons, tt.reasons) {
+				t.Errorf("motivos = %v, se esperaba %v", reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestFindDuplicatePairs(t *testing.T) {
+	pairs := FindDuplicatePairs([]DuplicateCandidate{
+		duplicateCandidate("1", "Nirvana", "Nevermind", "DGC-24425"),
+		duplicateCandidate("2", "John Coltrane", "Blue Train", ""),
+		duplicateCandidate("3", "Nirvana", "Nevermind", "DGC 24425"),
+		duplicateCandidate("4", "The Beatles", "Abbey Road", ""),
+		duplicateCandidate("5", "Beatles", "Abbey Road (Remastered)", ""),
+		duplicateCandidate("6", "Nirvana", "Nevermind", "GEF 24425"),
+	})
+
+	var got [][2]string
+	for _, pair := range pairs {
+		got = append(got, [2]string{pair.A.ID, pair.B.ID})
+	}
+	// 1 y 6, y 3 y 6, tienen números de catálogo distintos
+	want := [][2]string{{"1", "3"}, {"4", "5"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("pares = %v, se esperaba %v", got, want)
+	}
+}
+
+func TestMergeRecords(t *testing.T) {
+	keep := NewRecord()
+	keep.Titulo = "Nevermind"
+	keep.Artista = "Nirvana"
+	keep.Anio = sql.NullInt32{Int32: 1991, Valid: true}
+	keep.Rating = sql.NullFloat64{Float64: 5, Valid: true}
+	keep.SetCampos(map[string]any{"prensaje": "original"})
+
+	other := NewRecord()
+	other.Titulo = "Nevermind (Remastered)"
+	other.Artista = "Nirvana"
+	other.Sello = sql.NullString{String: "DGC", Valid: true}
+	other.LocationID = sql.NullString{String: "repisa", Valid: true}
+	other.Rating = sql.NullFloat64{Float64: 3, Valid: true}
+	other.Review = sql.NullString{String: "Suena mejor", Valid: true}
+	other.SetCampos(map[string]any{"prensaje": "reedición", "color": "negro"})
+
+	if err := MergeRecords(keep, other, []string{"sello", "anio"}); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	// Los campos elegidos se copian, aunque vacíen el de keep
+	if keep.Sello.String != "DGC" || keep.Anio.Valid || keep.Titulo != "Nevermind" {
+		t.Errorf("campos elegidos: sello %q, año %v, título %q", keep.Sello.String, keep.Anio, keep.Titulo)
+	}
+	// Lo que keep no tiene se completa con other, sin pisar lo que tiene
+	if keep.LocationID.String != "repisa" || keep.Rating.Float64 != 5 || keep.Review.String != "Suena mejor" {
+		t.Errorf("completados: ubicación %q, calificación %v, reseña %q", keep.LocationID.String, keep.Rating.Float64, keep.Review.String)
+	}
+	campos := keep.GetCampos()
+	if campos["prensaje"] != "original" || campos["color"] != "negro" {
+		t.Errorf("campos personalizados = %v", campos)
+	}
+}
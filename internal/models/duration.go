@@ -0,0 +1,36 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTrackDuration interpreta una duración escrita como "3:45" o "1:02:03"
+func ParseTrackDuration(value string) (time.Duration, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+
+	var total time.Duration
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0, false
+		}
+		total = total*60 + time.Duration(n)
+	}
+	return total * time.Second, true
+}
+
+// FormatTrackDuration formatea una duración como "42:49" o "1:02:03"
+func FormatTrackDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second) / time.Second)
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
@@ -0,0 +1,247 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Ordenamientos disponibles para los listados de records
+const (
+	SortRecent = "recent"
+	SortRating = "rating"
+	SortArtist = "artista"
+	SortYear   = "anio"
+	SortTitle  = "titulo"
+)
+
+// SortOptions lista los ordenamientos con su etiqueta para los formularios
+var SortOptions = []struct {
+	Value string
+	Label string
+}{
+	{SortRecent, "Más recientes"},
+	{SortRating, "Mejor calificados"},
+	{SortArtist, "Artista"},
+	{SortTitle, "Título"},
+	{SortYear, "Año"},
+}
+
+// RecordFilter describe los criterios de búsqueda, filtrado y orden de
+// los listados de records. El valor cero lista todo por fecha de alta.
+type RecordFilter struct {
+	Search     string            `json:"search,omitempty"`
+	MinRating  float64           `json:"min_rating,omitempty"`
+	Tag        string            `json:"tag,omitempty"`
+	Conditions []FilterCondition `json:"conditions,omitempty"`
+	Sort       string            `json:"sort,omitempty"`
+}
+
+// Values convierte el filtro en parámetros de query, omitiendo los vacíos
+func (f RecordFilter) Values() url.Values {
+	values := url.Values{}
+	if f.Search != "" {
+		values.Set("search", f.Search)
+	}
+	if f.MinRating > 0 {
+		values.Set("min_rating", FormatRatingValue(f.MinRating))
+	}
+	if f.Tag != "" {
+		values.Set("tag", f.Tag)
+	}
+	if len(f.Conditions) > 0 {
+		values.Set("filter", FormatFilterExpression(f.Conditions))
+	}
+	if f.Sort != "" && f.Sort != SortRecent {
+		values.Set("sort", f.Sort)
+	}
+	return values
+}
+
+// PageURL construye la URL de una página del listado conservando el filtro
+func (f RecordFilter) PageURL(base string, page int) string {
+	values := f.Values()
+	values.Set("page", strconv.Itoa(page))
+	return base + "?" + values.Encode()
+}
+
+// ParseRecordFilter lee un filtro desde parámetros de query. Los valores
+// inválidos se ignoran.
+func ParseRecordFilter(values url.Values) RecordFilter {
+	f := RecordFilter{
+		Search: values.Get("search"),
+		Sort:   SortRecent,
+	}
+
+	if v, err := strconv.ParseFloat(values.Get("min_rating"), 64); err == nil && IsValidRating(v) {
+		f.MinRating = v
+	}
+
+	if tag := NormalizeTag(values.Get("tag")); tag != "" {
+		f.Tag = tag
+	}
+
+	if conditions, err := ParseFilterExpression(values.Get("filter")); err == nil {
+		f.Conditions = conditions
+	}
+
+	for _, option := range SortOptions {
+		if option.Value == values.Get("sort") {
+			f.Sort = option.Value
+		}
+	}
+
+	return f
+}
+
+// Tipos de campo admitidos en las expresiones de filtro
+const (
+	FilterKindText   = "text"
+	FilterKindNumber = "number"
+	FilterKindList   = "list"
+	FilterKindTag    = "tag"
+	FilterKindCustom = "custom"
+)
+
+// CustomFieldFilterPrefix antecede a la clave de un campo personalizado en
+// las expresiones de filtro, por ejemplo campos.firmado=true
+const CustomFieldFilterPrefix = "campos."
+
+// FilterFields asocia cada campo de las expresiones de filtro con su tipo
+var FilterFields = map[string]string{
+	"titulo":         FilterKindText,
+	"artista":        FilterKindText,
+	"sello":          FilterKindText,
+	"catalog_number": FilterKindText,
+	"formato":        FilterKindText,
+	"pais":           FilterKindText,
+	"condicion":      FilterKindText,
+	"anio":           FilterKindNumber,
+	"rating":         FilterKindNumber,
+	"genero":         FilterKindList,
+	"estilo":         FilterKindList,
+	"tag":            FilterKindTag,
+}
+
+// filterOperators lista los operadores válidos para cada tipo de campo.
+// "~" significa "contiene".
+var filterOperators = map[string][]string{
+	FilterKindText:   {"=", "!=", "~"},
+	FilterKindNumber: {"=", "!=", "<", "<=", ">", ">="},
+	FilterKindList:   {"=", "!="},
+	FilterKindTag:    {"=", "!="},
+	FilterKindCustom: {"=", "!=", "<", "<=", ">", ">=", "~"},
+}
+
+// FilterFieldKind retorna el tipo de un campo de las expresiones de filtro
+func FilterFieldKind(field string) (string, bool) {
+	if clave, ok := strings.CutPrefix(field, CustomFieldFilterPrefix); ok {
+		return FilterKindCustom, clave != ""
+	}
+	kind, ok := FilterFields[field]
+	return kind, ok
+}
+
+// FilterCondition es una condición de una expresión de filtro, por
+// ejemplo anio<1980
+type FilterCondition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// String formatea la condición como texto, con comillas si el valor tiene espacios
+func (c FilterCondition) String() string {
+	value := c.Value
+	if strings.ContainsAny(value, " \t") {
+		value = strconv.Quote(value)
+	}
+	return c.Field + c.Op + value
+}
+
+// FormatFilterExpression une condiciones en una expresión de filtro
+func FormatFilterExpression(conditions []FilterCondition) string {
+	parts := make([]string, len(conditions))
+	for i, c := range conditions {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " and ")
+}
+
+// Los valores sin comillas no pueden empezar con un operador, para que
+// "anio==1980" o "artista<>X" sean un error y no una búsqueda de "=1980"
+var (
+	filterConditionPattern = regexp.MustCompile(`^\s*([a-z_]+(?:\.[a-z0-9_]+)?)\s*(<=|>=|!=|=|<|>|~)\s*("(?:[^"\\]|\\.)*"|[^\s"<>=!~][^\s"]*)`)
+	filterAndPattern       = regexp.MustCompile(`(?i)^\s+and\s+`)
+)
+
+// ParseFilterExpression interpreta una expresión como
+// `formato=LP and anio<1980 and tag="needs cleaning"`. Las condiciones se
+// combinan con "and"; los valores con espacios van entre comillas.
+func ParseFilterExpression(expr string) ([]FilterCondition, error) {
+	rest := strings.TrimSpace(expr)
+	if rest == "" {
+		return nil, nil
+	}
+
+	var conditions []FilterCondition
+	for {
+		match := filterConditionPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("condición inválida cerca de %q", rest)
+		}
+
+		condition := FilterCondition{Field: match[1], Op: match[2], Value: match[3]}
+		if strings.HasPrefix(condition.Value, `"`) {
+			value, err := strconv.Unquote(condition.Value)
+			if err != nil {
+				return nil, fmt.Errorf("valor inválido %s", condition.Value)
+			}
+			condition.Value = value
+		}
+
+		if err := condition.validate(); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+
+		rest = rest[len(match[0]):]
+		if strings.TrimSpace(rest) == "" {
+			return conditions, nil
+		}
+
+		sep := filterAndPattern.FindString(rest)
+		if sep == "" {
+			return nil, fmt.Errorf("se esperaba \"and\" cerca de %q", strings.TrimSpace(rest))
+		}
+		rest = rest[len(sep):]
+	}
+}
+
+// validate verifica que el campo exista y admita el operador y el valor
+func (c FilterCondition) validate() error {
+	kind, ok := FilterFieldKind(c.Field)
+	if !ok {
+		return fmt.Errorf("campo desconocido: %s", c.Field)
+	}
+
+	valid := false
+	for _, op := range filterOperators[kind] {
+		if op == c.Op {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("operador %s no válido para %s", c.Op, c.Field)
+	}
+
+	if kind == FilterKindNumber {
+		if _, err := strconv.ParseFloat(c.Value, 64); err != nil {
+			return fmt.Errorf("%s debe ser un número: %s", c.Field, c.Value)
+		}
+	}
+
+	return nil
+}
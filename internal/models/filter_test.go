@@ -0,0 +1,86 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseFilterExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []FilterCondition
+		wantErr string // parte del mensaje de error, vacío si es válida
+	}{
+		{expr: "", want: nil},
+		{expr: "   ", want: nil},
+		{expr: "formato=LP", want: []FilterCondition{{"formato", "=", "LP"}}},
+		{
+			expr: `formato=LP and anio<1980 AND tag="needs cleaning"`,
+			want: []FilterCondition{{"formato", "=", "LP"}, {"anio", "<", "1980"}, {"tag", "=", "needs cleaning"}},
+		},
+		{expr: "  rating >= 4.5  ", want: []FilterCondition{{"rating", ">=", "4.5"}}},
+		{expr: "artista~beatles and sello!=Apple", want: []FilterCondition{{"artista", "~", "beatles"}, {"sello", "!=", "Apple"}}},
+		{expr: `titulo="Say \"Hello\""`, want: []FilterCondition{{"titulo", "=", `Say "Hello"`}}},
+		{expr: `pais=""`, want: []FilterCondition{{"pais", "=", ""}}},
+		{expr: "campos.firmado=true", want: []FilterCondition{{"campos.firmado", "=", "true"}}},
+		{expr: "campos.copias_2>=3", want: []FilterCondition{{"campos.copias_2", ">=", "3"}}},
+
+		// Campos desconocidos o con caracteres que no se admiten
+		{expr: "precio<10", wantErr: "campo desconocido: precio"},
+		{expr: "Artista=Nirvana", wantErr: "condición inválida"},
+		{expr: "campos.=1", wantErr: "condición inválida"},
+		{expr: "campos.x')=1", wantErr: "condición inválida"},
+		{expr: "campos.a.b=1", wantErr: "condición inválida"},
+		{expr: "records.artista=Nirvana", wantErr: "campo desconocido"},
+
+		// Comillas
+		{expr: `titulo="Nevermind`, wantErr: "condición inválida"},
+		{expr: `titulo="Abbey Road"x`, wantErr: `se esperaba "and"`},
+		{expr: `titulo="\q"`, wantErr: "valor inválido"},
+		{expr: "titulo=Abbey Road", wantErr: `se esperaba "and" cerca de "Road"`},
+
+		// Operadores
+		{expr: "anio~19", wantErr: "operador ~ no válido para anio"},
+		{expr: "artista<M", wantErr: "operador < no válido para artista"},
+		{expr: "tag~jazz", wantErr: "operador ~ no válido para tag"},
+		{expr: "anio=setenta", wantErr: "anio debe ser un número"},
+		{expr: "anio==1980", wantErr: "condición inválida"},
+		{expr: "anio<>1980", wantErr: "condición inválida"},
+		{expr: "artista==Nirvana", wantErr: "condición inválida"},
+		{expr: `artista="=Nirvana"`, want: []FilterCondition{{"artista", "=", "=Nirvana"}}},
+		{expr: "anio 1980", wantErr: "condición inválida"},
+		{expr: "formato=LP or formato=CD", wantErr: `se esperaba "and"`},
+		{expr: "formato=LP and", wantErr: `se esperaba "and"`},
+		{expr: "formato=LP and and anio<1980", wantErr: "condición inválida"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFilterExpression(tt.expr)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseFilterExpression(%q) error = %v, se esperaba %q", tt.expr, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseFilterExpression(%q) error inesperado: %v", tt.expr, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseFilterExpression(%q) = %v, se esperaba %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFilterExpressionRoundTrip(t *testing.T) {
+	conditions := []FilterCondition{{"formato", "=", "LP"}, {"tag", "=", "needs cleaning"}, {"campos.firmado", "=", "true"}}
+	expr := FormatFilterExpression(conditions)
+	if expr != `formato=LP and tag="needs cleaning" and campos.firmado=true` {
+		t.Errorf("expresión = %s", expr)
+	}
+	got, err := ParseFilterExpression(expr)
+	if err != nil || !slices.Equal(got, conditions) {
+		t.Errorf("ParseFilterExpression(%q) = %v (%v), se esperaba %v", expr, got, err, conditions)
+	}
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Acciones que quedan registradas en el historial de un record
+const (
+	HistoryCreate  = "crear"
+	HistoryUpdate  = "editar"
+	HistoryDelete  = "eliminar"
+	HistoryRestore = "restaurar"
+	HistoryRevert  = "revertir"
+	HistoryMerge   = "fusionar"
+	HistoryPurge   = "purgar"
+)
+
+// SystemActor es el actor de los cambios que no hizo una persona, p. ej. el
+// seed o los procesos en segundo plano sin actor propio
+const SystemActor = "sistema"
+
+// HistoryMergedInto es el cambio con el que queda en el historial del record
+// descartado en una fusión el ID del record en que se fusionó
+const HistoryMergedInto = "fusionado_en"
+
+// HistoryFields lista los campos del record que se comparan entre versiones,
+// en el orden en que se muestran
+var HistoryFields = append(append([]string{}, MergeFields...),
+	"location_id", "rating", "review", "track_ratings", "campos")
+
+// FieldChange es el valor anterior y el nuevo de un campo del record
+type FieldChange struct {
+	Campo   string `json:"campo"`
+	Antes   string `json:"antes"`
+	Despues string `json:"despues"`
+}
+
+// GetFieldLabel retorna el nombre legible del campo
+func (c FieldChange) GetFieldLabel() string {
+	return HistoryFieldLabel(c.Campo)
+}
+
+// RecordVersion es una entrada del historial de un record: quién hizo el
+// cambio, qué campos cambiaron y el record completo tal como quedó
+type RecordVersion struct {
+	ID        string         `json:"id" db:"id"`
+	RecordID  string         `json:"record_id" db:"record_id"`
+	Numero    int            `json:"numero" db:"numero"`
+	Accion    string         `json:"accion" db:"accion"`
+	Actor     string         `json:"actor" db:"actor"`
+	Cambios   sql.NullString `json:"cambios" db:"cambios"`   // JSON array de FieldChange
+	Snapshot  string         `json:"snapshot" db:"snapshot"` // JSON del record
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewRecordVersion crea una versión con ID generado a partir del record
+// resultante y los campos que cambiaron
+func NewRecordVersion(record *Record, accion, actor string, changes []FieldChange) (*RecordVersion, error) {
+	snapshot, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("error serializando record: %w", err)
+	}
+
+	version := &RecordVersion{
+		ID:        uuid.New().String(),
+		RecordID:  record.ID,
+		Accion:    accion,
+		Actor:     actor,
+		Snapshot:  string(snapshot),
+		CreatedAt: time.Now(),
+	}
+	if len(changes) > 0 {
+		data, err := json.Marshal(changes)
+		if err != nil {
+			return nil, fmt.Errorf("error serializando cambios: %w", err)
+		}
+		version.Cambios = sql.NullString{String: string(data), Valid: true}
+	}
+	return version, nil
+}
+
+// GetChanges retorna los campos que cambiaron en esta versión
+func (v *RecordVersion) GetChanges() []FieldChange {
+	var changes []FieldChange
+	if v.Cambios.Valid {
+		_ = json.Unmarshal([]byte(v.Cambios.String), &changes)
+	}
+	return changes
+}
+
+// GetRecord retorna el record tal como quedó en esta versión
+func (v *RecordVersion) GetRecord() (*Record, error) {
+	var record Record
+	if err := json.Unmarshal([]byte(v.Snapshot), &record); err != nil {
+		return nil, fmt.Errorf("versión %d ilegible: %w", v.Numero, err)
+	}
+	return &record, nil
+}
+
+// GetActionLabel retorna la acción de la versión para mostrar
+func (v *RecordVersion) GetActionLabel() string {
+	switch v.Accion {
+	case HistoryCreate:
+		return "Creado"
+	case HistoryUpdate:
+		return "Editado"
+	case HistoryDelete:
+		return "Enviado a la papelera"
+	case HistoryRestore:
+		return "Restaurado"
+	case HistoryRevert:
+		return "Revertido"
+	case HistoryMerge:
+		return "Fusionado con un duplicado"
+	case HistoryPurge:
+		return "Eliminado definitivamente"
+	}
+	return v.Accion
+}
+
+// HistoryFieldLabel retorna el nombre legible de un campo del historial
+func HistoryFieldLabel(campo string) string {
+	switch campo {
+	case "location_id":
+		return "Ubicación"
+	case "rating":
+		return "Calificación"
+	case "review":
+		return "Reseña"
+	case "track_ratings":
+		return "Calificaciones de tracks"
+	case "campos":
+		return "Campos personalizados"
+	case "tags":
+		return "Tags"
+	case HistoryMergedInto:
+		return "Fusionado en"
+	}
+	return MergeFieldLabel(campo)
+}
+
+// HistoryValue retorna el valor de un campo del historial como texto
+// comparable entre versiones
+func (r *Record) HistoryValue(campo string) string {
+	switch campo {
+	case "location_id":
+		return r.LocationID.String
+	case "rating":
+		if r.Rating.Valid {
+			return FormatRatingValue(r.Rating.Float64)
+		}
+		return ""
+	case "review":
+		return r.Review.String
+	case "track_ratings":
+		var lines []string
+		for _, track := range r.GetTracklistAsSlice() {
+			if track.Rating == 0 && track.Review == "" {
+				continue
+			}
+			line := track.GetPosition() + ". " + track.Titulo + ":"
+			if track.Rating > 0 {
+				line += " " + FormatStars(track.Rating)
+			}
+			if track.Review != "" {
+				line += " " + track.Review
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "\n")
+	case "campos":
+		campos := r.GetCampos()
+		if len(campos) == 0 {
+			return ""
+		}
+		// json.Marshal ordena las claves, así que el texto es comparable
+		data, _ := json.Marshal(campos)
+		return string(data)
+	}
+	return r.MergeValue(campo)
+}
+
+// FormatHistoryValue retorna un valor del historial para mostrar
+func FormatHistoryValue(campo, valor string) string {
+	switch campo {
+	case "rating":
+		if rating, err := strconv.ParseFloat(valor, 64); err == nil {
+			return FormatStars(rating)
+		}
+		return valor
+	case "campos":
+		var campos map[string]any
+		if err := json.Unmarshal([]byte(valor), &campos); err != nil {
+			return valor
+		}
+		lines := make([]string, 0, len(campos))
+		for clave, v := range campos {
+			lines = append(lines, fmt.Sprintf("%s: %v", clave, v))
+		}
+		sort.Strings(lines)
+		return strings.Join(lines, "\n")
+	}
+	return FormatMetadataValue(campo, valor)
+}
+
+// DiffHistory compara los campos del historial de dos versiones de un
+// record. Con before nil, cada campo con valor cuenta como cambio.
+func DiffHistory(before, after *Record) []FieldChange {
+	var changes []FieldChange
+	for _, campo := range HistoryFields {
+		var antes string
+		if before != nil {
+			antes = before.HistoryValue(campo)
+		}
+		if despues := after.HistoryValue(campo); antes != despues {
+			changes = append(changes, FieldChange{Campo: campo, Antes: antes, Despues: despues})
+		}
+	}
+	return changes
+}
+
+// RevertTo copia en el record los campos editables de una versión anterior.
+// El ID, la fecha de creación y el estado en la papelera no cambian.
+func (r *Record) RevertTo(version *Record) {
+	r.Titulo = version.Titulo
+	r.Artista = version.Artista
+	r.Sello = version.Sello
+	r.CatalogNumber = version.CatalogNumber
+	r.Anio = version.Anio
+	r.Formato = version.Formato
+	r.Generos = version.Generos
+	r.Estilos = version.Estilos
+	r.Pais = version.Pais
+	r.Tracklist = version.Tracklist
+	r.DuracionTotal = version.DuracionTotal
+	r.ArteURL = version.ArteURL
+	r.Condicion = version.Condicion
+	r.Notas = version.Notas
+	r.LocationID = version.LocationID
+	r.Rating = version.Rating
+	r.Review = version.Review
+	r.Campos = version.Campos
+	r.ColorDominante = version.ColorDominante
+	r.ColorAcento = version.ColorAcento
+}
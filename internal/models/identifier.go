@@ -0,0 +1,154 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de identificador de un record
+const (
+	IdentifierBarcode     = "barcode"
+	IdentifierMatrix      = "matrix"
+	IdentifierDiscogs     = "discogs"
+	IdentifierMusicBrainz = "musicbrainz"
+)
+
+// IdentifierTypes lista los tipos de identificador válidos
+var IdentifierTypes = []string{
+	IdentifierBarcode,
+	IdentifierMatrix,
+	IdentifierDiscogs,
+	IdentifierMusicBrainz,
+}
+
+// Identifier representa un identificador de un record: código de barras,
+// inscripción de matriz/runout o ID en un catálogo externo
+type Identifier struct {
+	ID          string         `json:"id" db:"id"`
+	RecordID    string         `json:"record_id" db:"record_id"`
+	Tipo        string         `json:"tipo" db:"tipo"`
+	Valor       string         `json:"valor" db:"valor"`
+	Descripcion sql.NullString `json:"descripcion" db:"descripcion"` // p. ej. "Lado A" para una matriz
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewIdentifier crea un nuevo identificador validando y normalizando su valor
+func NewIdentifier(recordID, tipo, valor string) (*Identifier, error) {
+	normalized, err := NormalizeIdentifier(tipo, valor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Identifier{
+		ID:        uuid.New().String(),
+		RecordID:  recordID,
+		Tipo:      tipo,
+		Valor:     normalized,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// IdentifierTypeLabel retorna el nombre legible de un tipo de identificador
+func IdentifierTypeLabel(tipo string) string {
+	switch tipo {
+	case IdentifierBarcode:
+		return "Código de barras"
+	case IdentifierMatrix:
+		return "Matriz / Runout"
+	case IdentifierDiscogs:
+		return "Discogs"
+	case IdentifierMusicBrainz:
+		return "MusicBrainz"
+	}
+	return tipo
+}
+
+// GetTypeLabel retorna el nombre legible del tipo del identificador
+func (i *Identifier) GetTypeLabel() string {
+	return IdentifierTypeLabel(i.Tipo)
+}
+
+// GetURL retorna el enlace al catálogo externo, o vacío si no aplica
+func (i *Identifier) GetURL() string {
+	switch i.Tipo {
+	case IdentifierDiscogs:
+		return "https://www.discogs.com/release/" + i.Valor
+	case IdentifierMusicBrainz:
+		return "https://musicbrainz.org/release/" + i.Valor
+	}
+	return ""
+}
+
+// CompactIdentifier quita espacios y guiones, como se escriben a menudo
+// los códigos de barras ("0 77774 64402 0")
+func CompactIdentifier(valor string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(valor))
+}
+
+// NormalizeIdentifier valida el valor de un identificador según su tipo y
+// lo retorna en su forma canónica
+func NormalizeIdentifier(tipo, valor string) (string, error) {
+	valor = strings.TrimSpace(valor)
+	if valor == "" {
+		return "", fmt.Errorf("el identificador está vacío")
+	}
+
+	switch tipo {
+	case IdentifierBarcode:
+		code := CompactIdentifier(valor)
+		if !IsValidBarcode(code) {
+			return "", fmt.Errorf("código de barras EAN/UPC inválido: %s", valor)
+		}
+		return code, nil
+	case IdentifierMatrix:
+		return strings.Join(strings.Fields(valor), " "), nil
+	case IdentifierDiscogs:
+		// Se acepta el formato de Discogs "[r123456]" o "r123456"
+		id := strings.TrimPrefix(strings.Trim(valor, "[]"), "r")
+		if n, err := strconv.ParseUint(id, 10, 64); err != nil || n == 0 {
+			return "", fmt.Errorf("ID de release de Discogs inválido: %s", valor)
+		}
+		return id, nil
+	case IdentifierMusicBrainz:
+		mbid, err := uuid.Parse(valor)
+		if err != nil {
+			return "", fmt.Errorf("MBID de MusicBrainz inválido: %s", valor)
+		}
+		return mbid.String(), nil
+	}
+
+	return "", fmt.Errorf("tipo de identificador inválido: %s", tipo)
+}
+
+// IsValidBarcode verifica un código EAN-8, UPC-A (12 dígitos) o EAN-13,
+// incluido su dígito de control
+func IsValidBarcode(code string) bool {
+	if len(code) != 8 && len(code) != 12 && len(code) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		if i == len(code)-1 {
+			break
+		}
+		// Los pesos alternan 3 y 1 empezando desde el dígito anterior al de control
+		digit := int(c - '0')
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := (10 - sum%10) % 10
+	return int(code[len(code)-1]-'0') == check
+}
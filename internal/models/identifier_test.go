@@ -0,0 +1,42 @@
+package models
+
+import "testing"
+
+func TestIsValidBarcode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"4006381333931", true},  // EAN-13
+		{"036000291452", true},   // UPC-A
+		{"73513537", true},       // EAN-8
+		{"077774644020", true},   // UPC-A con 0 inicial
+		{"4006381333932", false}, // dígito de control incorrecto
+		{"036000291453", false},
+		{"73513538", false},
+		{"40063813339", false}, // 11 dígitos
+		{"400638133393", false},
+		{"4006381A33931", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValidBarcode(tt.code); got != tt.want {
+			t.Errorf("IsValidBarcode(%q) = %v, se esperaba %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeIdentifierBarcode(t *testing.T) {
+	got, err := NormalizeIdentifier(IdentifierBarcode, " 0 77774 64402-0 ")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got != "077774644020" {
+		t.Errorf("código normalizado = %q, se esperaba %q", got, "077774644020")
+	}
+
+	if _, err := NormalizeIdentifier(IdentifierBarcode, "0 77774 64402 1"); err == nil {
+		t.Error("se esperaba un error para un dígito de control incorrecto")
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportProfile guarda cómo se mapean las columnas de un archivo CSV/JSON
+// a los campos de un record, para reutilizarlo en próximas importaciones
+type ImportProfile struct {
+	ID        string    `json:"id" db:"id"`
+	Nombre    string    `json:"nombre" db:"nombre"`
+	Mapeo     string    `json:"mapeo" db:"mapeo"` // JSON object columna → campo
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NewImportProfile crea un nuevo perfil de importación con ID generado
+func NewImportProfile(nombre string, mapeo map[string]string) *ImportProfile {
+	profile := &ImportProfile{
+		ID:        uuid.New().String(),
+		Nombre:    nombre,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	profile.SetMapeo(mapeo)
+	return profile
+}
+
+// GetMapeo retorna el campo asignado a cada columna
+func (p *ImportProfile) GetMapeo() map[string]string {
+	mapeo := map[string]string{}
+	if err := json.Unmarshal([]byte(p.Mapeo), &mapeo); err != nil {
+		return map[string]string{}
+	}
+	return mapeo
+}
+
+// SetMapeo establece el mapeo de columnas, omitiendo las columnas ignoradas
+func (p *ImportProfile) SetMapeo(mapeo map[string]string) {
+	for columna, campo := range mapeo {
+		if campo == "" {
+			delete(mapeo, columna)
+		}
+	}
+
+	data, err := json.Marshal(mapeo)
+	if err != nil {
+		data = []byte("{}")
+	}
+	p.Mapeo = string(data)
+}
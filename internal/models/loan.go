@@ -0,0 +1,49 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Loan representa el préstamo de un record a otra persona
+type Loan struct {
+	ID               string         `json:"id" db:"id"`
+	RecordID         string         `json:"record_id" db:"record_id"`
+	Prestatario      string         `json:"prestatario" db:"prestatario"`
+	FechaSalida      time.Time      `json:"fecha_salida" db:"fecha_salida"`
+	FechaVencimiento sql.NullTime   `json:"fecha_vencimiento" db:"fecha_vencimiento"`
+	FechaDevolucion  sql.NullTime   `json:"fecha_devolucion" db:"fecha_devolucion"`
+	Notas            sql.NullString `json:"notas" db:"notas"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// NewLoan crea un nuevo préstamo con ID generado
+func NewLoan() *Loan {
+	return &Loan{
+		ID:          uuid.New().String(),
+		FechaSalida: time.Now(),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// IsActive indica si el disco aún no ha sido devuelto
+func (l *Loan) IsActive() bool {
+	return !l.FechaDevolucion.Valid
+}
+
+// IsOverdue indica si el préstamo sigue activo después de su fecha de vencimiento
+func (l *Loan) IsOverdue(now time.Time) bool {
+	return l.IsActive() && l.FechaVencimiento.Valid && l.FechaVencimiento.Time.Before(now)
+}
+
+// GetDueDate retorna la fecha de vencimiento formateada o "Sin fecha"
+func (l *Loan) GetDueDate() string {
+	if l.FechaVencimiento.Valid {
+		return l.FechaVencimiento.Time.Format("02/01/2006")
+	}
+	return "Sin fecha"
+}
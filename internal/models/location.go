@@ -0,0 +1,153 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de ubicación física, de mayor a menor
+const (
+	LocationTypeRoom  = "room"
+	LocationTypeShelf = "shelf"
+	LocationTypeBox   = "box"
+	LocationTypeSlot  = "slot"
+)
+
+// LocationTypes lista los tipos de ubicación en orden jerárquico
+var LocationTypes = []string{
+	LocationTypeRoom,
+	LocationTypeShelf,
+	LocationTypeBox,
+	LocationTypeSlot,
+}
+
+// Location representa un lugar físico donde se guardan records
+// (habitación, repisa, caja o posición). Las ubicaciones forman una
+// jerarquía a través de ParentID.
+type Location struct {
+	ID        string         `json:"id" db:"id"`
+	Nombre    string         `json:"nombre" db:"nombre"`
+	Tipo      string         `json:"tipo" db:"tipo"`
+	ParentID  sql.NullString `json:"parent_id" db:"parent_id"`
+	Notas     sql.NullString `json:"notas" db:"notas"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// NewLocation crea una nueva ubicación con ID generado
+func NewLocation() *Location {
+	return &Location{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// IsValidLocationType indica si el tipo de ubicación es conocido
+func IsValidLocationType(tipo string) bool {
+	for _, t := range LocationTypes {
+		if t == tipo {
+			return true
+		}
+	}
+	return false
+}
+
+// LocationTypeLabel retorna el nombre legible de un tipo de ubicación
+func LocationTypeLabel(tipo string) string {
+	switch tipo {
+	case LocationTypeRoom:
+		return "Habitación"
+	case LocationTypeShelf:
+		return "Repisa"
+	case LocationTypeBox:
+		return "Caja"
+	case LocationTypeSlot:
+		return "Posición"
+	}
+	return tipo
+}
+
+// GetTypeLabel retorna el nombre legible del tipo de ubicación
+func (l *Location) GetTypeLabel() string {
+	return LocationTypeLabel(l.Tipo)
+}
+
+// FormatLocationPath une una ruta de ubicaciones (de la raíz a la hoja)
+// en un texto legible, por ejemplo "Living › Repisa A › Caja 3"
+func FormatLocationPath(path []*Location) string {
+	names := make([]string, 0, len(path))
+	for _, l := range path {
+		names = append(names, l.Nombre)
+	}
+	return strings.Join(names, " › ")
+}
+
+// LocationNode es una ubicación junto a su profundidad en la jerarquía
+type LocationNode struct {
+	*Location
+	Depth int
+}
+
+// FlattenLocationTree ordena las ubicaciones en profundidad (cada padre
+// seguido de sus hijos) para mostrarlas como árbol indentado
+func FlattenLocationTree(locations []*Location) []LocationNode {
+	children := make(map[string][]*Location)
+	known := make(map[string]bool, len(locations))
+	for _, l := range locations {
+		known[l.ID] = true
+	}
+
+	var roots []*Location
+	for _, l := range locations {
+		if l.ParentID.Valid && known[l.ParentID.String] {
+			children[l.ParentID.String] = append(children[l.ParentID.String], l)
+		} else {
+			roots = append(roots, l)
+		}
+	}
+
+	nodes := make([]LocationNode, 0, len(locations))
+	visited := make(map[string]bool, len(locations))
+	var walk func(l *Location, depth int)
+	walk = func(l *Location, depth int) {
+		if visited[l.ID] {
+			return
+		}
+		visited[l.ID] = true
+		nodes = append(nodes, LocationNode{Location: l, Depth: depth})
+		for _, child := range children[l.ID] {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return nodes
+}
+
+// WithoutLocationSubtree retorna el árbol sin la ubicación indicada ni sus
+// sub-ubicaciones, que no pueden ser su contenedora. nodes debe venir de
+// FlattenLocationTree, donde cada sub-árbol queda contiguo.
+func WithoutLocationSubtree(nodes []LocationNode, id string) []LocationNode {
+	result := make([]LocationNode, 0, len(nodes))
+	skipDepth := -1
+	for _, node := range nodes {
+		if skipDepth >= 0 {
+			if node.Depth > skipDepth {
+				continue
+			}
+			skipDepth = -1
+		}
+		if node.ID == id {
+			skipDepth = node.Depth
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
@@ -0,0 +1,305 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/vinilo/internal/artwork"
+)
+
+// Estados de un cambio de metadatos propuesto
+const (
+	ChangePending  = "pendiente"
+	ChangeAccepted = "aceptado"
+	ChangeRejected = "rechazado"
+)
+
+// MetadataFields lista los campos del record que se re-sincronizan con el
+// proveedor de metadatos, en el orden en que se revisan
+var MetadataFields = []string{
+	"titulo",
+	"artista",
+	"sello",
+	"catalog_number",
+	"anio",
+	"formato",
+	"pais",
+	"generos",
+	"estilos",
+	"tracklist",
+	"duracion_total",
+	"arte_url",
+}
+
+// MetadataSync es el estado de la re-sincronización de un record con su
+// proveedor de metadatos, incluidos los valores de mercado
+type MetadataSync struct {
+	RecordID     string          `json:"record_id" db:"record_id"`
+	Provider     string          `json:"provider" db:"provider"`
+	ExternalID   string          `json:"external_id" db:"external_id"`
+	SyncedAt     time.Time       `json:"synced_at" db:"synced_at"`
+	Error        sql.NullString  `json:"error" db:"error"`
+	PrecioMinimo sql.NullFloat64 `json:"precio_minimo" db:"precio_minimo"`
+	Moneda       sql.NullString  `json:"moneda" db:"moneda"`
+	EnVenta      sql.NullInt32   `json:"en_venta" db:"en_venta"`
+}
+
+// GetMarketValue retorna el precio más bajo a la venta formateado, o
+// vacío si el proveedor no informa valores de mercado
+func (s *MetadataSync) GetMarketValue() string {
+	if !s.PrecioMinimo.Valid {
+		return ""
+	}
+	value := fmt.Sprintf("$%.2f", s.PrecioMinimo.Float64)
+	if s.Moneda.Valid && s.Moneda.String != "" {
+		value += " " + s.Moneda.String
+	}
+	if s.EnVenta.Valid {
+		value += fmt.Sprintf(" (%d a la venta)", s.EnVenta.Int32)
+	}
+	return value
+}
+
+// MetadataChange es un cambio de un campo del record propuesto por el
+// proveedor de metadatos, que se acepta o rechaza desde el admin
+type MetadataChange struct {
+	ID          string         `json:"id" db:"id"`
+	RecordID    string         `json:"record_id" db:"record_id"`
+	Provider    string         `json:"provider" db:"provider"`
+	Campo       string         `json:"campo" db:"campo"`
+	ValorActual sql.NullString `json:"valor_actual" db:"valor_actual"`
+	ValorNuevo  string         `json:"valor_nuevo" db:"valor_nuevo"`
+	Estado      string         `json:"estado" db:"estado"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	ResolvedAt  sql.NullTime   `json:"resolved_at" db:"resolved_at"`
+}
+
+// NewMetadataChange crea un cambio pendiente con ID generado
+func NewMetadataChange(recordID, provider, campo, valorActual, valorNuevo string) *MetadataChange {
+	return &MetadataChange{
+		ID:          uuid.New().String(),
+		RecordID:    recordID,
+		Provider:    provider,
+		Campo:       campo,
+		ValorActual: sql.NullString{String: valorActual, Valid: valorActual != ""},
+		ValorNuevo:  valorNuevo,
+		Estado:      ChangePending,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// GetFieldLabel retorna el nombre legible del campo del cambio
+func (c *MetadataChange) GetFieldLabel() string {
+	return MetadataFieldLabel(c.Campo)
+}
+
+// GetCurrentValue retorna el valor actual del campo para mostrar
+func (c *MetadataChange) GetCurrentValue() string {
+	return FormatMetadataValue(c.Campo, c.ValorActual.String)
+}
+
+// GetNewValue retorna el valor propuesto para mostrar
+func (c *MetadataChange) GetNewValue() string {
+	return FormatMetadataValue(c.Campo, c.ValorNuevo)
+}
+
+// MetadataFieldLabel retorna el nombre legible de un campo re-sincronizable
+func MetadataFieldLabel(campo string) string {
+	switch campo {
+	case "titulo":
+		return "Título"
+	case "artista":
+		return "Artista"
+	case "sello":
+		return "Sello"
+	case "catalog_number":
+		return "Número de catálogo"
+	case "anio":
+		return "Año"
+	case "formato":
+		return "Formato"
+	case "pais":
+		return "País"
+	case "generos":
+		return "Géneros"
+	case "estilos":
+		return "Estilos"
+	case "tracklist":
+		return "Tracklist"
+	case "duracion_total":
+		return "Duración total"
+	case "arte_url":
+		return "Arte"
+	}
+	return campo
+}
+
+// MetadataValue retorna el valor de un campo re-sincronizable como se
+// guarda en un cambio: texto, el año como número o las listas en JSON.
+// El tracklist se retorna sin calificaciones ni reseñas.
+func (r *Record) MetadataValue(campo string) string {
+	switch campo {
+	case "titulo":
+		return r.Titulo
+	case "artista":
+		return r.Artista
+	case "sello":
+		return r.Sello.String
+	case "catalog_number":
+		return r.CatalogNumber.String
+	case "anio":
+		if r.Anio.Valid && r.Anio.Int32 > 0 {
+			return strconv.Itoa(int(r.Anio.Int32))
+		}
+		return ""
+	case "formato":
+		return r.Formato.String
+	case "pais":
+		return r.Pais.String
+	case "generos":
+		return r.Generos.String
+	case "estilos":
+		return r.Estilos.String
+	case "tracklist":
+		tracklist := r.GetTracklistAsSlice()
+		for i := range tracklist {
+			tracklist[i].Rating = 0
+			tracklist[i].Review = ""
+		}
+		return toJSONString(tracklist).String
+	case "duracion_total":
+		return r.DuracionTotal.String
+	case "arte_url":
+		return r.ArteURL.String
+	}
+	return ""
+}
+
+// SetMetadataValue aplica el valor de un cambio a un campo del record. Al
+// reemplazar el tracklist se conservan las calificaciones y reseñas de los
+// tracks con el mismo título.
+func (r *Record) SetMetadataValue(campo, valor string) error {
+	text := sql.NullString{String: valor, Valid: valor != ""}
+
+	switch campo {
+	case "titulo":
+		r.Titulo = valor
+	case "artista":
+		r.Artista = valor
+	case "sello":
+		r.Sello = text
+	case "catalog_number":
+		r.CatalogNumber = text
+	case "anio":
+		anio, err := strconv.Atoi(valor)
+		if err != nil {
+			return fmt.Errorf("año inválido: %s", valor)
+		}
+		r.Anio = sql.NullInt32{Int32: int32(anio), Valid: true}
+	case "formato":
+		r.Formato = text
+	case "pais":
+		r.Pais = text
+	case "generos", "estilos":
+		var list []string
+		if err := json.Unmarshal([]byte(valor), &list); err != nil {
+			return fmt.Errorf("lista inválida para %s: %w", campo, err)
+		}
+		if campo == "generos" {
+			r.SetGeneros(list)
+		} else {
+			r.SetEstilos(list)
+		}
+	case "tracklist":
+		var tracklist []Track
+		if err := json.Unmarshal([]byte(valor), &tracklist); err != nil {
+			return fmt.Errorf("tracklist inválido: %w", err)
+		}
+		previous := map[string]Track{}
+		for _, track := range r.GetTracklistAsSlice() {
+			previous[strings.ToLower(track.Titulo)] = track
+		}
+		for i, track := range tracklist {
+			if old, ok := previous[strings.ToLower(track.Titulo)]; ok {
+				tracklist[i].Rating = old.Rating
+				tracklist[i].Review = old.Review
+			}
+		}
+		r.SetTracklist(tracklist)
+	case "duracion_total":
+		r.DuracionTotal = text
+	case "arte_url":
+		r.ArteURL = text
+		// La paleta del arte anterior ya no aplica; se recalcula al copiar
+		// el arte nuevo al almacenamiento local
+		r.SetPalette(artwork.Palette{})
+	default:
+		return fmt.Errorf("campo no sincronizable: %s", campo)
+	}
+	return nil
+}
+
+// SameMetadataValue compara dos valores de un campo ignorando diferencias
+// de mayúsculas, espacios y orden de las listas, que no justifican
+// proponer un cambio
+func SameMetadataValue(campo, a, b string) bool {
+	return normalizeMetadataValue(campo, a) == normalizeMetadataValue(campo, b)
+}
+
+// normalizeMetadataValue lleva un valor a una forma comparable
+func normalizeMetadataValue(campo, valor string) string {
+	var parts []string
+	switch campo {
+	case "generos", "estilos":
+		_ = json.Unmarshal([]byte(valor), &parts)
+	case "tracklist":
+		var tracklist []Track
+		_ = json.Unmarshal([]byte(valor), &tracklist)
+		for _, track := range tracklist {
+			parts = append(parts, track.GetPosition()+"|"+track.Titulo+"|"+track.Duracion)
+		}
+	default:
+		parts = []string{valor}
+	}
+
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(part), " "))
+	}
+	// El orden de géneros y estilos no es significativo
+	if campo == "generos" || campo == "estilos" {
+		sort.Strings(parts)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// FormatMetadataValue retorna un valor de un campo re-sincronizable para
+// mostrar: las listas separadas por coma y el tracklist una línea por track
+func FormatMetadataValue(campo, valor string) string {
+	switch campo {
+	case "generos", "estilos":
+		var list []string
+		if err := json.Unmarshal([]byte(valor), &list); err == nil {
+			return strings.Join(list, ", ")
+		}
+	case "tracklist":
+		var tracklist []Track
+		if err := json.Unmarshal([]byte(valor), &tracklist); err == nil {
+			lines := make([]string, 0, len(tracklist))
+			for _, track := range tracklist {
+				line := track.GetPosition() + ". " + track.Titulo
+				if track.Duracion != "" {
+					line += " (" + track.Duracion + ")"
+				}
+				lines = append(lines, line)
+			}
+			return strings.Join(lines, "\n")
+		}
+	}
+	return valor
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Play representa una sesión de escucha de un record
+type Play struct {
+	ID          string         `json:"id" db:"id"`
+	RecordID    string         `json:"record_id" db:"record_id"`
+	EscuchadoEn time.Time      `json:"escuchado_en" db:"escuchado_en"`
+	Lados       sql.NullString `json:"lados" db:"lados"`
+	Notas       sql.NullString `json:"notas" db:"notas"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewPlay crea una nueva sesión de escucha con ID generado y hora actual
+func NewPlay() *Play {
+	return &Play{
+		ID:          uuid.New().String(),
+		EscuchadoEn: time.Now(),
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Vistas disponibles del registro de escucha
+const (
+	PlaysViewMostPlayed  = "most"
+	PlaysViewNeverPlayed = "never"
+	PlaysViewStale       = "stale"
+)
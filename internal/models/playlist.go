@@ -0,0 +1,143 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Playlist representa una lista ordenada de records (o tracks puntuales)
+// curada a mano, por ejemplo para un set de DJ o una fiesta de escucha
+type Playlist struct {
+	ID          string         `json:"id" db:"id"`
+	Nombre      string         `json:"nombre" db:"nombre"`
+	Descripcion sql.NullString `json:"descripcion" db:"descripcion"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+
+	// ItemCount se calcula al listar playlists
+	ItemCount int `json:"item_count,omitempty" db:"item_count"`
+}
+
+// NewPlaylist crea una nueva playlist con ID generado
+func NewPlaylist() *Playlist {
+	return &Playlist{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// PlaylistItem es una entrada de una playlist: un record completo o uno
+// de sus tracks
+type PlaylistItem struct {
+	ID          string         `json:"id" db:"id"`
+	PlaylistID  string         `json:"playlist_id" db:"playlist_id"`
+	RecordID    string         `json:"record_id" db:"record_id"`
+	TrackNumero sql.NullInt32  `json:"track_numero" db:"track_numero"`
+	Posicion    int            `json:"posicion" db:"posicion"`
+	Notas       sql.NullString `json:"notas" db:"notas"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+
+	// Record se completa al mostrar la playlist
+	Record *Record `json:"record,omitempty" db:"-"`
+}
+
+// NewPlaylistItem crea una nueva entrada de playlist con ID generado
+func NewPlaylistItem() *PlaylistItem {
+	return &PlaylistItem{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+}
+
+// Track retorna el track de la entrada, o nil si es el disco completo o
+// el track ya no existe en el tracklist
+func (i *PlaylistItem) Track() *Track {
+	if !i.TrackNumero.Valid || i.Record == nil {
+		return nil
+	}
+	for _, track := range i.Record.GetTracklistAsSlice() {
+		if track.Numero == int(i.TrackNumero.Int32) {
+			return &track
+		}
+	}
+	return nil
+}
+
+// GetLabel retorna el título a mostrar: el del track o el del disco
+func (i *PlaylistItem) GetLabel() string {
+	if track := i.Track(); track != nil {
+		return track.Titulo
+	}
+	if i.TrackNumero.Valid {
+		return fmt.Sprintf("Track %d", i.TrackNumero.Int32)
+	}
+	if i.Record != nil {
+		return i.Record.GetDisplayTitle()
+	}
+	return ""
+}
+
+// Duration calcula la duración de la entrada a partir del tracklist. Para
+// un disco completo suma sus tracks o, si no tienen duración, usa la
+// duración total del record.
+func (i *PlaylistItem) Duration() (time.Duration, bool) {
+	if i.Record == nil {
+		return 0, false
+	}
+
+	if i.TrackNumero.Valid {
+		track := i.Track()
+		if track == nil {
+			return 0, false
+		}
+		return ParseTrackDuration(track.Duracion)
+	}
+
+	var total time.Duration
+	tracks := i.Record.GetTracklistAsSlice()
+	complete := len(tracks) > 0
+	for _, track := range tracks {
+		d, ok := ParseTrackDuration(track.Duracion)
+		if !ok {
+			complete = false
+			break
+		}
+		total += d
+	}
+	if complete {
+		return total, true
+	}
+
+	if i.Record.DuracionTotal.Valid {
+		return ParseTrackDuration(i.Record.DuracionTotal.String)
+	}
+	return 0, false
+}
+
+// GetDuration retorna la duración formateada o una cadena vacía si se desconoce
+func (i *PlaylistItem) GetDuration() string {
+	if d, ok := i.Duration(); ok {
+		return FormatTrackDuration(d)
+	}
+	return ""
+}
+
+// PlaylistRuntime suma la duración de las entradas de una playlist y
+// retorna cuántas entradas no tienen duración conocida
+func PlaylistRuntime(items []*PlaylistItem) (time.Duration, int) {
+	var total time.Duration
+	missing := 0
+	for _, item := range items {
+		d, ok := item.Duration()
+		if !ok {
+			missing++
+			continue
+		}
+		total += d
+	}
+	return total, missing
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Límites de la calificación personal, en pasos de media estrella
+const (
+	MinRating  = 0.5
+	MaxRating  = 5.0
+	RatingStep = 0.5
+)
+
+// RatingOptions lista los valores de calificación válidos, de mayor a menor
+var RatingOptions = []float64{5, 4.5, 4, 3.5, 3, 2.5, 2, 1.5, 1, 0.5}
+
+// IsValidRating indica si la calificación está en rango y en pasos de media estrella
+func IsValidRating(rating float64) bool {
+	if rating < MinRating || rating > MaxRating {
+		return false
+	}
+	steps := rating / RatingStep
+	return steps == math.Trunc(steps)
+}
+
+// FormatStars representa una calificación como estrellas, por ejemplo "★★★½"
+func FormatStars(rating float64) string {
+	full := int(rating)
+	stars := strings.Repeat("★", full)
+	if rating-float64(full) >= RatingStep {
+		stars += "½"
+	}
+	return stars
+}
+
+// FormatRatingValue formatea una calificación para formularios y filtros ("4.5")
+func FormatRatingValue(rating float64) string {
+	return fmt.Sprintf("%g", rating)
+}
+
+// RatingStat es el promedio de calificación de un grupo de records
+// (un artista, un sello o un género)
+type RatingStat struct {
+	Nombre   string  `json:"nombre"`
+	Promedio float64 `json:"promedio"`
+	Cantidad int     `json:"cantidad"`
+}
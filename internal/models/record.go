@@ -3,30 +3,51 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rodrwan/vinilo/internal/artwork"
 )
 
 // Record representa un vinilo en la colección
 type Record struct {
-	ID            string         `json:"id" db:"id"`
-	Titulo        string         `json:"titulo" db:"titulo"`
-	Artista       string         `json:"artista" db:"artista"`
-	Sello         sql.NullString `json:"sello" db:"sello"`
-	CatalogNumber sql.NullString `json:"catalog_number" db:"catalog_number"`
-	Anio          sql.NullInt32  `json:"anio" db:"anio"`
-	Formato       sql.NullString `json:"formato" db:"formato"`
-	Generos       sql.NullString `json:"generos" db:"generos"`
-	Estilos       sql.NullString `json:"estilos" db:"estilos"`
-	Pais          sql.NullString `json:"pais" db:"pais"`
-	Tracklist     sql.NullString `json:"tracklist" db:"tracklist"`
-	DuracionTotal sql.NullString `json:"duracion_total" db:"duracion_total"`
-	ArteURL       sql.NullString `json:"arte_url" db:"arte_url"`
-	Condicion     sql.NullString `json:"condicion" db:"condicion"`
-	Notas         sql.NullString `json:"notas" db:"notas"`
-	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+	ID            string          `json:"id" db:"id"`
+	Titulo        string          `json:"titulo" db:"titulo"`
+	Artista       string          `json:"artista" db:"artista"`
+	Sello         sql.NullString  `json:"sello" db:"sello"`
+	CatalogNumber sql.NullString  `json:"catalog_number" db:"catalog_number"`
+	Anio          sql.NullInt32   `json:"anio" db:"anio"`
+	Formato       sql.NullString  `json:"formato" db:"formato"`
+	Generos       sql.NullString  `json:"generos" db:"generos"`
+	Estilos       sql.NullString  `json:"estilos" db:"estilos"`
+	Pais          sql.NullString  `json:"pais" db:"pais"`
+	Tracklist     sql.NullString  `json:"tracklist" db:"tracklist"`
+	DuracionTotal sql.NullString  `json:"duracion_total" db:"duracion_total"`
+	ArteURL       sql.NullString  `json:"arte_url" db:"arte_url"`
+	Condicion     sql.NullString  `json:"condicion" db:"condicion"`
+	Notas         sql.NullString  `json:"notas" db:"notas"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at" db:"updated_at"`
+	LocationID    sql.NullString  `json:"location_id" db:"location_id"`
+	Rating        sql.NullFloat64 `json:"rating" db:"rating"`
+	Review        sql.NullString  `json:"review" db:"review"`
+	Campos        sql.NullString  `json:"campos" db:"campos"` // JSON object con los campos personalizados
+
+	// Colores "#rrggbb" extraídos del arte (ver artwork.Palette)
+	ColorDominante sql.NullString `json:"color_dominante" db:"color_dominante"`
+	ColorAcento    sql.NullString `json:"color_acento" db:"color_acento"`
+
+	// Fecha en que se envió a la papelera; NULL si está en la colección
+	DeletedAt sql.NullTime `json:"deleted_at" db:"deleted_at"`
+
+	// Versión del record, incrementada con cada escritura. Una edición solo
+	// se guarda si el record sigue en la versión con que se leyó.
+	Version int `json:"version" db:"version"`
+
+	// Campos calculados al consultar a partir de otras tablas
+	OnLoan    bool `json:"on_loan" db:"on_loan"`
+	PlayCount int  `json:"play_count" db:"play_count"`
 }
 
 // RecordCreate representa los datos para crear un nuevo record
@@ -45,13 +66,28 @@ type RecordCreate struct {
 	ArteURL       string   `json:"arte_url"`
 	Condicion     string   `json:"condicion"`
 	Notas         string   `json:"notas"`
+	LocationID    string   `json:"location_id"`
+	Rating        float64  `json:"rating"`
+	Review        string   `json:"review"`
 }
 
 // Track representa una canción en el tracklist
 type Track struct {
-	Numero   int    `json:"numero"`
-	Titulo   string `json:"titulo"`
-	Duracion string `json:"duracion"`
+	Numero   int     `json:"numero"`
+	Posicion string  `json:"posicion,omitempty"` // posición en el disco, p. ej. "A1"
+	Titulo   string  `json:"titulo"`
+	Duracion string  `json:"duracion"`
+	Rating   float64 `json:"rating,omitempty"`
+	Review   string  `json:"review,omitempty"`
+}
+
+// GetPosition retorna la posición del track en el disco ("A1") o, si no
+// se conoce, su número
+func (t Track) GetPosition() string {
+	if t.Posicion != "" {
+		return t.Posicion
+	}
+	return strconv.Itoa(t.Numero)
 }
 
 // RecordUpdate representa los datos para actualizar un record
@@ -70,6 +106,9 @@ type RecordUpdate struct {
 	ArteURL       *string  `json:"arte_url"`
 	Condicion     *string  `json:"condicion"`
 	Notas         *string  `json:"notas"`
+	LocationID    *string  `json:"location_id"`
+	Rating        *float64 `json:"rating"`
+	Review        *string  `json:"review"`
 }
 
 // NewRecord crea un nuevo record con ID generado
@@ -78,6 +117,7 @@ func NewRecord() *Record {
 		ID:        uuid.New().String(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   1,
 	}
 }
 
@@ -122,56 +162,66 @@ func (r *Record) GetTracklistAsSlice() []Track {
 
 // SetGeneros convierte el slice de géneros a JSON string
 func (r *Record) SetGeneros(generos []string) {
-	if len(generos) == 0 {
-		r.Generos = sql.NullString{Valid: false}
-		return
-	}
+	r.Generos = toJSONString(generos)
+}
 
-	data, err := json.Marshal(generos)
-	if err != nil {
-		r.Generos = sql.NullString{Valid: false}
-		return
+// SetEstilos convierte el slice de estilos a JSON string
+func (r *Record) SetEstilos(estilos []string) {
+	r.Estilos = toJSONString(estilos)
+}
+
+// SetTracklist convierte el slice de tracks a JSON string
+func (r *Record) SetTracklist(tracklist []Track) {
+	r.Tracklist = toJSONString(tracklist)
+}
+
+// GetCampos retorna los valores de los campos personalizados por clave
+func (r *Record) GetCampos() map[string]any {
+	campos := map[string]any{}
+	if !r.Campos.Valid || r.Campos.String == "" {
+		return campos
 	}
 
-	r.Generos = sql.NullString{
-		String: string(data),
-		Valid:  true,
+	if err := json.Unmarshal([]byte(r.Campos.String), &campos); err != nil {
+		return map[string]any{}
 	}
+	return campos
 }
 
-// SetEstilos convierte el slice de estilos a JSON string
-func (r *Record) SetEstilos(estilos []string) {
-	if len(estilos) == 0 {
-		r.Estilos = sql.NullString{Valid: false}
-		return
+// SetCampos establece los valores de los campos personalizados, omitiendo los vacíos
+func (r *Record) SetCampos(campos map[string]any) {
+	for clave, valor := range campos {
+		if valor == nil {
+			delete(campos, clave)
+		}
 	}
 
-	data, err := json.Marshal(estilos)
-	if err != nil {
-		r.Estilos = sql.NullString{Valid: false}
+	if len(campos) == 0 {
+		r.Campos = sql.NullString{}
 		return
 	}
 
-	r.Estilos = sql.NullString{
-		String: string(data),
-		Valid:  true,
+	data, err := json.Marshal(campos)
+	if err != nil {
+		r.Campos = sql.NullString{}
+		return
 	}
+	r.Campos = sql.NullString{String: string(data), Valid: true}
 }
 
-// SetTracklist convierte el slice de tracks a JSON string
-func (r *Record) SetTracklist(tracklist []Track) {
-	if len(tracklist) == 0 {
-		r.Tracklist = sql.NullString{Valid: false}
-		return
+// toJSONString serializa una lista no vacía como JSON para guardarla en
+// columnas de texto; una lista vacía se guarda como NULL
+func toJSONString[T any](list []T) sql.NullString {
+	if len(list) == 0 {
+		return sql.NullString{Valid: false}
 	}
 
-	data, err := json.Marshal(tracklist)
+	data, err := json.Marshal(list)
 	if err != nil {
-		r.Tracklist = sql.NullString{Valid: false}
-		return
+		return sql.NullString{Valid: false}
 	}
 
-	r.Tracklist = sql.NullString{
+	return sql.NullString{
 		String: string(data),
 		Valid:  true,
 	}
@@ -209,10 +259,71 @@ func (r *Record) GetCondition() string {
 	return "No especificada"
 }
 
+// GetRating retorna la calificación formateada como estrellas o "Sin calificar"
+func (r *Record) GetRating() string {
+	if r.Rating.Valid {
+		return FormatStars(r.Rating.Float64)
+	}
+	return "Sin calificar"
+}
+
 // GetArtworkURL retorna la URL del artwork o una imagen por defecto
 func (r *Record) GetArtworkURL() string {
 	if r.ArteURL.Valid && r.ArteURL.String != "" {
 		return r.ArteURL.String
 	}
-	return "/static/images/default-vinyl.jpg"
+	return "/static/images/default-vinyl.svg"
+}
+
+// GetArtworkThumbnailURL retorna la URL de la miniatura del artwork en el
+// tamaño indicado; el arte externo y la imagen por defecto no tienen
+// miniaturas y se retornan tal cual
+func (r *Record) GetArtworkThumbnailURL(size artwork.Size) string {
+	return artwork.ThumbnailURL(r.GetArtworkURL(), size)
+}
+
+// Colores que se usan cuando el record no tiene paleta: el fondo oscuro y
+// el rojo de la interfaz
+const (
+	defaultDominantColor = "#1f2937"
+	defaultAccentColor   = "#e63946"
+)
+
+// SetPalette guarda los colores extraídos del arte; una paleta vacía los borra
+func (r *Record) SetPalette(palette artwork.Palette) {
+	r.ColorDominante = sql.NullString{String: palette.Dominant, Valid: artwork.IsColor(palette.Dominant)}
+	r.ColorAcento = sql.NullString{String: palette.Accent, Valid: artwork.IsColor(palette.Accent)}
+}
+
+// HasPalette indica si el record tiene los colores de su arte calculados
+func (r *Record) HasPalette() bool {
+	return r.ColorDominante.Valid && r.ColorAcento.Valid
+}
+
+// GetDominantColor retorna el color dominante del arte o un gris oscuro
+func (r *Record) GetDominantColor() string {
+	if r.ColorDominante.Valid && artwork.IsColor(r.ColorDominante.String) {
+		return r.ColorDominante.String
+	}
+	return defaultDominantColor
+}
+
+// GetAccentColor retorna el color de acento del arte o el rojo de la interfaz
+func (r *Record) GetAccentColor() string {
+	if r.ColorAcento.Valid && artwork.IsColor(r.ColorAcento.String) {
+		return r.ColorAcento.String
+	}
+	return defaultAccentColor
+}
+
+// GetArtworkSrcset retorna el srcset con las miniaturas del artwork, o
+// vacío si no las tiene
+func (r *Record) GetArtworkSrcset() string {
+	return artwork.Srcset(r.GetArtworkURL())
+}
+
+// GetArtworkSrcsetWebP retorna el srcset con las miniaturas WebP del
+// artwork, o vacío si no las tiene
+func (r *Record) GetArtworkSrcsetWebP() string {
+	return artwork.SrcsetWebP(r.GetArtworkURL())
 }
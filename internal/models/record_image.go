@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/vinilo/internal/artwork"
+)
+
+// Tipos de imagen de la galería de un record
+const (
+	ImageFront  = "front"
+	ImageBack   = "back"
+	ImageLabelA = "label_a"
+	ImageLabelB = "label_b"
+	ImageInsert = "insert"
+	ImageRunout = "runout"
+)
+
+// ImageTypes lista los tipos de imagen válidos, en el orden en que se
+// ofrecen al subir una imagen
+var ImageTypes = []string{
+	ImageFront,
+	ImageBack,
+	ImageLabelA,
+	ImageLabelB,
+	ImageInsert,
+	ImageRunout,
+}
+
+// RecordImage es una imagen de la galería de un record
+type RecordImage struct {
+	ID        string         `json:"id" db:"id"`
+	RecordID  string         `json:"record_id" db:"record_id"`
+	URL       string         `json:"url" db:"url"`
+	Tipo      string         `json:"tipo" db:"tipo"`
+	Leyenda   sql.NullString `json:"leyenda" db:"leyenda"`
+	Posicion  int            `json:"posicion" db:"posicion"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// NewRecordImage crea una imagen de galería validando su tipo
+func NewRecordImage(recordID, url, tipo string) (*RecordImage, error) {
+	if !IsValidImageType(tipo) {
+		return nil, fmt.Errorf("tipo de imagen inválido: %s", tipo)
+	}
+
+	return &RecordImage{
+		ID:        uuid.New().String(),
+		RecordID:  recordID,
+		URL:       url,
+		Tipo:      tipo,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// IsValidImageType indica si el tipo de imagen es válido
+func IsValidImageType(tipo string) bool {
+	for _, t := range ImageTypes {
+		if t == tipo {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageTypeLabel retorna el nombre legible de un tipo de imagen
+func ImageTypeLabel(tipo string) string {
+	switch tipo {
+	case ImageFront:
+		return "Portada"
+	case ImageBack:
+		return "Contraportada"
+	case ImageLabelA:
+		return "Etiqueta lado A"
+	case ImageLabelB:
+		return "Etiqueta lado B"
+	case ImageInsert:
+		return "Inserto"
+	case ImageRunout:
+		return "Runout"
+	}
+	return tipo
+}
+
+// GetTypeLabel retorna el nombre legible del tipo de la imagen
+func (i *RecordImage) GetTypeLabel() string {
+	return ImageTypeLabel(i.Tipo)
+}
+
+// GetCaption retorna la leyenda de la imagen, o el nombre de su tipo si
+// no tiene
+func (i *RecordImage) GetCaption() string {
+	if i.Leyenda.Valid && i.Leyenda.String != "" {
+		return i.Leyenda.String
+	}
+	return i.GetTypeLabel()
+}
+
+// GetThumbnailURL retorna la URL de la miniatura de la imagen en el tamaño indicado
+func (i *RecordImage) GetThumbnailURL(size artwork.Size) string {
+	return artwork.ThumbnailURL(i.URL, size)
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SmartCrate representa un crate inteligente: una selección de records
+// definida por un filtro guardado que se evalúa en cada consulta
+type SmartCrate struct {
+	ID          string         `json:"id" db:"id"`
+	Nombre      string         `json:"nombre" db:"nombre"`
+	Descripcion sql.NullString `json:"descripcion" db:"descripcion"`
+	Filtro      string         `json:"filtro" db:"filtro"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+
+	// RecordCount se calcula al mostrar el crate
+	RecordCount int `json:"record_count,omitempty" db:"-"`
+}
+
+// NewSmartCrate crea un nuevo crate inteligente con ID generado
+func NewSmartCrate() *SmartCrate {
+	return &SmartCrate{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// RecordFilter retorna el filtro de records equivalente al crate
+func (c *SmartCrate) RecordFilter() (RecordFilter, error) {
+	conditions, err := ParseFilterExpression(c.Filtro)
+	if err != nil {
+		return RecordFilter{}, err
+	}
+	return RecordFilter{Conditions: conditions, Sort: SortArtist}, nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag representa una etiqueta libre asignable a records ("verano",
+// "limpiar", "firmado")
+type Tag struct {
+	ID        string    `json:"id" db:"id"`
+	Nombre    string    `json:"nombre" db:"nombre"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+
+	// RecordCount se calcula al listar tags
+	RecordCount int `json:"record_count,omitempty" db:"record_count"`
+}
+
+// NewTag crea un nuevo tag con ID generado y nombre normalizado
+func NewTag(nombre string) *Tag {
+	return &Tag{
+		ID:        uuid.New().String(),
+		Nombre:    NormalizeTag(nombre),
+		CreatedAt: time.Now(),
+	}
+}
+
+// NormalizeTag normaliza el nombre de un tag: minúsculas y sin espacios extra
+func NormalizeTag(nombre string) string {
+	return strings.ToLower(strings.Join(strings.Fields(nombre), " "))
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Prioridades de una entrada de la wantlist
+const (
+	WantPriorityHigh   = 1
+	WantPriorityMedium = 2
+	WantPriorityLow    = 3
+)
+
+// WantlistItem representa un disco que se está buscando. Comparte los
+// metadatos de lanzamiento de Record para poder convertirse en uno.
+type WantlistItem struct {
+	ID                string          `json:"id" db:"id"`
+	Titulo            string          `json:"titulo" db:"titulo"`
+	Artista           string          `json:"artista" db:"artista"`
+	Sello             sql.NullString  `json:"sello" db:"sello"`
+	CatalogNumber     sql.NullString  `json:"catalog_number" db:"catalog_number"`
+	Anio              sql.NullInt32   `json:"anio" db:"anio"`
+	Formato           sql.NullString  `json:"formato" db:"formato"`
+	Generos           sql.NullString  `json:"generos" db:"generos"`
+	Estilos           sql.NullString  `json:"estilos" db:"estilos"`
+	Pais              sql.NullString  `json:"pais" db:"pais"`
+	ArteURL           sql.NullString  `json:"arte_url" db:"arte_url"`
+	Notas             sql.NullString  `json:"notas" db:"notas"`
+	PrecioMaximo      sql.NullFloat64 `json:"precio_maximo" db:"precio_maximo"`
+	PrensajePreferido sql.NullString  `json:"prensaje_preferido" db:"prensaje_preferido"`
+	Prioridad         int             `json:"prioridad" db:"prioridad"`
+	CreatedAt         time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// NewWantlistItem crea una nueva entrada de wantlist con ID generado
+func NewWantlistItem() *WantlistItem {
+	return &WantlistItem{
+		ID:        uuid.New().String(),
+		Prioridad: WantPriorityMedium,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// GetGenerosAsSlice convierte el string JSON de géneros a slice
+func (w *WantlistItem) GetGenerosAsSlice() []string {
+	if !w.Generos.Valid {
+		return []string{}
+	}
+
+	var generos []string
+	if err := json.Unmarshal([]byte(w.Generos.String), &generos); err != nil {
+		return []string{}
+	}
+	return generos
+}
+
+// SetGeneros convierte el slice de géneros a JSON string
+func (w *WantlistItem) SetGeneros(generos []string) {
+	w.Generos = toJSONString(generos)
+}
+
+// SetEstilos convierte el slice de estilos a JSON string
+func (w *WantlistItem) SetEstilos(estilos []string) {
+	w.Estilos = toJSONString(estilos)
+}
+
+// GetPriorityLabel retorna el nombre legible de la prioridad
+func (w *WantlistItem) GetPriorityLabel() string {
+	switch w.Prioridad {
+	case WantPriorityHigh:
+		return "Alta"
+	case WantPriorityLow:
+		return "Baja"
+	}
+	return "Media"
+}
+
+// GetMaxPrice retorna el precio máximo formateado o "Sin límite"
+func (w *WantlistItem) GetMaxPrice() string {
+	if w.PrecioMaximo.Valid {
+		return fmt.Sprintf("$%.2f", w.PrecioMaximo.Float64)
+	}
+	return "Sin límite"
+}
+
+// ToRecord crea un record de la colección con los metadatos de la entrada
+func (w *WantlistItem) ToRecord() *Record {
+	record := NewRecord()
+	record.Titulo = w.Titulo
+	record.Artista = w.Artista
+	record.Sello = w.Sello
+	record.CatalogNumber = w.CatalogNumber
+	record.Anio = w.Anio
+	record.Formato = w.Formato
+	record.Generos = w.Generos
+	record.Estilos = w.Estilos
+	record.Pais = w.Pais
+	record.ArteURL = w.ArteURL
+	record.Notas = w.Notas
+	return record
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// ArtworkMirrorRepository maneja las operaciones de base de datos para las
+// copias locales del arte enlazado desde sitios externos
+type ArtworkMirrorRepository struct {
+	db *database.DB
+}
+
+// NewArtworkMirrorRepository crea un nuevo repositorio de copias de arte
+func NewArtworkMirrorRepository(db *database.DB) *ArtworkMirrorRepository {
+	return &ArtworkMirrorRepository{db: db}
+}
+
+// artworkMirrorColumns lista las columnas de artwork_mirrors en el orden que espera scanArtworkMirror
+const artworkMirrorColumns = `record_id, url_original, estado, error, intentado_at`
+
+// artworkMirrorActor es el actor con el que la copia de arte registra en el
+// historial el cambio de arte_url a la copia local
+const artworkMirrorActor = "copia de arte"
+
+// pendingArtworkCondition filtra los records que enlazan su arte desde un
+// sitio externo y cuya URL actual todavía no se intentó copiar
+const pendingArtworkCondition = activeRecord + ` AND (arte_url LIKE 'http://%' OR arte_url LIKE 'https://%')
+	AND NOT EXISTS (
+		SELECT 1 FROM artwork_mirrors m
+		WHERE m.record_id = records.id AND m.url_original = records.arte_url
+	)`
+
+// scanArtworkMirror lee una fila de artwork_mirrors en un modelo
+func scanArtworkMirror(s rowScanner) (*models.ArtworkMirror, error) {
+	var mirror models.ArtworkMirror
+	err := s.Scan(
+		&mirror.RecordID,
+		&mirror.URLOriginal,
+		&mirror.Estado,
+		&mirror.Error,
+		&mirror.IntentadoAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &mirror, nil
+}
+
+// GetPending obtiene los records que enlazan su arte desde un sitio externo
+// y cuya URL actual todavía no se intentó copiar
+func (r *ArtworkMirrorRepository) GetPending(limit int) ([]*models.Record, error) {
+	query := `
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + pendingArtworkCondition + `
+		ORDER BY created_at ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo arte por copiar: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// CountPending cuenta los records con arte externo que todavía no se intentó copiar
+func (r *ArtworkMirrorRepository) CountPending() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM records WHERE ` + pendingArtworkCondition).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error contando arte por copiar: %w", err)
+	}
+	return count, nil
+}
+
+// CountCopied cuenta los records de la colección cuyo arte se copió desde un
+// sitio externo
+func (r *ArtworkMirrorRepository) CountCopied() (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM artwork_mirrors m
+		JOIN records ON records.id = m.record_id AND `+activeRecord+`
+		WHERE m.estado = ?`,
+		models.MirrorCopied,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error contando arte copiado: %w", err)
+	}
+	return count, nil
+}
+
+// GetBroken obtiene los enlaces rotos que el record todavía usa, es decir,
+// que no se reemplazaron por otra URL o por una imagen subida
+func (r *ArtworkMirrorRepository) GetBroken() ([]*models.ArtworkMirror, error) {
+	query := `
+		SELECT m.record_id, m.url_original, m.estado, m.error, m.intentado_at
+		FROM artwork_mirrors m
+		JOIN records ON records.id = m.record_id AND records.arte_url = m.url_original
+		WHERE m.estado = ? AND ` + activeRecord + `
+		ORDER BY m.intentado_at DESC
+	`
+
+	rows, err := r.db.Query(query, models.MirrorBroken)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo enlaces rotos: %w", err)
+	}
+	defer rows.Close()
+
+	var mirrors []*models.ArtworkMirror
+	for rows.Next() {
+		mirror, err := scanArtworkMirror(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando copia de arte: %w", err)
+		}
+		mirrors = append(mirrors, mirror)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando copias de arte: %w", err)
+	}
+
+	return mirrors, nil
+}
+
+// GetByRecord obtiene la copia del arte de un record, o nil si nunca se intentó
+func (r *ArtworkMirrorRepository) GetByRecord(recordID string) (*models.ArtworkMirror, error) {
+	query := `SELECT ` + artworkMirrorColumns + ` FROM artwork_mirrors WHERE record_id = ?`
+
+	mirror, err := scanArtworkMirror(r.db.QueryRow(query, recordID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo copia de arte: %w", err)
+	}
+	return mirror, nil
+}
+
+// Save guarda el resultado de copiar el arte de un record y, si se copió,
+// reemplaza su arte_url por la URL local y su paleta en la misma transacción
+func (r *ArtworkMirrorRepository) Save(mirror *models.ArtworkMirror, localURL string, palette artwork.Palette) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO artwork_mirrors (`+artworkMirrorColumns+`) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(record_id) DO UPDATE SET
+			url_original = excluded.url_original, estado = excluded.estado,
+			error = excluded.error, intentado_at = excluded.intentado_at
+	`,
+		mirror.RecordID,
+		mirror.URLOriginal,
+		mirror.Estado,
+		mirror.Error,
+		mirror.IntentadoAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("error guardando copia de arte: %w", err)
+	}
+
+	if localURL != "" {
+		before, err := loadRecord(tx, mirror.RecordID)
+		if err != nil {
+			return err
+		}
+
+		// Solo se reemplaza si el record sigue usando la URL copiada
+		result, err := tx.Exec(`
+			UPDATE records SET arte_url = ?, color_dominante = ?, color_acento = ?,
+				updated_at = CURRENT_TIMESTAMP, version = version + 1
+			WHERE id = ? AND arte_url = ?
+		`, localURL, nullColor(palette.Dominant), nullColor(palette.Accent), mirror.RecordID, mirror.URLOriginal)
+		if err != nil {
+			return fmt.Errorf("error actualizando arte del record: %w", err)
+		}
+		if n, err := result.RowsAffected(); err == nil && n > 0 {
+			if err := saveVersion(tx, mirror.RecordID, models.HistoryUpdate, artworkMirrorActor, before); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando transacción: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+const externalArt = "https://img.discogs.test/nevermind.jpg"
+
+// newExternalArtRecord crea un record con arte enlazado desde otro sitio
+func newExternalArtRecord(t *testing.T, records *RecordRepository) *models.Record {
+	t.Helper()
+	record := models.NewRecord()
+	record.Artista = "Nirvana"
+	record.Titulo = "Nevermind"
+	record.ArteURL = sql.NullString{String: externalArt, Valid: true}
+	if err := records.Create(record); err != nil {
+		t.Fatalf("error creando record: %v", err)
+	}
+	return record
+}
+
+func TestArtworkMirrorSaveCopied(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	mirrors := NewArtworkMirrorRepository(db)
+	record := newExternalArtRecord(t, records)
+
+	if n, err := mirrors.CountPending(); err != nil || n != 1 {
+		t.Fatalf("pendientes = %d (%v), se esperaba 1", n, err)
+	}
+
+	mirror := &models.ArtworkMirror{RecordID: record.ID, URLOriginal: externalArt, Estado: models.MirrorCopied, IntentadoAt: time.Now()}
+	palette := artwork.Palette{Dominant: "#112233", Accent: "#ddeeff"}
+	if err := mirrors.Save(mirror, "/media/artwork/abc.jpg", palette); err != nil {
+		t.Fatalf("error guardando copia: %v", err)
+	}
+
+	got := reloadRecord(t, records, record.ID)
+	if got.ArteURL.String != "/media/artwork/abc.jpg" || got.ColorDominante.String != "#112233" || got.Version != record.Version+1 {
+		t.Errorf("record: arte %q, color %q, versión %d", got.ArteURL.String, got.ColorDominante.String, got.Version)
+	}
+	versions, err := NewRecordVersionRepository(db).GetByRecord(record.ID)
+	if err != nil || len(versions) != 2 || versions[0].Actor != artworkMirrorActor {
+		t.Errorf("historial = %d versiones (%v)", len(versions), err)
+	}
+
+	saved, err := mirrors.GetByRecord(record.ID)
+	if err != nil || saved == nil || saved.URLOriginal != externalArt || saved.IsBroken() {
+		t.Errorf("copia = %+v (%v)", saved, err)
+	}
+	if n, err := mirrors.CountPending(); err != nil || n != 0 {
+		t.Errorf("pendientes = %d (%v), se esperaba 0", n, err)
+	}
+	if n, err := mirrors.CountCopied(); err != nil || n != 1 {
+		t.Errorf("copiados = %d (%v), se esperaba 1", n, err)
+	}
+}
+
+func TestArtworkMirrorSaveKeepsChangedURL(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	mirrors := NewArtworkMirrorRepository(db)
+	record := newExternalArtRecord(t, records)
+
+	// Mientras se descargaba, alguien eligió otra portada
+	record.ArteURL = sql.NullString{String: "https://img.discogs.test/otra.jpg", Valid: true}
+	if err := records.Update(record); err != nil {
+		t.Fatalf("error actualizando record: %v", err)
+	}
+	before := len(historyActions(t, db, record.ID))
+
+	mirror := &models.ArtworkMirror{RecordID: record.ID, URLOriginal: externalArt, Estado: models.MirrorCopied, IntentadoAt: time.Now()}
+	if err := mirrors.Save(mirror, "/media/artwork/abc.jpg", artwork.Palette{Dominant: "#112233"}); err != nil {
+		t.Fatalf("error guardando copia: %v", err)
+	}
+
+	got := reloadRecord(t, records, record.ID)
+	if got.ArteURL.String != "https://img.discogs.test/otra.jpg" || got.ColorDominante.Valid || got.Version != record.Version {
+		t.Errorf("se reemplazó el arte nuevo: arte %q, color %q, versión %d", got.ArteURL.String, got.ColorDominante.String, got.Version)
+	}
+	if n := len(historyActions(t, db, record.ID)); n != before {
+		t.Errorf("historial creció de %d a %d versiones", before, n)
+	}
+	// La URL nueva todavía no se intentó copiar
+	if n, err := mirrors.CountPending(); err != nil || n != 1 {
+		t.Errorf("pendientes = %d (%v), se esperaba 1", n, err)
+	}
+}
+
+func TestArtworkMirrorSaveBroken(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	mirrors := NewArtworkMirrorRepository(db)
+	record := newExternalArtRecord(t, records)
+
+	mirror := &models.ArtworkMirror{
+		RecordID: record.ID, URLOriginal: externalArt, Estado: models.MirrorBroken, IntentadoAt: time.Now(),
+		Error: sql.NullString{String: "img.discogs.test respondió HTTP 404", Valid: true},
+	}
+	if err := mirrors.Save(mirror, "", artwork.Palette{}); err != nil {
+		t.Fatalf("error guardando copia: %v", err)
+	}
+
+	if got := reloadRecord(t, records, record.ID); got.ArteURL.String != externalArt || got.Version != record.Version {
+		t.Errorf("el record cambió: arte %q, versión %d", got.ArteURL.String, got.Version)
+	}
+	brokenIDs := func() []string {
+		broken, err := mirrors.GetBroken()
+		if err != nil {
+			t.Fatalf("error obteniendo enlaces rotos: %v", err)
+		}
+		var ids []string
+		for _, m := range broken {
+			ids = append(ids, m.RecordID)
+		}
+		return ids
+	}
+	if ids := brokenIDs(); !slices.Equal(ids, []string{record.ID}) {
+		t.Errorf("enlaces rotos = %v", ids)
+	}
+	if n, err := mirrors.CountPending(); err != nil || n != 0 {
+		t.Errorf("pendientes = %d (%v), un enlace roto no se vuelve a intentar", n, err)
+	}
+
+	// Los records en la papelera no se reportan
+	if err := records.Delete(record.ID); err != nil {
+		t.Fatalf("error enviando a la papelera: %v", err)
+	}
+	if ids := brokenIDs(); len(ids) != 0 {
+		t.Errorf("enlaces rotos en la papelera = %v", ids)
+	}
+}
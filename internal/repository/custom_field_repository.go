@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// CustomFieldRepository maneja las operaciones de base de datos para campos personalizados
+type CustomFieldRepository struct {
+	db    *database.DB
+	actor string
+}
+
+// NewCustomFieldRepository crea un nuevo repositorio de campos personalizados
+func NewCustomFieldRepository(db *database.DB) *CustomFieldRepository {
+	return &CustomFieldRepository{db: db}
+}
+
+// WithActor retorna una copia del repositorio que registra en el historial
+// de los records los cambios a sus valores a nombre del actor indicado
+func (r *CustomFieldRepository) WithActor(actor string) *CustomFieldRepository {
+	return &CustomFieldRepository{db: r.db, actor: actor}
+}
+
+// customFieldColumns lista las columnas de custom_fields en el orden que espera scanCustomField
+const customFieldColumns = `id, clave, nombre, tipo, opciones, posicion, created_at`
+
+// scanCustomField lee una fila de custom_fields en un modelo
+func scanCustomField(s rowScanner) (*models.CustomField, error) {
+	var field models.CustomField
+	err := s.Scan(
+		&field.ID,
+		&field.Clave,
+		&field.Nombre,
+		&field.Tipo,
+		&field.Opciones,
+		&field.Posicion,
+		&field.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+// Create crea un nuevo campo personalizado al final de la lista
+func (r *CustomFieldRepository) Create(field *models.CustomField) error {
+	query := `
+		INSERT INTO custom_fields (` + customFieldColumns + `)
+		VALUES (?, ?, ?, ?, ?, (SELECT COALESCE(MAX(posicion), 0) + 1 FROM custom_fields), ?)
+	`
+
+	_, err := r.db.Exec(query,
+		field.ID,
+		field.Clave,
+		field.Nombre,
+		field.Tipo,
+		field.Opciones,
+		field.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error creando campo personalizado: %w", err)
+	}
+
+	log.Printf("✅ Campo personalizado creado: %s (%s)", field.Nombre, field.Tipo)
+	return nil
+}
+
+// GetByID obtiene un campo personalizado por su ID
+func (r *CustomFieldRepository) GetByID(id string) (*models.CustomField, error) {
+	query := `SELECT ` + customFieldColumns + ` FROM custom_fields WHERE id = ?`
+
+	field, err := scanCustomField(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("campo personalizado no encontrado: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo campo personalizado: %w", err)
+	}
+
+	return field, nil
+}
+
+// GetAll obtiene todos los campos personalizados en el orden en que se muestran
+func (r *CustomFieldRepository) GetAll() ([]*models.CustomField, error) {
+	rows, err := r.db.Query(`SELECT ` + customFieldColumns + ` FROM custom_fields ORDER BY posicion ASC, nombre ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo campos personalizados: %w", err)
+	}
+	defer rows.Close()
+
+	var fields []*models.CustomField
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando campo personalizado: %w", err)
+		}
+		fields = append(fields, field)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo campos personalizados: %w", err)
+	}
+
+	return fields, nil
+}
+
+// Delete elimina un campo personalizado y quita su valor de todos los
+// records, dejando el cambio en el historial de cada uno
+func (r *CustomFieldRepository) Delete(id string) error {
+	field, err := r.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	path := "$." + field.Clave
+	records, err := loadRecordsWhere(tx, `json_valid(campos) AND json_type(campos, ?) IS NOT NULL`, path)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE records SET campos = NULLIF(json_remove(campos, ?), '{}'), version = version + 1
+		WHERE json_valid(campos) AND json_type(campos, ?) IS NOT NULL
+	`, path, path); err != nil {
+		return fmt.Errorf("error quitando valores del campo: %w", err)
+	}
+	if err := saveVersions(tx, models.HistoryUpdate, r.actor, records); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM custom_fields WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("error eliminando campo personalizado: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando eliminación: %w", err)
+	}
+
+	log.Printf("✅ Campo personalizado eliminado: %s", field.Clave)
+	return nil
+}
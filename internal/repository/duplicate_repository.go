@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// DuplicateRepository maneja las operaciones de base de datos para buscar
+// y fusionar records duplicados
+type DuplicateRepository struct {
+	db *database.DB
+}
+
+// NewDuplicateRepository crea un nuevo repositorio de duplicados
+func NewDuplicateRepository(db *database.DB) *DuplicateRepository {
+	return &DuplicateRepository{db: db}
+}
+
+// GetCandidates obtiene los records de la colección con sus identificadores
+func (r *DuplicateRepository) GetCandidates() ([]models.DuplicateCandidate, error) {
+	rows, err := r.db.Query(`SELECT ` + recordColumns + ` FROM records WHERE ` + activeRecord + ` ORDER BY created_at ASC, id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records: %w", err)
+	}
+	records, err := scanRecords(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(`SELECT ` + identifierColumns + ` FROM identifiers`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo identificadores: %w", err)
+	}
+	defer rows.Close()
+
+	identifiers := map[string][]*models.Identifier{}
+	for rows.Next() {
+		identifier, err := scanIdentifier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando identificador: %w", err)
+		}
+		identifiers[identifier.RecordID] = append(identifiers[identifier.RecordID], identifier)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo identificadores: %w", err)
+	}
+
+	candidates := make([]models.DuplicateCandidate, 0, len(records))
+	for _, record := range records {
+		candidates = append(candidates, models.DuplicateCandidate{
+			Record:      record,
+			Identifiers: identifiers[record.ID],
+		})
+	}
+	return candidates, nil
+}
+
+// GetDismissed obtiene los pares marcados como distintos, indexados por
+// models.DuplicatePairKey
+func (r *DuplicateRepository) GetDismissed() (map[[2]string]bool, error) {
+	rows, err := r.db.Query(`SELECT record_a, record_b FROM duplicate_dismissals`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo pares descartados: %w", err)
+	}
+	defer rows.Close()
+
+	dismissed := map[[2]string]bool{}
+	for rows.Next() {
+		var a, b string
+		if err := rows.Scan(&a, &b); err != nil {
+			return nil, fmt.Errorf("error escaneando par descartado: %w", err)
+		}
+		dismissed[models.DuplicatePairKey(a, b)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo pares descartados: %w", err)
+	}
+	return dismissed, nil
+}
+
+// Dismiss marca dos records como distintos para no volver a proponerlos
+func (r *DuplicateRepository) Dismiss(a, b string) error {
+	key := models.DuplicatePairKey(a, b)
+	_, err := r.db.Exec(
+		`INSERT OR IGNORE INTO duplicate_dismissals (record_a, record_b) VALUES (?, ?)`,
+		key[0], key[1],
+	)
+	if err != nil {
+		return fmt.Errorf("error descartando par: %w", err)
+	}
+
+	log.Printf("✅ Par descartado como duplicado: %s / %s", key[0], key[1])
+	return nil
+}
+
+// CountRelated cuenta las escuchas, préstamos, imágenes, identificadores,
+// tags y playlists de un record
+func (r *DuplicateRepository) CountRelated(recordID string) (*models.DuplicateRelated, error) {
+	var related models.DuplicateRelated
+	err := r.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM plays WHERE record_id = ?),
+			(SELECT COUNT(*) FROM loans WHERE record_id = ?),
+			(SELECT COUNT(*) FROM record_images WHERE record_id = ?),
+			(SELECT COUNT(*) FROM identifiers WHERE record_id = ?),
+			(SELECT COUNT(*) FROM record_tags WHERE record_id = ?),
+			(SELECT COUNT(DISTINCT playlist_id) FROM playlist_items WHERE record_id = ?)
+	`, recordID, recordID, recordID, recordID, recordID, recordID).Scan(
+		&related.Plays,
+		&related.Loans,
+		&related.Images,
+		&related.Identifiers,
+		&related.Tags,
+		&related.Playlists,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error contando datos del record: %w", err)
+	}
+	return &related, nil
+}
+
+// Merge guarda keep con los campos ya combinados y le traspasa todo lo que
+// cuelga de discard (escuchas, préstamos, imágenes, identificadores, tags,
+// playlists y el estado de sincronización) antes de enviarlo a la papelera,
+// en una sola transacción. La fusión queda en el historial de ambos a nombre
+// de actor; el de discard conserva sus versiones y termina apuntando a keep.
+// Si keep cambió desde keep.Version o discard desde discardVersion, no se
+// fusiona nada y se retorna ErrConflict.
+func (r *DuplicateRepository) Merge(keep *models.Record, discardID string, discardVersion int, actor string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := loadRecord(tx, keep.ID)
+	if err != nil {
+		return err
+	}
+	discard, err := loadRecord(tx, discardID)
+	if err != nil {
+		return err
+	}
+	if discard.Version != discardVersion {
+		return ErrConflict
+	}
+	if err := updateRecord(tx, keep); err != nil {
+		return err
+	}
+
+	// Las imágenes de discard van al final de la galería de keep
+	var offset int
+	err = tx.QueryRow(
+		`SELECT COALESCE(MAX(posicion), 0) FROM record_images WHERE record_id = ?`, keep.ID,
+	).Scan(&offset)
+	if err != nil {
+		return fmt.Errorf("error obteniendo galería: %w", err)
+	}
+
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE plays SET record_id = ? WHERE record_id = ?`, []any{keep.ID, discardID}},
+		{`UPDATE loans SET record_id = ? WHERE record_id = ?`, []any{keep.ID, discardID}},
+		{`UPDATE playlist_items SET record_id = ? WHERE record_id = ?`, []any{keep.ID, discardID}},
+		{`UPDATE record_images SET record_id = ?, posicion = posicion + ? WHERE record_id = ?`, []any{keep.ID, offset, discardID}},
+		// Los identificadores y tags que keep ya tiene no se duplican
+		{`UPDATE identifiers SET record_id = ?
+			WHERE record_id = ? AND NOT EXISTS (
+				SELECT 1 FROM identifiers k
+				WHERE k.record_id = ? AND k.tipo = identifiers.tipo AND k.valor = identifiers.valor
+			)`, []any{keep.ID, discardID, keep.ID}},
+		{`DELETE FROM identifiers WHERE record_id = ?`, []any{discardID}},
+		{`INSERT OR IGNORE INTO record_tags (record_id, tag_id, created_at)
+			SELECT ?, tag_id, created_at FROM record_tags WHERE record_id = ?`, []any{keep.ID, discardID}},
+		{`DELETE FROM record_tags WHERE record_id = ?`, []any{discardID}},
+		// El estado de sincronización y de copia del arte de keep tiene
+		// prioridad; los cambios propuestos para discard ya no aplican
+		{`UPDATE OR IGNORE metadata_syncs SET record_id = ? WHERE record_id = ?`, []any{keep.ID, discardID}},
+		{`DELETE FROM metadata_syncs WHERE record_id = ?`, []any{discardID}},
+		{`DELETE FROM metadata_changes WHERE record_id = ?`, []any{discardID}},
+		{`UPDATE OR IGNORE artwork_mirrors SET record_id = ? WHERE record_id = ?`, []any{keep.ID, discardID}},
+		{`DELETE FROM artwork_mirrors WHERE record_id = ?`, []any{discardID}},
+		{`DELETE FROM duplicate_dismissals WHERE record_a = ? OR record_b = ?`, []any{discardID, discardID}},
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			return fmt.Errorf("error traspasando datos del duplicado: %w", err)
+		}
+	}
+
+	// discard queda en la papelera, desde donde se puede restaurar hasta
+	// que se purgue
+	result, err := tx.Exec(`
+		UPDATE records SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ? AND `+activeRecord, discardID)
+	if err != nil {
+		return fmt.Errorf("error enviando duplicado a la papelera: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("record no encontrado: %s", discardID)
+	}
+
+	if err := saveVersion(tx, keep.ID, models.HistoryMerge, actor, before); err != nil {
+		return err
+	}
+	mergedInto := models.FieldChange{Campo: models.HistoryMergedInto, Despues: keep.ID}
+	if err := saveVersion(tx, discardID, models.HistoryMerge, actor, discard, mergedInto); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando fusión: %w", err)
+	}
+
+	log.Printf("✅ Record %s fusionado en %s - %s", discardID, keep.Artista, keep.Titulo)
+	return nil
+}
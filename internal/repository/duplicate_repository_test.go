@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// mergeFixture son dos records duplicados con identificadores, tags,
+// imágenes, escuchas y sincronizaciones, algunos compartidos
+type mergeFixture struct {
+	db         *database.DB
+	records    *RecordRepository
+	keep       *models.Record
+	discard    *models.Record
+	keepImages []string
+	discImages []string
+}
+
+func newMergeFixture(t *testing.T) *mergeFixture {
+	t.Helper()
+	db := newTestDB(t)
+	f := &mergeFixture{db: db, records: NewRecordRepository(db)}
+	f.keep = newTestRecord(t, f.records, "Nirvana", "Nevermind")
+	f.discard = newTestRecord(t, f.records, "Nirvana", "Nevermind (Remastered)")
+
+	identifiers := NewIdentifierRepository(db)
+	for _, item := range []struct{ recordID, tipo, valor string }{
+		{f.keep.ID, models.IdentifierBarcode, "720642442517"},
+		{f.discard.ID, models.IdentifierBarcode, "720642442517"},
+		{f.discard.ID, models.IdentifierDiscogs, "367113"},
+	} {
+		identifier, err := models.NewIdentifier(item.recordID, item.tipo, item.valor)
+		if err != nil {
+			t.Fatalf("identificador inválido: %v", err)
+		}
+		if err := identifiers.Create(identifier); err != nil {
+			t.Fatalf("error creando identificador: %v", err)
+		}
+	}
+
+	tags := NewTagRepository(db)
+	if err := tags.AddToRecord(f.keep.ID, []string{"grunge"}); err != nil {
+		t.Fatalf("error agregando tags: %v", err)
+	}
+	if err := tags.AddToRecord(f.discard.ID, []string{"grunge", "90s"}); err != nil {
+		t.Fatalf("error agregando tags: %v", err)
+	}
+
+	images := NewRecordImageRepository(db)
+	for _, target := range []struct {
+		recordID string
+		ids      *[]string
+	}{{f.keep.ID, &f.keepImages}, {f.keep.ID, &f.keepImages}, {f.discard.ID, &f.discImages}, {f.discard.ID, &f.discImages}} {
+		image, err := models.NewRecordImage(target.recordID, "/media/"+target.recordID+".jpg", models.ImageFront)
+		if err != nil {
+			t.Fatalf("imagen inválida: %v", err)
+		}
+		if err := images.Create(image); err != nil {
+			t.Fatalf("error creando imagen: %v", err)
+		}
+		*target.ids = append(*target.ids, image.ID)
+	}
+
+	play := models.NewPlay()
+	play.RecordID = f.discard.ID
+	if err := NewPlayRepository(db).Create(play); err != nil {
+		t.Fatalf("error registrando escucha: %v", err)
+	}
+
+	syncs := NewMetadataSyncRepository(db)
+	for _, sync := range []*models.MetadataSync{
+		{RecordID: f.keep.ID, Provider: "discogs", ExternalID: "1", SyncedAt: time.Now()},
+		{RecordID: f.discard.ID, Provider: "musicbrainz", ExternalID: "2", SyncedAt: time.Now()},
+	} {
+		change := models.NewMetadataChange(sync.RecordID, sync.Provider, "sello", "", "DGC")
+		if err := syncs.Save(sync, []*models.MetadataChange{change}); err != nil {
+			t.Fatalf("error guardando sincronización: %v", err)
+		}
+	}
+
+	// Las escrituras anteriores pueden haber subido la versión
+	f.keep = reloadRecord(t, f.records, f.keep.ID)
+	f.discard = reloadRecord(t, f.records, f.discard.ID)
+	return f
+}
+
+// count cuenta las filas de una tabla que pertenecen a un record
+func (f *mergeFixture) count(t *testing.T, table, recordID string) int {
+	t.Helper()
+	var n int
+	if err := f.db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE record_id = ?`, recordID).Scan(&n); err != nil {
+		t.Fatalf("error contando %s: %v", table, err)
+	}
+	return n
+}
+
+func TestMerge(t *testing.T) {
+	f := newMergeFixture(t)
+
+	keep := reloadRecord(t, f.records, f.keep.ID)
+	if err := models.MergeRecords(keep, f.discard, []string{"titulo"}); err != nil {
+		t.Fatalf("error combinando: %v", err)
+	}
+	if err := NewDuplicateRepository(f.db).Merge(keep, f.discard.ID, f.discard.Version, "ana"); err != nil {
+		t.Fatalf("error fusionando: %v", err)
+	}
+
+	got := reloadRecord(t, f.records, f.keep.ID)
+	if got.Titulo != "Nevermind (Remastered)" || got.Version != f.keep.Version+1 {
+		t.Errorf("record conservado: título %q, versión %d", got.Titulo, got.Version)
+	}
+
+	// Identificadores y tags que keep ya tenía no se duplican
+	identifiers, err := NewIdentifierRepository(f.db).GetByRecord(f.keep.ID)
+	if err != nil || len(identifiers) != 2 {
+		t.Errorf("identificadores = %d (%v), se esperaban el código de barras y el release de Discogs", len(identifiers), err)
+	}
+	tags, err := NewTagRepository(f.db).GetByRecord(f.keep.ID)
+	if err != nil || len(tags) != 2 {
+		t.Errorf("tags = %d (%v), se esperaban 2", len(tags), err)
+	}
+
+	// Las imágenes del descartado van al final de la galería
+	images, err := NewRecordImageRepository(f.db).GetByRecord(f.keep.ID)
+	if err != nil || len(images) != 4 {
+		t.Fatalf("galería = %d imágenes (%v), se esperaban 4", len(images), err)
+	}
+	wantOrder := append(append([]string{}, f.keepImages...), f.discImages...)
+	for i, image := range images {
+		if image.ID != wantOrder[i] || image.Posicion != i+1 {
+			t.Errorf("imagen %d = %s en posición %d, se esperaba %s en %d", i, image.ID, image.Posicion, wantOrder[i], i+1)
+		}
+	}
+
+	if n := f.count(t, "plays", f.keep.ID); n != 1 {
+		t.Errorf("escuchas del conservado = %d, se esperaba 1", n)
+	}
+
+	// La sincronización de keep tiene prioridad y la del descartado se elimina
+	sync, err := NewMetadataSyncRepository(f.db).GetSync(f.keep.ID)
+	if err != nil || sync == nil || sync.Provider != "discogs" {
+		t.Errorf("sincronización del conservado = %+v (%v)", sync, err)
+	}
+	for _, table := range []string{"metadata_syncs", "metadata_changes", "identifiers", "record_tags", "record_images", "plays"} {
+		if n := f.count(t, table, f.discard.ID); n != 0 {
+			t.Errorf("%s del descartado = %d, se esperaba 0", table, n)
+		}
+	}
+
+	// El descartado queda en la papelera con una versión que apunta a keep
+	discarded := reloadRecord(t, f.records, f.discard.ID)
+	if !discarded.DeletedAt.Valid {
+		t.Error("el descartado no quedó en la papelera")
+	}
+	versions, err := NewRecordVersionRepository(f.db).GetByRecord(f.discard.ID)
+	if err != nil || len(versions) == 0 {
+		t.Fatalf("historial del descartado: %v", err)
+	}
+	last := versions[0]
+	if last.Accion != models.HistoryMerge || last.Actor != "ana" {
+		t.Errorf("última versión del descartado = %s por %s", last.Accion, last.Actor)
+	}
+	var mergedInto string
+	for _, change := range last.GetChanges() {
+		if change.Campo == models.HistoryMergedInto {
+			mergedInto = change.Despues
+		}
+	}
+	if mergedInto != f.keep.ID {
+		t.Errorf("fusionado en = %q, se esperaba %q", mergedInto, f.keep.ID)
+	}
+	if actions := historyActions(t, f.db, f.keep.ID); actions[len(actions)-1] != models.HistoryMerge {
+		t.Errorf("historial del conservado = %v", actions)
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, f *mergeFixture) // edición de otra persona antes de fusionar
+	}{
+		{name: "conservado desactualizado", change: func(t *testing.T, f *mergeFixture) {
+			rate(t, f.records, f.keep)
+		}},
+		{name: "descartado desactualizado", change: func(t *testing.T, f *mergeFixture) {
+			rate(t, f.records, f.discard)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newMergeFixture(t)
+			keep := reloadRecord(t, f.records, f.keep.ID)
+			if err := models.MergeRecords(keep, f.discard, []string{"titulo"}); err != nil {
+				t.Fatalf("error combinando: %v", err)
+			}
+			tt.change(t, f)
+			keepHistory := len(historyActions(t, f.db, f.keep.ID))
+			discardHistory := len(historyActions(t, f.db, f.discard.ID))
+
+			err := NewDuplicateRepository(f.db).Merge(keep, f.discard.ID, f.discard.Version, "ana")
+			if !errors.Is(err, ErrConflict) {
+				t.Fatalf("error = %v, se esperaba ErrConflict", err)
+			}
+
+			if got := reloadRecord(t, f.records, f.keep.ID); got.Titulo != "Nevermind" {
+				t.Errorf("el conservado cambió: %q", got.Titulo)
+			}
+			if got := reloadRecord(t, f.records, f.discard.ID); got.DeletedAt.Valid {
+				t.Error("el descartado quedó en la papelera")
+			}
+			for table, want := range map[string]int{"identifiers": 2, "record_tags": 2, "record_images": 2, "plays": 1, "metadata_syncs": 1} {
+				if n := f.count(t, table, f.discard.ID); n != want {
+					t.Errorf("%s del descartado = %d, se esperaban %d", table, n, want)
+				}
+			}
+			if n := f.count(t, "record_images", f.keep.ID); n != 2 {
+				t.Errorf("galería del conservado = %d, se esperaban 2", n)
+			}
+			if n := len(historyActions(t, f.db, f.keep.ID)); n != keepHistory {
+				t.Errorf("historial del conservado creció a %d versiones", n)
+			}
+			if n := len(historyActions(t, f.db, f.discard.ID)); n != discardHistory {
+				t.Errorf("historial del descartado creció a %d versiones", n)
+			}
+		})
+	}
+}
+
+// rate califica un record en su versión actual, como lo haría otra persona
+func rate(t *testing.T, records *RecordRepository, record *models.Record) {
+	t.Helper()
+	err := records.UpdateRating(record.ID, record.Version, sql.NullFloat64{Float64: 3, Valid: true}, sql.NullString{}, record.Tracklist)
+	if err != nil {
+		t.Fatalf("error calificando: %v", err)
+	}
+}
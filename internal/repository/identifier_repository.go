@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// IdentifierRepository maneja las operaciones de base de datos para identificadores
+type IdentifierRepository struct {
+	db *database.DB
+}
+
+// NewIdentifierRepository crea un nuevo repositorio de identificadores
+func NewIdentifierRepository(db *database.DB) *IdentifierRepository {
+	return &IdentifierRepository{db: db}
+}
+
+// identifierColumns lista las columnas de identifiers en el orden que espera scanIdentifier
+const identifierColumns = `id, record_id, tipo, valor, descripcion, created_at`
+
+// scanIdentifier lee una fila de identifiers en un modelo
+func scanIdentifier(s rowScanner) (*models.Identifier, error) {
+	var identifier models.Identifier
+	err := s.Scan(
+		&identifier.ID,
+		&identifier.RecordID,
+		&identifier.Tipo,
+		&identifier.Valor,
+		&identifier.Descripcion,
+		&identifier.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &identifier, nil
+}
+
+// insertIdentifier inserta un identificador usando la conexión o transacción indicada
+func insertIdentifier(ex execer, identifier *models.Identifier) error {
+	query := `INSERT INTO identifiers (` + identifierColumns + `) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := ex.Exec(query,
+		identifier.ID,
+		identifier.RecordID,
+		identifier.Tipo,
+		identifier.Valor,
+		identifier.Descripcion,
+		identifier.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error creando identificador: %w", err)
+	}
+
+	return nil
+}
+
+// Create agrega un identificador a un record
+func (r *IdentifierRepository) Create(identifier *models.Identifier) error {
+	if err := insertIdentifier(r.db, identifier); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Identificador agregado a %s: %s %s", identifier.RecordID, identifier.Tipo, identifier.Valor)
+	return nil
+}
+
+// GetByRecord obtiene los identificadores de un record agrupados por tipo
+func (r *IdentifierRepository) GetByRecord(recordID string) ([]*models.Identifier, error) {
+	query := `
+		SELECT ` + identifierColumns + ` FROM identifiers
+		WHERE record_id = ?
+		ORDER BY tipo ASC, created_at ASC
+	`
+
+	rows, err := r.db.Query(query, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo identificadores: %w", err)
+	}
+	defer rows.Close()
+
+	var identifiers []*models.Identifier
+	for rows.Next() {
+		identifier, err := scanIdentifier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando identificador: %w", err)
+		}
+		identifiers = append(identifiers, identifier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo identificadores: %w", err)
+	}
+
+	return identifiers, nil
+}
+
+// FindRecordID busca el record que tiene un identificador, o retorna una
+// cadena vacía si ninguno lo tiene
+func (r *IdentifierRepository) FindRecordID(tipo, valor string) (string, error) {
+	var recordID string
+	err := r.db.QueryRow(
+		`SELECT record_id FROM identifiers
+		JOIN records ON records.id = identifiers.record_id AND `+activeRecord+`
+		WHERE tipo = ? AND valor = ? LIMIT 1`,
+		tipo, valor,
+	).Scan(&recordID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("error buscando identificador: %w", err)
+	}
+	return recordID, nil
+}
+
+// Delete elimina un identificador de un record
+func (r *IdentifierRepository) Delete(recordID, id string) error {
+	result, err := r.db.Exec(`DELETE FROM identifiers WHERE id = ? AND record_id = ?`, id, recordID)
+	if err != nil {
+		return fmt.Errorf("error eliminando identificador: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("identificador no encontrado: %s", id)
+	}
+
+	log.Printf("✅ Identificador eliminado: %s", id)
+	return nil
+}
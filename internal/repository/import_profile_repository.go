@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// ImportProfileRepository maneja las operaciones de base de datos para perfiles de importación
+type ImportProfileRepository struct {
+	db *database.DB
+}
+
+// NewImportProfileRepository crea un nuevo repositorio de perfiles de importación
+func NewImportProfileRepository(db *database.DB) *ImportProfileRepository {
+	return &ImportProfileRepository{db: db}
+}
+
+// importProfileColumns lista las columnas de import_profiles en el orden que espera scanImportProfile
+const importProfileColumns = `id, nombre, mapeo, created_at, updated_at`
+
+// scanImportProfile lee una fila de import_profiles en un modelo
+func scanImportProfile(s rowScanner) (*models.ImportProfile, error) {
+	var profile models.ImportProfile
+	err := s.Scan(
+		&profile.ID,
+		&profile.Nombre,
+		&profile.Mapeo,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Save crea un perfil o, si ya existe uno con el mismo nombre, reemplaza su mapeo
+func (r *ImportProfileRepository) Save(profile *models.ImportProfile) error {
+	query := `
+		INSERT INTO import_profiles (` + importProfileColumns + `)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(nombre) DO UPDATE SET mapeo = excluded.mapeo, updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		profile.ID,
+		profile.Nombre,
+		profile.Mapeo,
+		profile.CreatedAt,
+		profile.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error guardando perfil de importación: %w", err)
+	}
+
+	log.Printf("✅ Perfil de importación guardado: %s", profile.Nombre)
+	return nil
+}
+
+// GetByID obtiene un perfil de importación por su ID
+func (r *ImportProfileRepository) GetByID(id string) (*models.ImportProfile, error) {
+	return r.getBy("id", id)
+}
+
+// GetByName obtiene un perfil de importación por su nombre
+func (r *ImportProfileRepository) GetByName(nombre string) (*models.ImportProfile, error) {
+	return r.getBy("nombre", nombre)
+}
+
+// getBy obtiene un perfil de importación por una columna única
+func (r *ImportProfileRepository) getBy(column, value string) (*models.ImportProfile, error) {
+	query := `SELECT ` + importProfileColumns + ` FROM import_profiles WHERE ` + column + ` = ?`
+
+	profile, err := scanImportProfile(r.db.QueryRow(query, value))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("perfil de importación no encontrado: %s", value)
+		}
+		return nil, fmt.Errorf("error obteniendo perfil de importación: %w", err)
+	}
+
+	return profile, nil
+}
+
+// GetAll obtiene todos los perfiles de importación ordenados por nombre
+func (r *ImportProfileRepository) GetAll() ([]*models.ImportProfile, error) {
+	rows, err := r.db.Query(`SELECT ` + importProfileColumns + ` FROM import_profiles ORDER BY nombre ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo perfiles de importación: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*models.ImportProfile
+	for rows.Next() {
+		profile, err := scanImportProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando perfil de importación: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo perfiles de importación: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// Delete elimina un perfil de importación
+func (r *ImportProfileRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM import_profiles WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error eliminando perfil de importación: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error verificando eliminación: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("perfil de importación no encontrado: %s", id)
+	}
+
+	return nil
+}
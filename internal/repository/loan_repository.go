@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// LoanRepository maneja las operaciones de base de datos para préstamos
+type LoanRepository struct {
+	db *database.DB
+}
+
+// NewLoanRepository crea un nuevo repositorio de préstamos
+func NewLoanRepository(db *database.DB) *LoanRepository {
+	return &LoanRepository{db: db}
+}
+
+// loanColumns lista las columnas de loans en el orden que espera scanLoan
+const loanColumns = `
+	id, record_id, prestatario, fecha_salida, fecha_vencimiento,
+	fecha_devolucion, notas, created_at, updated_at`
+
+// scanLoan lee una fila de loans en un modelo
+func scanLoan(s rowScanner) (*models.Loan, error) {
+	var loan models.Loan
+	err := s.Scan(
+		&loan.ID,
+		&loan.RecordID,
+		&loan.Prestatario,
+		&loan.FechaSalida,
+		&loan.FechaVencimiento,
+		&loan.FechaDevolucion,
+		&loan.Notas,
+		&loan.CreatedAt,
+		&loan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &loan, nil
+}
+
+// scanLoans recorre un conjunto de filas de loans
+func scanLoans(rows *sql.Rows) ([]*models.Loan, error) {
+	var loans []*models.Loan
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando préstamo: %w", err)
+		}
+		loans = append(loans, loan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo préstamos: %w", err)
+	}
+
+	return loans, nil
+}
+
+// Create registra un nuevo préstamo
+func (r *LoanRepository) Create(loan *models.Loan) error {
+	query := `
+		INSERT INTO loans (
+			id, record_id, prestatario, fecha_salida, fecha_vencimiento,
+			fecha_devolucion, notas, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		loan.ID,
+		loan.RecordID,
+		loan.Prestatario,
+		loan.FechaSalida,
+		loan.FechaVencimiento,
+		loan.FechaDevolucion,
+		loan.Notas,
+		loan.CreatedAt,
+		loan.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error creando préstamo: %w", err)
+	}
+
+	log.Printf("✅ Préstamo registrado: %s a %s", loan.RecordID, loan.Prestatario)
+	return nil
+}
+
+// GetByID obtiene un préstamo por su ID
+func (r *LoanRepository) GetByID(id string) (*models.Loan, error) {
+	query := `SELECT ` + loanColumns + ` FROM loans WHERE id = ?`
+
+	loan, err := scanLoan(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("préstamo no encontrado: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo préstamo: %w", err)
+	}
+
+	return loan, nil
+}
+
+// GetByRecord obtiene el historial de préstamos de un record, del más reciente al más antiguo
+func (r *LoanRepository) GetByRecord(recordID string) ([]*models.Loan, error) {
+	query := `
+		SELECT ` + loanColumns + ` FROM loans
+		WHERE record_id = ?
+		ORDER BY fecha_salida DESC
+	`
+
+	rows, err := r.db.Query(query, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo préstamos del record: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLoans(rows)
+}
+
+// GetActiveByRecord obtiene el préstamo sin devolver de un record, o nil si está en casa
+func (r *LoanRepository) GetActiveByRecord(recordID string) (*models.Loan, error) {
+	query := `
+		SELECT ` + loanColumns + ` FROM loans
+		WHERE record_id = ? AND fecha_devolucion IS NULL
+		ORDER BY fecha_salida DESC
+		LIMIT 1
+	`
+
+	loan, err := scanLoan(r.db.QueryRow(query, recordID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error obteniendo préstamo activo: %w", err)
+	}
+
+	return loan, nil
+}
+
+// GetActive obtiene todos los préstamos sin devolver ordenados por vencimiento
+func (r *LoanRepository) GetActive() ([]*models.Loan, error) {
+	query := `
+		SELECT ` + loanColumns + ` FROM loans
+		WHERE fecha_devolucion IS NULL
+		AND EXISTS (SELECT 1 FROM records WHERE records.id = loans.record_id AND ` + activeRecord + `)
+		ORDER BY fecha_vencimiento IS NULL, fecha_vencimiento ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo préstamos activos: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLoans(rows)
+}
+
+// GetOverdue obtiene los préstamos activos cuya fecha de vencimiento ya pasó
+func (r *LoanRepository) GetOverdue(now time.Time) ([]*models.Loan, error) {
+	active, err := r.GetActive()
+	if err != nil {
+		return nil, err
+	}
+
+	var overdue []*models.Loan
+	for _, loan := range active {
+		if loan.IsOverdue(now) {
+			overdue = append(overdue, loan)
+		}
+	}
+	return overdue, nil
+}
+
+// MarkReturned registra la devolución de un préstamo
+func (r *LoanRepository) MarkReturned(id string, returnedAt time.Time) error {
+	query := `
+		UPDATE loans SET fecha_devolucion = ?, updated_at = ?
+		WHERE id = ? AND fecha_devolucion IS NULL
+	`
+
+	result, err := r.db.Exec(query, returnedAt, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("error registrando devolución: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error obteniendo filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("préstamo activo no encontrado: %s", id)
+	}
+
+	log.Printf("✅ Préstamo devuelto: %s", id)
+	return nil
+}
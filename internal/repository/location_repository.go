@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// LocationRepository maneja las operaciones de base de datos para ubicaciones.
+// Los records que cambian de ubicación al eliminar una quedan en su
+// historial a nombre del actor del repositorio (ver WithActor).
+type LocationRepository struct {
+	db    *database.DB
+	actor string
+}
+
+// NewLocationRepository crea un nuevo repositorio de ubicaciones
+func NewLocationRepository(db *database.DB) *LocationRepository {
+	return &LocationRepository{db: db}
+}
+
+// WithActor retorna una copia del repositorio que registra en el historial
+// de los records los cambios a nombre del actor indicado
+func (r *LocationRepository) WithActor(actor string) *LocationRepository {
+	return &LocationRepository{db: r.db, actor: actor}
+}
+
+// locationColumns lista las columnas de locations en el orden que espera scanLocation
+const locationColumns = `id, nombre, tipo, parent_id, notas, created_at, updated_at`
+
+// scanLocation lee una fila de locations en un modelo
+func scanLocation(s rowScanner) (*models.Location, error) {
+	var location models.Location
+	err := s.Scan(
+		&location.ID,
+		&location.Nombre,
+		&location.Tipo,
+		&location.ParentID,
+		&location.Notas,
+		&location.CreatedAt,
+		&location.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// scanLocations recorre un conjunto de filas de locations
+func scanLocations(rows *sql.Rows) ([]*models.Location, error) {
+	var locations []*models.Location
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando ubicación: %w", err)
+		}
+		locations = append(locations, location)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo ubicaciones: %w", err)
+	}
+
+	return locations, nil
+}
+
+// Create crea una nueva ubicación en la base de datos
+func (r *LocationRepository) Create(location *models.Location) error {
+	query := `
+		INSERT INTO locations (
+			id, nombre, tipo, parent_id, notas, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		location.ID,
+		location.Nombre,
+		location.Tipo,
+		location.ParentID,
+		location.Notas,
+		location.CreatedAt,
+		location.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error creando ubicación: %w", err)
+	}
+
+	log.Printf("✅ Ubicación creada: %s", location.Nombre)
+	return nil
+}
+
+// GetByID obtiene una ubicación por su ID
+func (r *LocationRepository) GetByID(id string) (*models.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM locations WHERE id = ?`
+
+	location, err := scanLocation(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("ubicación no encontrada: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo ubicación: %w", err)
+	}
+
+	return location, nil
+}
+
+// GetAll obtiene todas las ubicaciones ordenadas por nombre
+func (r *LocationRepository) GetAll() ([]*models.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM locations ORDER BY nombre ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo ubicaciones: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLocations(rows)
+}
+
+// GetChildren obtiene las ubicaciones contenidas directamente en otra
+func (r *LocationRepository) GetChildren(parentID string) ([]*models.Location, error) {
+	query := `
+		SELECT ` + locationColumns + ` FROM locations
+		WHERE parent_id = ?
+		ORDER BY nombre ASC
+	`
+
+	rows, err := r.db.Query(query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo sub-ubicaciones: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLocations(rows)
+}
+
+// GetPath obtiene la ruta completa de una ubicación, desde la raíz
+// hasta la ubicación indicada (inclusive)
+func (r *LocationRepository) GetPath(id string) ([]*models.Location, error) {
+	query := `
+		WITH RECURSIVE ancestors(id, depth) AS (
+			SELECT id, 0 FROM locations WHERE id = ?
+			UNION ALL
+			SELECT l.parent_id, a.depth + 1 FROM locations l
+			JOIN ancestors a ON l.id = a.id
+			WHERE l.parent_id IS NOT NULL AND a.depth < 32
+		)
+		SELECT ` + locationColumns + ` FROM locations
+		JOIN ancestors USING (id)
+		ORDER BY ancestors.depth DESC
+	`
+
+	rows, err := r.db.Query(query, id)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo ruta de ubicación: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLocations(rows)
+}
+
+// ErrLocationCycle indica que se quiso guardar una ubicación dentro de sí
+// misma o de una de sus sub-ubicaciones
+var ErrLocationCycle = errors.New("una ubicación no puede quedar dentro de sí misma ni de una de sus sub-ubicaciones")
+
+// Update actualiza una ubicación existente. Si la nueva ubicación
+// contenedora es la misma ubicación o una de sus sub-ubicaciones no la
+// modifica y retorna ErrLocationCycle.
+func (r *LocationRepository) Update(location *models.Location) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if location.ParentID.Valid {
+		// Recorre la nueva contenedora hacia la raíz; UNION descarta las
+		// filas repetidas, así que termina aunque ya exista un ciclo
+		var cycle bool
+		err := tx.QueryRow(`
+			WITH RECURSIVE ancestors(id) AS (
+				SELECT ?
+				UNION
+				SELECT l.parent_id FROM locations l
+				JOIN ancestors a ON l.id = a.id
+				WHERE l.parent_id IS NOT NULL
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = ?)`,
+			location.ParentID.String, location.ID,
+		).Scan(&cycle)
+		if err != nil {
+			return fmt.Errorf("error verificando ubicación contenedora: %w", err)
+		}
+		if cycle {
+			return ErrLocationCycle
+		}
+	}
+
+	query := `
+		UPDATE locations SET
+			nombre = ?, tipo = ?, parent_id = ?, notas = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := tx.Exec(query,
+		location.Nombre,
+		location.Tipo,
+		location.ParentID,
+		location.Notas,
+		location.UpdatedAt,
+		location.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("error actualizando ubicación: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("ubicación no encontrada: %s", location.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando ubicación: %w", err)
+	}
+
+	log.Printf("✅ Ubicación actualizada: %s", location.Nombre)
+	return nil
+}
+
+// Delete elimina una ubicación por su ID. Los records y sub-ubicaciones
+// que apuntaban a ella quedan sin ubicación asignada; el cambio queda en el
+// historial de cada record a nombre del actor del repositorio.
+func (r *LocationRepository) Delete(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	records, err := loadRecordsWhere(tx, `location_id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE records SET location_id = NULL, version = version + 1 WHERE location_id = ?`, id); err != nil {
+		return fmt.Errorf("error liberando records de la ubicación: %w", err)
+	}
+	if err := saveVersions(tx, models.HistoryUpdate, r.actor, records); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE locations SET parent_id = NULL WHERE parent_id = ?`, id); err != nil {
+		return fmt.Errorf("error liberando sub-ubicaciones: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM locations WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error eliminando ubicación: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error obteniendo filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("ubicación no encontrada: %s", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando eliminación: %w", err)
+	}
+
+	log.Printf("✅ Ubicación eliminada: %s", id)
+	return nil
+}
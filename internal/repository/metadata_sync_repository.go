@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// MetadataSyncRepository maneja las operaciones de base de datos para la
+// re-sincronización de metadatos y los cambios propuestos
+type MetadataSyncRepository struct {
+	db *database.DB
+}
+
+// NewMetadataSyncRepository crea un nuevo repositorio de sincronización de metadatos
+func NewMetadataSyncRepository(db *database.DB) *MetadataSyncRepository {
+	return &MetadataSyncRepository{db: db}
+}
+
+// metadataSyncColumns lista las columnas de metadata_syncs en el orden que espera scanMetadataSync
+const metadataSyncColumns = `record_id, provider, external_id, synced_at, error, precio_minimo, moneda, en_venta`
+
+// metadataChangeColumns lista las columnas de metadata_changes en el orden que espera scanMetadataChange
+const metadataChangeColumns = `id, record_id, provider, campo, valor_actual, valor_nuevo, estado, created_at, resolved_at`
+
+// activeChangeColumns son las columnas de metadata_changes unida con
+// records, para omitir los cambios de los records en la papelera
+const activeChangeColumns = `
+	metadata_changes.id, metadata_changes.record_id, metadata_changes.provider,
+	metadata_changes.campo, metadata_changes.valor_actual, metadata_changes.valor_nuevo,
+	metadata_changes.estado, metadata_changes.created_at, metadata_changes.resolved_at
+	FROM metadata_changes
+	JOIN records ON records.id = metadata_changes.record_id AND ` + activeRecord
+
+// scanMetadataSync lee una fila de metadata_syncs en un modelo
+func scanMetadataSync(s rowScanner) (*models.MetadataSync, error) {
+	var sync models.MetadataSync
+	err := s.Scan(
+		&sync.RecordID,
+		&sync.Provider,
+		&sync.ExternalID,
+		&sync.SyncedAt,
+		&sync.Error,
+		&sync.PrecioMinimo,
+		&sync.Moneda,
+		&sync.EnVenta,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
+
+// scanMetadataChange lee una fila de metadata_changes en un modelo
+func scanMetadataChange(s rowScanner) (*models.MetadataChange, error) {
+	var change models.MetadataChange
+	err := s.Scan(
+		&change.ID,
+		&change.RecordID,
+		&change.Provider,
+		&change.Campo,
+		&change.ValorActual,
+		&change.ValorNuevo,
+		&change.Estado,
+		&change.CreatedAt,
+		&change.ResolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// scanMetadataChanges lee todas las filas de metadata_changes
+func scanMetadataChanges(rows *sql.Rows) ([]*models.MetadataChange, error) {
+	var changes []*models.MetadataChange
+	for rows.Next() {
+		change, err := scanMetadataChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando cambio de metadatos: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando cambios de metadatos: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetDue obtiene un identificador del tipo indicado por cada record que
+// nunca se sincronizó o cuya última sincronización es anterior a before,
+// empezando por los más antiguos
+func (r *MetadataSyncRepository) GetDue(tipo string, before time.Time, limit int) ([]*models.Identifier, error) {
+	query := `
+		SELECT i.id, i.record_id, i.tipo, i.valor, i.descripcion, i.created_at
+		FROM identifiers i
+		JOIN records ON records.id = i.record_id AND ` + activeRecord + `
+		LEFT JOIN metadata_syncs s ON s.record_id = i.record_id
+		WHERE i.tipo = ? AND (s.record_id IS NULL OR s.synced_at < ?)
+		GROUP BY i.record_id
+		ORDER BY s.synced_at ASC, i.created_at ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, tipo, before.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records por sincronizar: %w", err)
+	}
+	defer rows.Close()
+
+	var identifiers []*models.Identifier
+	for rows.Next() {
+		identifier, err := scanIdentifier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando identificador: %w", err)
+		}
+		identifiers = append(identifiers, identifier)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando identificadores: %w", err)
+	}
+
+	return identifiers, nil
+}
+
+// Save guarda el resultado de sincronizar un record: su estado y los
+// cambios propuestos, que reemplazan a los pendientes anteriores
+func (r *MetadataSyncRepository) Save(sync *models.MetadataSync, changes []*models.MetadataChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO metadata_syncs (`+metadataSyncColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(record_id) DO UPDATE SET
+			provider = excluded.provider, external_id = excluded.external_id,
+			synced_at = excluded.synced_at, error = excluded.error,
+			precio_minimo = excluded.precio_minimo, moneda = excluded.moneda,
+			en_venta = excluded.en_venta
+	`,
+		sync.RecordID,
+		sync.Provider,
+		sync.ExternalID,
+		sync.SyncedAt.UTC(),
+		sync.Error,
+		sync.PrecioMinimo,
+		sync.Moneda,
+		sync.EnVenta,
+	)
+	if err != nil {
+		return fmt.Errorf("error guardando sincronización: %w", err)
+	}
+
+	// Un error de sincronización no descarta los cambios que ya estaban pendientes
+	if !sync.Error.Valid {
+		_, err = tx.Exec(`DELETE FROM metadata_changes WHERE record_id = ? AND estado = ?`, sync.RecordID, models.ChangePending)
+		if err != nil {
+			return fmt.Errorf("error eliminando cambios pendientes: %w", err)
+		}
+
+		for _, change := range changes {
+			_, err = tx.Exec(`INSERT INTO metadata_changes (`+metadataChangeColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				change.ID,
+				change.RecordID,
+				change.Provider,
+				change.Campo,
+				change.ValorActual,
+				change.ValorNuevo,
+				change.Estado,
+				change.CreatedAt.UTC(),
+				change.ResolvedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("error guardando cambio de metadatos: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando sincronización: %w", err)
+	}
+
+	return nil
+}
+
+// GetSync obtiene el estado de sincronización de un record, o nil si nunca se sincronizó
+func (r *MetadataSyncRepository) GetSync(recordID string) (*models.MetadataSync, error) {
+	query := `SELECT ` + metadataSyncColumns + ` FROM metadata_syncs WHERE record_id = ?`
+
+	sync, err := scanMetadataSync(r.db.QueryRow(query, recordID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error obteniendo sincronización: %w", err)
+	}
+
+	return sync, nil
+}
+
+// GetFailed obtiene las sincronizaciones cuya última ejecución falló, sin
+// las de records en la papelera
+func (r *MetadataSyncRepository) GetFailed(limit int) ([]*models.MetadataSync, error) {
+	query := `
+		SELECT ` + metadataSyncColumns + ` FROM metadata_syncs
+		JOIN records ON records.id = metadata_syncs.record_id AND ` + activeRecord + `
+		WHERE error IS NOT NULL
+		ORDER BY synced_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo sincronizaciones fallidas: %w", err)
+	}
+	defer rows.Close()
+
+	var syncs []*models.MetadataSync
+	for rows.Next() {
+		sync, err := scanMetadataSync(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando sincronización: %w", err)
+		}
+		syncs = append(syncs, sync)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterando sincronizaciones: %w", err)
+	}
+
+	return syncs, nil
+}
+
+// GetChange obtiene un cambio de metadatos por ID. Los cambios de records en
+// la papelera no se encuentran, para que no se apliquen.
+func (r *MetadataSyncRepository) GetChange(id string) (*models.MetadataChange, error) {
+	query := `SELECT ` + activeChangeColumns + ` WHERE metadata_changes.id = ?`
+
+	change, err := scanMetadataChange(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("cambio de metadatos no encontrado: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo cambio de metadatos: %w", err)
+	}
+
+	return change, nil
+}
+
+// GetByRecord obtiene los cambios de un record en el estado indicado, o
+// ninguno si el record está en la papelera
+func (r *MetadataSyncRepository) GetByRecord(recordID, estado string) ([]*models.MetadataChange, error) {
+	query := `
+		SELECT ` + activeChangeColumns + `
+		WHERE metadata_changes.record_id = ? AND metadata_changes.estado = ?
+		ORDER BY metadata_changes.created_at ASC, metadata_changes.rowid ASC
+	`
+
+	rows, err := r.db.Query(query, recordID, estado)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo cambios de metadatos: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMetadataChanges(rows)
+}
+
+// GetPending obtiene todos los cambios pendientes de revisión, agrupados por
+// record, sin los de records en la papelera
+func (r *MetadataSyncRepository) GetPending() ([]*models.MetadataChange, error) {
+	query := `
+		SELECT ` + activeChangeColumns + `
+		WHERE metadata_changes.estado = ?
+		ORDER BY metadata_changes.record_id ASC, metadata_changes.created_at ASC, metadata_changes.rowid ASC
+	`
+
+	rows, err := r.db.Query(query, models.ChangePending)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo cambios pendientes: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMetadataChanges(rows)
+}
+
+// CountPending cuenta los cambios pendientes de revisión, sin los de records
+// en la papelera
+func (r *MetadataSyncRepository) CountPending() (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM metadata_changes
+		JOIN records ON records.id = metadata_changes.record_id AND `+activeRecord+`
+		WHERE metadata_changes.estado = ?`,
+		models.ChangePending,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error contando cambios pendientes: %w", err)
+	}
+	return count, nil
+}
+
+// Resolve marca cambios pendientes como aceptados o rechazados
+func (r *MetadataSyncRepository) Resolve(ids []string, estado string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	query := `
+		UPDATE metadata_changes SET estado = ?, resolved_at = ?
+		WHERE estado = ? AND id IN (` + placeholders + `)
+	`
+
+	args := []any{estado, time.Now().UTC(), models.ChangePending}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("error resolviendo cambios de metadatos: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error verificando cambios resueltos: %w", err)
+	}
+
+	log.Printf("✅ Cambios de metadatos %s: %d", estado+"s", rowsAffected)
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+func TestMetadataQueuesHideTrash(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	syncs := NewMetadataSyncRepository(db)
+	failed := newTestRecord(t, records, "Nirvana", "Nevermind")
+	pending := newTestRecord(t, records, "Nirvana", "Bleach")
+
+	err := syncs.Save(&models.MetadataSync{
+		RecordID: failed.ID, Provider: "discogs", ExternalID: "1", SyncedAt: time.Now(),
+		Error: sql.NullString{String: "release no encontrado", Valid: true},
+	}, nil)
+	if err != nil {
+		t.Fatalf("error guardando sincronización: %v", err)
+	}
+	change := models.NewMetadataChange(pending.ID, "discogs", "sello", "", "Sub Pop")
+	err = syncs.Save(&models.MetadataSync{RecordID: pending.ID, Provider: "discogs", ExternalID: "2", SyncedAt: time.Now()}, []*models.MetadataChange{change})
+	if err != nil {
+		t.Fatalf("error guardando sincronización: %v", err)
+	}
+
+	// visible indica si las colas muestran la sincronización fallida y el
+	// cambio pendiente
+	check := func(t *testing.T, visible bool) {
+		t.Helper()
+		want := 0
+		if visible {
+			want = 1
+		}
+		if got, err := syncs.GetFailed(10); err != nil || len(got) != want {
+			t.Errorf("fallidas = %d (%v), se esperaba %d", len(got), err, want)
+		}
+		if got, err := syncs.GetPending(); err != nil || len(got) != want {
+			t.Errorf("pendientes = %d (%v), se esperaba %d", len(got), err, want)
+		}
+		if got, err := syncs.CountPending(); err != nil || got != want {
+			t.Errorf("total pendientes = %d (%v), se esperaba %d", got, err, want)
+		}
+		if got, err := syncs.GetByRecord(pending.ID, models.ChangePending); err != nil || len(got) != want {
+			t.Errorf("pendientes del record = %d (%v), se esperaba %d", len(got), err, want)
+		}
+		if _, err := syncs.GetChange(change.ID); (err == nil) != visible {
+			t.Errorf("cambio encontrado = %v, se esperaba %v", err == nil, visible)
+		}
+	}
+
+	check(t, true)
+	for _, record := range []*models.Record{failed, pending} {
+		if err := records.Delete(record.ID); err != nil {
+			t.Fatalf("error enviando a la papelera: %v", err)
+		}
+	}
+	check(t, false)
+	for _, record := range []*models.Record{failed, pending} {
+		if err := records.Restore(record.ID); err != nil {
+			t.Fatalf("error restaurando: %v", err)
+		}
+	}
+	check(t, true)
+}
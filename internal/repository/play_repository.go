@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// PlayRepository maneja las operaciones de base de datos del registro de escucha
+type PlayRepository struct {
+	db *database.DB
+}
+
+// NewPlayRepository crea un nuevo repositorio de sesiones de escucha
+func NewPlayRepository(db *database.DB) *PlayRepository {
+	return &PlayRepository{db: db}
+}
+
+// playColumns lista las columnas de plays en el orden que espera scanPlay
+const playColumns = `id, record_id, escuchado_en, lados, notas, created_at`
+
+// scanPlay lee una fila de plays en un modelo
+func scanPlay(s rowScanner) (*models.Play, error) {
+	var play models.Play
+	err := s.Scan(
+		&play.ID,
+		&play.RecordID,
+		&play.EscuchadoEn,
+		&play.Lados,
+		&play.Notas,
+		&play.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &play, nil
+}
+
+// Create registra una nueva sesión de escucha. La hora se guarda en UTC
+// para que las comparaciones por fecha sean consistentes.
+func (r *PlayRepository) Create(play *models.Play) error {
+	query := `
+		INSERT INTO plays (id, record_id, escuchado_en, lados, notas, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		play.ID,
+		play.RecordID,
+		play.EscuchadoEn.UTC(),
+		play.Lados,
+		play.Notas,
+		play.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error registrando escucha: %w", err)
+	}
+
+	log.Printf("✅ Escucha registrada: %s", play.RecordID)
+	return nil
+}
+
+// GetByRecord obtiene las últimas sesiones de escucha de un record
+func (r *PlayRepository) GetByRecord(recordID string, limit int) ([]*models.Play, error) {
+	query := `
+		SELECT ` + playColumns + ` FROM plays
+		WHERE record_id = ?
+		ORDER BY escuchado_en DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, recordID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo escuchas: %w", err)
+	}
+	defer rows.Close()
+
+	var plays []*models.Play
+	for rows.Next() {
+		play, err := scanPlay(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando escucha: %w", err)
+		}
+		plays = append(plays, play)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo escuchas: %w", err)
+	}
+
+	return plays, nil
+}
+
+// GetLastByRecord obtiene la sesión de escucha más reciente de un record, o nil si nunca se escuchó
+func (r *PlayRepository) GetLastByRecord(recordID string) (*models.Play, error) {
+	query := `
+		SELECT ` + playColumns + ` FROM plays
+		WHERE record_id = ?
+		ORDER BY escuchado_en DESC
+		LIMIT 1
+	`
+
+	play, err := scanPlay(r.db.QueryRow(query, recordID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error obteniendo última escucha: %w", err)
+	}
+
+	return play, nil
+}
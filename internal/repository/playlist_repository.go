@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// PlaylistRepository maneja las operaciones de base de datos para playlists
+type PlaylistRepository struct {
+	db *database.DB
+}
+
+// NewPlaylistRepository crea un nuevo repositorio de playlists
+func NewPlaylistRepository(db *database.DB) *PlaylistRepository {
+	return &PlaylistRepository{db: db}
+}
+
+// playlistColumns lista las columnas de playlists en el orden que espera scanPlaylist
+const playlistColumns = `
+	playlists.id, playlists.nombre, playlists.descripcion,
+	playlists.created_at, playlists.updated_at,
+	(
+		SELECT COUNT(*) FROM playlist_items pi
+		JOIN records ON records.id = pi.record_id AND ` + activeRecord + `
+		WHERE pi.playlist_id = playlists.id
+	) AS item_count`
+
+// playlistItemColumns lista las columnas de playlist_items en el orden que espera scanPlaylistItem
+const playlistItemColumns = `id, playlist_id, record_id, track_numero, posicion, notas, created_at`
+
+// scanPlaylist lee una fila de playlists en un modelo
+func scanPlaylist(s rowScanner) (*models.Playlist, error) {
+	var playlist models.Playlist
+	err := s.Scan(
+		&playlist.ID,
+		&playlist.Nombre,
+		&playlist.Descripcion,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt,
+		&playlist.ItemCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+// scanPlaylistItem lee una fila de playlist_items en un modelo
+func scanPlaylistItem(s rowScanner) (*models.PlaylistItem, error) {
+	var item models.PlaylistItem
+	err := s.Scan(
+		&item.ID,
+		&item.PlaylistID,
+		&item.RecordID,
+		&item.TrackNumero,
+		&item.Posicion,
+		&item.Notas,
+		&item.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create crea una nueva playlist vacía
+func (r *PlaylistRepository) Create(playlist *models.Playlist) error {
+	query := `
+		INSERT INTO playlists (id, nombre, descripcion, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		playlist.ID,
+		playlist.Nombre,
+		playlist.Descripcion,
+		playlist.CreatedAt,
+		playlist.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error creando playlist: %w", err)
+	}
+
+	log.Printf("✅ Playlist creada: %s", playlist.Nombre)
+	return nil
+}
+
+// GetByID obtiene una playlist por su ID
+func (r *PlaylistRepository) GetByID(id string) (*models.Playlist, error) {
+	query := `SELECT ` + playlistColumns + ` FROM playlists WHERE id = ?`
+
+	playlist, err := scanPlaylist(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("playlist no encontrada: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo playlist: %w", err)
+	}
+
+	return playlist, nil
+}
+
+// GetAll obtiene todas las playlists, las más recientes primero
+func (r *PlaylistRepository) GetAll() ([]*models.Playlist, error) {
+	rows, err := r.db.Query(`SELECT ` + playlistColumns + ` FROM playlists ORDER BY updated_at DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo playlists: %w", err)
+	}
+	defer rows.Close()
+
+	var playlists []*models.Playlist
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando playlist: %w", err)
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo playlists: %w", err)
+	}
+
+	return playlists, nil
+}
+
+// Delete elimina una playlist y sus entradas. Los records no se ven afectados.
+func (r *PlaylistRepository) Delete(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM playlist_items WHERE playlist_id = ?`, id); err != nil {
+		return fmt.Errorf("error eliminando entradas de la playlist: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM playlists WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error eliminando playlist: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("playlist no encontrada: %s", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando eliminación: %w", err)
+	}
+
+	log.Printf("✅ Playlist eliminada: %s", id)
+	return nil
+}
+
+// GetItems obtiene las entradas de una playlist en orden
+func (r *PlaylistRepository) GetItems(playlistID string) ([]*models.PlaylistItem, error) {
+	query := `
+		SELECT ` + playlistItemColumns + ` FROM playlist_items
+		WHERE playlist_id = ?
+		ORDER BY posicion ASC
+	`
+
+	rows, err := r.db.Query(query, playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo entradas de la playlist: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.PlaylistItem
+	for rows.Next() {
+		item, err := scanPlaylistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando entrada de playlist: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo entradas de playlist: %w", err)
+	}
+
+	return items, nil
+}
+
+// AddItem agrega una entrada al final de la playlist
+func (r *PlaylistRepository) AddItem(item *models.PlaylistItem) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`SELECT COALESCE(MAX(posicion), 0) + 1 FROM playlist_items WHERE playlist_id = ?`,
+		item.PlaylistID,
+	).Scan(&item.Posicion)
+	if err != nil {
+		return fmt.Errorf("error calculando posición: %w", err)
+	}
+
+	query := `
+		INSERT INTO playlist_items (` + playlistItemColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(query,
+		item.ID,
+		item.PlaylistID,
+		item.RecordID,
+		item.TrackNumero,
+		item.Posicion,
+		item.Notas,
+		item.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("error agregando a la playlist: %w", err)
+	}
+
+	if err := touchPlaylist(tx, item.PlaylistID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando entrada: %w", err)
+	}
+
+	log.Printf("✅ Agregado a la playlist %s: %s", item.PlaylistID, item.RecordID)
+	return nil
+}
+
+// RemoveItem quita una entrada de la playlist
+func (r *PlaylistRepository) RemoveItem(playlistID, itemID string) error {
+	result, err := r.db.Exec(`DELETE FROM playlist_items WHERE id = ? AND playlist_id = ?`, itemID, playlistID)
+	if err != nil {
+		return fmt.Errorf("error quitando entrada de la playlist: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("entrada de playlist no encontrada: %s", itemID)
+	}
+
+	log.Printf("✅ Entrada quitada de la playlist %s: %s", playlistID, itemID)
+	return touchPlaylist(r.db, playlistID)
+}
+
+// Reorder asigna las posiciones de las entradas según el orden de itemIDs.
+// Todos los cambios se aplican en una sola transacción.
+func (r *PlaylistRepository) Reorder(playlistID string, itemIDs []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, itemID := range itemIDs {
+		result, err := tx.Exec(
+			`UPDATE playlist_items SET posicion = ? WHERE id = ? AND playlist_id = ?`,
+			i+1, itemID, playlistID,
+		)
+		if err != nil {
+			return fmt.Errorf("error ordenando playlist: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("entrada de playlist no encontrada: %s", itemID)
+		}
+	}
+
+	if err := touchPlaylist(tx, playlistID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando orden: %w", err)
+	}
+
+	log.Printf("✅ Playlist reordenada: %s", playlistID)
+	return nil
+}
+
+// touchPlaylist actualiza la fecha de modificación de una playlist
+func touchPlaylist(ex execer, playlistID string) error {
+	if _, err := ex.Exec(`UPDATE playlists SET updated_at = ? WHERE id = ?`, time.Now(), playlistID); err != nil {
+		return fmt.Errorf("error actualizando playlist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// RecordImageRepository maneja las operaciones de base de datos para la
+// galería de imágenes de los records
+type RecordImageRepository struct {
+	db *database.DB
+}
+
+// NewRecordImageRepository crea un nuevo repositorio de imágenes de records
+func NewRecordImageRepository(db *database.DB) *RecordImageRepository {
+	return &RecordImageRepository{db: db}
+}
+
+// recordImageColumns lista las columnas de record_images en el orden que espera scanRecordImage
+const recordImageColumns = `id, record_id, url, tipo, leyenda, posicion, created_at`
+
+// scanRecordImage lee una fila de record_images en un modelo
+func scanRecordImage(s rowScanner) (*models.RecordImage, error) {
+	var image models.RecordImage
+	err := s.Scan(
+		&image.ID,
+		&image.RecordID,
+		&image.URL,
+		&image.Tipo,
+		&image.Leyenda,
+		&image.Posicion,
+		&image.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// Create agrega una imagen al final de la galería del record
+func (r *RecordImageRepository) Create(image *models.RecordImage) error {
+	err := r.db.QueryRow(`
+		INSERT INTO record_images (id, record_id, url, tipo, leyenda, posicion, created_at)
+		SELECT ?, ?, ?, ?, ?, COALESCE(MAX(posicion), 0) + 1, ?
+		FROM record_images WHERE record_id = ?
+		RETURNING posicion
+	`,
+		image.ID,
+		image.RecordID,
+		image.URL,
+		image.Tipo,
+		image.Leyenda,
+		image.CreatedAt,
+		image.RecordID,
+	).Scan(&image.Posicion)
+	if err != nil {
+		return fmt.Errorf("error agregando imagen: %w", err)
+	}
+
+	log.Printf("✅ Imagen agregada a %s: %s", image.RecordID, image.Tipo)
+	return nil
+}
+
+// GetByRecord obtiene la galería de un record en orden
+func (r *RecordImageRepository) GetByRecord(recordID string) ([]*models.RecordImage, error) {
+	query := `
+		SELECT ` + recordImageColumns + ` FROM record_images
+		WHERE record_id = ?
+		ORDER BY posicion ASC, created_at ASC
+	`
+
+	rows, err := r.db.Query(query, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo imágenes: %w", err)
+	}
+	defer rows.Close()
+
+	var images []*models.RecordImage
+	for rows.Next() {
+		image, err := scanRecordImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando imagen: %w", err)
+		}
+		images = append(images, image)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo imágenes: %w", err)
+	}
+
+	return images, nil
+}
+
+// GetByID obtiene una imagen de la galería de un record
+func (r *RecordImageRepository) GetByID(recordID, id string) (*models.RecordImage, error) {
+	query := `SELECT ` + recordImageColumns + ` FROM record_images WHERE id = ? AND record_id = ?`
+
+	image, err := scanRecordImage(r.db.QueryRow(query, id, recordID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("imagen no encontrada: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo imagen: %w", err)
+	}
+	return image, nil
+}
+
+// Update actualiza el tipo y la leyenda de una imagen
+func (r *RecordImageRepository) Update(image *models.RecordImage) error {
+	result, err := r.db.Exec(
+		`UPDATE record_images SET tipo = ?, leyenda = ? WHERE id = ? AND record_id = ?`,
+		image.Tipo, image.Leyenda, image.ID, image.RecordID,
+	)
+	if err != nil {
+		return fmt.Errorf("error actualizando imagen: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("imagen no encontrada: %s", image.ID)
+	}
+	return nil
+}
+
+// Delete quita una imagen de la galería. El archivo no se elimina del
+// almacenamiento, ya que otros records pueden usar la misma imagen.
+func (r *RecordImageRepository) Delete(recordID, id string) error {
+	result, err := r.db.Exec(`DELETE FROM record_images WHERE id = ? AND record_id = ?`, id, recordID)
+	if err != nil {
+		return fmt.Errorf("error eliminando imagen: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("imagen no encontrada: %s", id)
+	}
+
+	log.Printf("✅ Imagen eliminada: %s", id)
+	return nil
+}
+
+// Reorder asigna las posiciones de las imágenes según el orden de
+// imageIDs. Todos los cambios se aplican en una sola transacción.
+func (r *RecordImageRepository) Reorder(recordID string, imageIDs []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, imageID := range imageIDs {
+		result, err := tx.Exec(
+			`UPDATE record_images SET posicion = ? WHERE id = ? AND record_id = ?`,
+			i+1, imageID, recordID,
+		)
+		if err != nil {
+			return fmt.Errorf("error ordenando imágenes: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil || n == 0 {
+			return fmt.Errorf("imagen no encontrada: %s", imageID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando orden: %w", err)
+	}
+
+	log.Printf("✅ Galería reordenada: %s", recordID)
+	return nil
+}
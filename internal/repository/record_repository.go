@@ -2,17 +2,31 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/rodrwan/vinilo/internal/artwork"
 	"github.com/rodrwan/vinilo/internal/database"
 	"github.com/rodrwan/vinilo/internal/models"
 )
 
-// RecordRepository maneja las operaciones de base de datos para records
+// ErrConflict indica que el record cambió desde que se leyó, por lo que la
+// edición se hizo sobre una versión desactualizada y no se guardó
+var ErrConflict = errors.New("el record fue modificado desde que se cargó")
+
+// ErrNotFound indica que el record no existe
+var ErrNotFound = errors.New("record no encontrado")
+
+// RecordRepository maneja las operaciones de base de datos para records.
+// Cada escritura queda registrada en el historial a nombre del actor del
+// repositorio (ver WithActor).
 type RecordRepository struct {
-	db *database.DB
+	db    *database.DB
+	actor string
 }
 
 // NewRecordRepository crea un nuevo repositorio de records
@@ -20,17 +34,110 @@ func NewRecordRepository(db *database.DB) *RecordRepository {
 	return &RecordRepository{db: db}
 }
 
-// Create crea un nuevo record en la base de datos
-func (r *RecordRepository) Create(record *models.Record) error {
+// WithActor retorna una copia del repositorio que registra sus cambios en
+// el historial a nombre del actor indicado. Sin actor, los cambios quedan
+// a nombre de models.SystemActor.
+func (r *RecordRepository) WithActor(actor string) *RecordRepository {
+	return &RecordRepository{db: r.db, actor: actor}
+}
+
+// recordColumns lista las columnas de records en el orden que espera scanRecord.
+// Las últimas columnas son calculadas a partir de otras tablas.
+const recordColumns = `
+	id, titulo, artista, sello, catalog_number, anio, formato,
+	generos, estilos, pais, tracklist, duracion_total, arte_url,
+	condicion, notas, created_at, updated_at, location_id,
+	rating, review, campos, color_dominante, color_acento, deleted_at,
+	version,
+	EXISTS (
+		SELECT 1 FROM loans
+		WHERE loans.record_id = records.id AND loans.fecha_devolucion IS NULL
+	) AS on_loan,
+	(SELECT COUNT(*) FROM plays WHERE plays.record_id = records.id) AS play_count`
+
+// activeRecord filtra los records que no están en la papelera. Todas las
+// consultas de records lo incluyen salvo las de la papelera.
+const activeRecord = `records.deleted_at IS NULL`
+
+// rowScanner abstrae *sql.Row y *sql.Rows para reutilizar el escaneo
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRecord lee una fila de records en un modelo
+func scanRecord(s rowScanner) (*models.Record, error) {
+	var record models.Record
+	err := s.Scan(
+		&record.ID,
+		&record.Titulo,
+		&record.Artista,
+		&record.Sello,
+		&record.CatalogNumber,
+		&record.Anio,
+		&record.Formato,
+		&record.Generos,
+		&record.Estilos,
+		&record.Pais,
+		&record.Tracklist,
+		&record.DuracionTotal,
+		&record.ArteURL,
+		&record.Condicion,
+		&record.Notas,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+		&record.LocationID,
+		&record.Rating,
+		&record.Review,
+		&record.Campos,
+		&record.ColorDominante,
+		&record.ColorAcento,
+		&record.DeletedAt,
+		&record.Version,
+		&record.OnLoan,
+		&record.PlayCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// scanRecords recorre un conjunto de filas de records
+func scanRecords(rows *sql.Rows) ([]*models.Record, error) {
+	var records []*models.Record
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando record: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo records: %w", err)
+	}
+
+	return records, nil
+}
+
+// execer abstrae *database.DB y *sql.Tx para reutilizar escrituras dentro
+// y fuera de transacciones
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertRecord inserta un record usando la conexión o transacción indicada
+func insertRecord(ex execer, record *models.Record) error {
 	query := `
 		INSERT INTO records (
 			id, titulo, artista, sello, catalog_number, anio, formato,
 			generos, estilos, pais, tracklist, duracion_total, arte_url,
-			condicion, notas, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			condicion, notas, created_at, updated_at, location_id,
+			rating, review, campos, color_dominante, color_acento
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.Exec(query,
+	_, err := ex.Exec(query,
 		record.ID,
 		record.Titulo,
 		record.Artista,
@@ -48,41 +155,116 @@ func (r *RecordRepository) Create(record *models.Record) error {
 		record.Notas,
 		record.CreatedAt,
 		record.UpdatedAt,
+		record.LocationID,
+		record.Rating,
+		record.Review,
+		record.Campos,
+		record.ColorDominante,
+		record.ColorAcento,
 	)
 
 	if err != nil {
 		return fmt.Errorf("error creando record: %w", err)
 	}
 
+	return nil
+}
+
+// Create crea un nuevo record en la base de datos
+func (r *RecordRepository) Create(record *models.Record) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertRecord(tx, record); err != nil {
+		return err
+	}
+	if err := saveVersion(tx, record.ID, models.HistoryCreate, r.actor, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando record: %w", err)
+	}
+
 	log.Printf("✅ Record creado: %s - %s", record.Artista, record.Titulo)
 	return nil
 }
 
+// CreateMany crea varios records y sus identificadores en una sola
+// transacción: si alguno falla no se guarda ninguno
+func (r *RecordRepository) CreateMany(records []*models.Record, identifiers []*models.Identifier) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, record := range records {
+		if err := insertRecord(tx, record); err != nil {
+			return fmt.Errorf("%s - %s: %w", record.Artista, record.Titulo, err)
+		}
+		if err := saveVersion(tx, record.ID, models.HistoryCreate, r.actor, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, identifier := range identifiers {
+		if err := insertIdentifier(tx, identifier); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando records: %w", err)
+	}
+
+	log.Printf("✅ %d records creados", len(records))
+	return nil
+}
+
+// FindDuplicate busca un record con el mismo artista, título y número de
+// catálogo (sin distinguir mayúsculas), o retorna nil si no existe. Sin
+// número de catálogo basta con que coincidan artista y título.
+func (r *RecordRepository) FindDuplicate(artista, titulo string, catalogNumber sql.NullString) (*models.Record, error) {
+	query := `
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + activeRecord + ` AND LOWER(artista) = LOWER(?) AND LOWER(titulo) = LOWER(?)
+		AND (? = '' OR LOWER(IFNULL(catalog_number, '')) = LOWER(?))
+		LIMIT 1
+	`
+
+	record, err := scanRecord(r.db.QueryRow(query, artista, titulo, catalogNumber.String, catalogNumber.String))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error buscando duplicados: %w", err)
+	}
+
+	return record, nil
+}
+
 // GetByID obtiene un record por su ID
 func (r *RecordRepository) GetByID(id string) (*models.Record, error) {
-	query := `SELECT * FROM records WHERE id = ?`
+	query := `SELECT ` + recordColumns + ` FROM records WHERE id = ? AND ` + activeRecord
 
-	var record models.Record
-	err := r.db.QueryRow(query, id).Scan(
-		&record.ID,
-		&record.Titulo,
-		&record.Artista,
-		&record.Sello,
-		&record.CatalogNumber,
-		&record.Anio,
-		&record.Formato,
-		&record.Generos,
-		&record.Estilos,
-		&record.Pais,
-		&record.Tracklist,
-		&record.DuracionTotal,
-		&record.ArteURL,
-		&record.Condicion,
-		&record.Notas,
-		&record.CreatedAt,
-		&record.UpdatedAt,
-	)
+	record, err := scanRecord(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("record no encontrado: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo record: %w", err)
+	}
+
+	return record, nil
+}
 
+// GetByIDWithDeleted obtiene un record por su ID, esté o no en la papelera
+func (r *RecordRepository) GetByIDWithDeleted(id string) (*models.Record, error) {
+	record, err := scanRecord(r.db.QueryRow(`SELECT `+recordColumns+` FROM records WHERE id = ?`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("record no encontrado: %s", id)
@@ -90,14 +272,15 @@ func (r *RecordRepository) GetByID(id string) (*models.Record, error) {
 		return nil, fmt.Errorf("error obteniendo record: %w", err)
 	}
 
-	return &record, nil
+	return record, nil
 }
 
 // GetAll obtiene todos los records con paginación
 func (r *RecordRepository) GetAll(limit, offset int) ([]*models.Record, error) {
 	query := `
-		SELECT * FROM records 
-		ORDER BY created_at DESC 
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + activeRecord + `
+		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
 	`
 
@@ -107,87 +290,17 @@ func (r *RecordRepository) GetAll(limit, offset int) ([]*models.Record, error) {
 	}
 	defer rows.Close()
 
-	var records []*models.Record
-	for rows.Next() {
-		var record models.Record
-		err := rows.Scan(
-			&record.ID,
-			&record.Titulo,
-			&record.Artista,
-			&record.Sello,
-			&record.CatalogNumber,
-			&record.Anio,
-			&record.Formato,
-			&record.Generos,
-			&record.Estilos,
-			&record.Pais,
-			&record.Tracklist,
-			&record.DuracionTotal,
-			&record.ArteURL,
-			&record.Condicion,
-			&record.Notas,
-			&record.CreatedAt,
-			&record.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error escaneando record: %w", err)
-		}
-		records = append(records, &record)
-	}
-
-	return records, nil
+	return scanRecords(rows)
 }
 
 // Search busca records por término
 func (r *RecordRepository) Search(term string, limit, offset int) ([]*models.Record, error) {
-	query := `
-		SELECT * FROM records 
-		WHERE titulo LIKE ? OR artista LIKE ? OR sello LIKE ?
-		ORDER BY created_at DESC 
-		LIMIT ? OFFSET ?
-	`
-
-	searchTerm := "%" + strings.ToLower(term) + "%"
-	rows, err := r.db.Query(query, searchTerm, searchTerm, searchTerm, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("error buscando records: %w", err)
-	}
-	defer rows.Close()
-
-	var records []*models.Record
-	for rows.Next() {
-		var record models.Record
-		err := rows.Scan(
-			&record.ID,
-			&record.Titulo,
-			&record.Artista,
-			&record.Sello,
-			&record.CatalogNumber,
-			&record.Anio,
-			&record.Formato,
-			&record.Generos,
-			&record.Estilos,
-			&record.Pais,
-			&record.Tracklist,
-			&record.DuracionTotal,
-			&record.ArteURL,
-			&record.Condicion,
-			&record.Notas,
-			&record.CreatedAt,
-			&record.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error escaneando record: %w", err)
-		}
-		records = append(records, &record)
-	}
-
-	return records, nil
+	return r.List(models.RecordFilter{Search: term}, limit, offset)
 }
 
 // Count obtiene el total de records
 func (r *RecordRepository) Count() (int, error) {
-	query := `SELECT COUNT(*) FROM records`
+	query := `SELECT COUNT(*) FROM records WHERE ` + activeRecord
 
 	var count int
 	err := r.db.QueryRow(query).Scan(&count)
@@ -200,33 +313,26 @@ func (r *RecordRepository) Count() (int, error) {
 
 // CountSearch obtiene el total de records que coinciden con la búsqueda
 func (r *RecordRepository) CountSearch(term string) (int, error) {
-	query := `
-		SELECT COUNT(*) FROM records 
-		WHERE titulo LIKE ? OR artista LIKE ? OR sello LIKE ?
-	`
-
-	searchTerm := "%" + strings.ToLower(term) + "%"
-	var count int
-	err := r.db.QueryRow(query, searchTerm, searchTerm, searchTerm).Scan(&count)
-	if err != nil {
-		return 0, fmt.Errorf("error contando búsqueda: %w", err)
-	}
-
-	return count, nil
+	return r.CountList(models.RecordFilter{Search: term})
 }
 
-// Update actualiza un record existente
-func (r *RecordRepository) Update(record *models.Record) error {
+// updateRecord guarda los campos de un record usando la conexión o
+// transacción indicada, siempre que siga en record.Version. Si otra escritura
+// lo cambió antes retorna ErrConflict; si no, incrementa record.Version.
+func updateRecord(ex execer, record *models.Record) error {
 	query := `
-		UPDATE records SET 
-			titulo = ?, artista = ?, sello = ?, catalog_number = ?, 
+		UPDATE records SET
+			titulo = ?, artista = ?, sello = ?, catalog_number = ?,
 			anio = ?, formato = ?, generos = ?, estilos = ?, pais = ?,
-			tracklist = ?, duracion_total = ?, arte_url = ?, 
-			condicion = ?, notas = ?, updated_at = ?
-		WHERE id = ?
+			tracklist = ?, duracion_total = ?, arte_url = ?,
+			condicion = ?, notas = ?, updated_at = ?, location_id = ?,
+			rating = ?, review = ?, campos = ?,
+			color_dominante = ?, color_acento = ?,
+			version = version + 1
+		WHERE id = ? AND version = ?
 	`
 
-	_, err := r.db.Exec(query,
+	result, err := ex.Exec(query,
 		record.Titulo,
 		record.Artista,
 		record.Sello,
@@ -242,45 +348,307 @@ func (r *RecordRepository) Update(record *models.Record) error {
 		record.Condicion,
 		record.Notas,
 		record.UpdatedAt,
+		record.LocationID,
+		record.Rating,
+		record.Review,
+		record.Campos,
+		record.ColorDominante,
+		record.ColorAcento,
 		record.ID,
+		record.Version,
 	)
 
 	if err != nil {
 		return fmt.Errorf("error actualizando record: %w", err)
 	}
 
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return ErrConflict
+	}
+	record.Version++
+	return nil
+}
+
+// Update actualiza un record existente
+func (r *RecordRepository) Update(record *models.Record) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := loadRecord(tx, record.ID)
+	if err != nil {
+		return err
+	}
+	if err := updateRecord(tx, record); err != nil {
+		return err
+	}
+	if err := saveVersion(tx, record.ID, models.HistoryUpdate, r.actor, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando record: %w", err)
+	}
+
 	log.Printf("✅ Record actualizado: %s - %s", record.Artista, record.Titulo)
 	return nil
 }
 
-// Delete elimina un record por su ID
+// Delete envía un record a la papelera. Sigue en la base de datos, con sus
+// escuchas, préstamos e imágenes, hasta que se restaura o se purga.
 func (r *RecordRepository) Delete(id string) error {
-	query := `DELETE FROM records WHERE id = ?`
+	err := r.change(id, models.HistoryDelete, 0, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.Exec(`UPDATE records SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND `+activeRecord, id)
+	})
+	if err != nil {
+		return err
+	}
 
-	result, err := r.db.Exec(query, id)
+	log.Printf("🗑️ Record enviado a la papelera: %s", id)
+	return nil
+}
+
+// Restore devuelve a la colección un record de la papelera
+func (r *RecordRepository) Restore(id string) error {
+	err := r.change(id, models.HistoryRestore, 0, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.Exec(`UPDATE records SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	})
 	if err != nil {
-		return fmt.Errorf("error eliminando record: %w", err)
+		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	log.Printf("✅ Record restaurado: %s", id)
+	return nil
+}
+
+// change aplica una escritura a un record y la registra en el historial, en
+// una sola transacción. La escritura debe afectar al record: si no lo hace
+// y el record ya no está en la versión esperada (0 si no se verifica) se
+// retorna ErrConflict, y si no, se considera que no existe.
+func (r *RecordRepository) change(id, accion string, version int, write func(tx *sql.Tx) (sql.Result, error)) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("error obteniendo filas afectadas: %w", err)
+		return fmt.Errorf("error iniciando transacción: %w", err)
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
+	before, err := loadRecord(tx, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := write(tx)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		if version != 0 && before.Version != version {
+			return ErrConflict
+		}
 		return fmt.Errorf("record no encontrado: %s", id)
 	}
 
-	log.Printf("✅ Record eliminado: %s", id)
+	if err := saveVersion(tx, id, accion, r.actor, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando cambio: %w", err)
+	}
+	return nil
+}
+
+// Revert devuelve los campos de un record a los de una versión anterior de
+// su historial. La reversión queda registrada como una versión nueva.
+func (r *RecordRepository) Revert(version *models.RecordVersion) error {
+	previous, err := version.GetRecord()
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := loadRecord(tx, version.RecordID)
+	if err != nil {
+		return err
+	}
+	if before.DeletedAt.Valid {
+		return fmt.Errorf("record no encontrado: %s", version.RecordID)
+	}
+
+	record := *before
+	record.RevertTo(previous)
+	record.UpdatedAt = time.Now()
+	if err := updateRecord(tx, &record); err != nil {
+		return err
+	}
+	if err := saveVersion(tx, record.ID, models.HistoryRevert, r.actor, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando reversión: %w", err)
+	}
+
+	log.Printf("✅ Record %s revertido a la versión %d", record.ID, version.Numero)
+	return nil
+}
+
+// GetDeleted obtiene los records de la papelera, los eliminados más
+// recientemente primero
+func (r *RecordRepository) GetDeleted(limit, offset int) ([]*models.Record, error) {
+	query := `
+		SELECT ` + recordColumns + ` FROM records
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo papelera: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// CountDeleted obtiene el total de records en la papelera
+func (r *RecordRepository) CountDeleted() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM records WHERE deleted_at IS NOT NULL`).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error contando papelera: %w", err)
+	}
+
+	return count, nil
+}
+
+// recordTables lista las tablas con filas que dependen de un record. Las
+// claves foráneas de SQLite no están activas, así que al purgar un record
+// se eliminan a mano. El historial no está en la lista: se conserva para
+// saber quién cambió y eliminó el record.
+var recordTables = []string{
+	"plays",
+	"loans",
+	"record_tags",
+	"playlist_items",
+	"identifiers",
+	"record_images",
+	"metadata_syncs",
+	"metadata_changes",
+	"artwork_mirrors",
+}
+
+// purgeRecord elimina definitivamente un record de la papelera y todo lo
+// que depende de él, dentro de una transacción. Antes de eliminarlo deja en
+// el historial una última versión con la purga.
+func purgeRecord(tx *sql.Tx, id, actor string) error {
+	before, err := loadRecord(tx, id)
+	if err != nil || !before.DeletedAt.Valid {
+		return fmt.Errorf("record no encontrado en la papelera: %s", id)
+	}
+	if err := saveVersion(tx, id, models.HistoryPurge, actor, before); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM records WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("error purgando record: %w", err)
+	}
+
+	for _, table := range recordTables {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE record_id = ?`, id); err != nil {
+			return fmt.Errorf("error purgando %s del record: %w", table, err)
+		}
+	}
+	_, err = tx.Exec(`DELETE FROM duplicate_dismissals WHERE record_a = ? OR record_b = ?`, id, id)
+	if err != nil {
+		return fmt.Errorf("error purgando duplicados descartados del record: %w", err)
+	}
 	return nil
 }
 
+// Purge elimina definitivamente un record de la papelera, con sus
+// escuchas, préstamos, imágenes, identificadores y tags. Su historial se
+// conserva, con la purga como última versión.
+func (r *RecordRepository) Purge(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := purgeRecord(tx, id, r.actor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando purga: %w", err)
+	}
+
+	log.Printf("✅ Record purgado: %s", id)
+	return nil
+}
+
+// PurgeDeletedBefore elimina definitivamente los records enviados a la
+// papelera antes de la fecha indicada y retorna cuántos purgó
+func (r *RecordRepository) PurgeDeletedBefore(before time.Time) (int, error) {
+	rows, err := r.db.Query(
+		`SELECT id FROM records WHERE deleted_at IS NOT NULL AND deleted_at < ?`,
+		before.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error obteniendo records por purgar: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("error escaneando record por purgar: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error recorriendo records por purgar: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		if err := purgeRecord(tx, id, r.actor); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error confirmando purga: %w", err)
+	}
+
+	log.Printf("✅ %d records purgados de la papelera", len(ids))
+	return len(ids), nil
+}
+
 // GetByArtist obtiene records por artista
 func (r *RecordRepository) GetByArtist(artist string, limit, offset int) ([]*models.Record, error) {
 	query := `
-		SELECT * FROM records 
-		WHERE artista LIKE ? 
-		ORDER BY anio DESC, titulo ASC 
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + activeRecord + ` AND artista LIKE ?
+		ORDER BY anio DESC, titulo ASC
 		LIMIT ? OFFSET ?
 	`
 
@@ -291,33 +659,539 @@ func (r *RecordRepository) GetByArtist(artist string, limit, offset int) ([]*mod
 	}
 	defer rows.Close()
 
-	var records []*models.Record
-	for rows.Next() {
-		var record models.Record
-		err := rows.Scan(
-			&record.ID,
-			&record.Titulo,
-			&record.Artista,
-			&record.Sello,
-			&record.CatalogNumber,
-			&record.Anio,
-			&record.Formato,
-			&record.Generos,
-			&record.Estilos,
-			&record.Pais,
-			&record.Tracklist,
-			&record.DuracionTotal,
-			&record.ArteURL,
-			&record.Condicion,
-			&record.Notas,
-			&record.CreatedAt,
-			&record.UpdatedAt,
-		)
+	return scanRecords(rows)
+}
+
+// locationSubtree es un CTE con los IDs de una ubicación y todas sus descendientes
+const locationSubtree = `
+	WITH RECURSIVE subtree(id, depth) AS (
+		SELECT id, 0 FROM locations WHERE id = ?
+		UNION ALL
+		SELECT l.id, s.depth + 1 FROM locations l
+		JOIN subtree s ON l.parent_id = s.id
+		WHERE s.depth < 32
+	)
+`
+
+// GetByLocation obtiene los records guardados en una ubicación o en
+// cualquiera de sus sub-ubicaciones
+func (r *RecordRepository) GetByLocation(locationID string, limit, offset int) ([]*models.Record, error) {
+	query := locationSubtree + `
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + activeRecord + ` AND location_id IN (SELECT id FROM subtree)
+		ORDER BY artista ASC, anio ASC, titulo ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, locationID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records por ubicación: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// CountByLocation obtiene el total de records en una ubicación y sus sub-ubicaciones
+func (r *RecordRepository) CountByLocation(locationID string) (int, error) {
+	query := locationSubtree + `
+		SELECT COUNT(*) FROM records
+		WHERE ` + activeRecord + ` AND location_id IN (SELECT id FROM subtree)
+	`
+
+	var count int
+	err := r.db.QueryRow(query, locationID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error contando records por ubicación: %w", err)
+	}
+
+	return count, nil
+}
+
+// MoveToLocation asigna la misma ubicación a varios records en una sola
+// transacción. Un locationID vacío deja los records sin ubicación. Retorna
+// cuántos se movieron y cuántos no existen o están en la papelera.
+func (r *RecordRepository) MoveToLocation(ids []string, locationID string) (moved, missing int, err error) {
+	location := sql.NullString{String: locationID, Valid: locationID != ""}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		UPDATE records SET location_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ?
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error preparando movimiento: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, id := range ids {
+		before, err := loadRecord(tx, id)
+		if errors.Is(err, ErrNotFound) || (err == nil && before.DeletedAt.Valid) {
+			missing++
+			continue
+		}
 		if err != nil {
-			return nil, fmt.Errorf("error escaneando record: %w", err)
+			return 0, 0, err
+		}
+		if _, err := stmt.Exec(location, id); err != nil {
+			return 0, 0, fmt.Errorf("error moviendo record %s: %w", id, err)
+		}
+		if err := saveVersion(tx, id, models.HistoryUpdate, r.actor, before); err != nil {
+			return 0, 0, err
 		}
-		records = append(records, &record)
+		moved++
 	}
 
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("error confirmando movimiento: %w", err)
+	}
+
+	log.Printf("✅ %d records movidos a la ubicación %s (%d no encontrados)", moved, locationID, missing)
+	return moved, missing, nil
+}
+
+// Bulk aplica una acción masiva a varios records en una sola transacción:
+// si alguno falla no se aplica a ninguno. Si alguno ya no está en la versión
+// indicada en versions (los que no aparecen no se verifican) retorna
+// ErrConflict sin modificar ninguno. Los IDs que no existen o están en la
+// papelera se cuentan como faltantes, y los records que ya tenían el valor
+// quedan sin modificar y fuera del historial.
+func (r *RecordRepository) Bulk(ids []string, versions map[string]int, action models.BulkAction) (*models.BulkResult, error) {
+	if err := action.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &models.BulkResult{Action: action, Selected: len(ids)}
+	for _, id := range ids {
+		before, err := loadRecord(tx, id)
+		if errors.Is(err, ErrNotFound) || (err == nil && before.DeletedAt.Valid) {
+			result.Missing++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if version, ok := versions[id]; ok && before.Version != version {
+			return nil, fmt.Errorf("%w: %s", ErrConflict, id)
+		}
+
+		changed, changes, err := r.bulkRecord(tx, before, action)
+		if err != nil {
+			return nil, fmt.Errorf("error aplicando acción a %s: %w", id, err)
+		}
+		if !changed {
+			result.Unchanged = append(result.Unchanged, before)
+			continue
+		}
+		result.Changed = append(result.Changed, models.BulkRecordResult{Record: before, Changes: changes})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error confirmando acción masiva: %w", err)
+	}
+
+	log.Printf("✅ Acción masiva %q: %d de %d records modificados", action.GetLabel(), len(result.Changed), len(ids))
+	return result, nil
+}
+
+// bulkRecord aplica una acción masiva a un record dentro de la transacción
+// y retorna si cambió y qué campos cambiaron
+func (r *RecordRepository) bulkRecord(tx *sql.Tx, before *models.Record, action models.BulkAction) (bool, []models.FieldChange, error) {
+	switch action.Accion {
+	case models.BulkAddTag, models.BulkRemoveTag:
+		changes, err := r.bulkTag(tx, before, action)
+		return len(changes) > 0, changes, err
+
+	case models.BulkDelete:
+		_, err := tx.Exec(`UPDATE records SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ?`, before.ID)
+		if err != nil {
+			return false, nil, err
+		}
+		return true, nil, saveVersion(tx, before.ID, models.HistoryDelete, r.actor, before)
+	}
+
+	record := *before
+	if err := record.ApplyBulk(action); err != nil {
+		return false, nil, err
+	}
+	changes := models.DiffHistory(before, &record)
+	if len(changes) == 0 {
+		return false, nil, nil
+	}
+
+	record.UpdatedAt = time.Now()
+	if err := updateRecord(tx, &record); err != nil {
+		return false, nil, err
+	}
+	return true, changes, saveVersion(tx, before.ID, models.HistoryUpdate, r.actor, before)
+}
+
+// bulkTag agrega o quita un tag de un record dentro de la transacción y
+// retorna el cambio en sus tags, registrado en su historial, o nada si ya
+// lo tenía o no lo tenía
+func (r *RecordRepository) bulkTag(tx *sql.Tx, before *models.Record, action models.BulkAction) ([]models.FieldChange, error) {
+	antes, err := recordTagNames(tx, before.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := models.NewTag(action.Valor)
+	if action.Accion == models.BulkAddTag {
+		err = assignTag(tx, before.ID, tag)
+	} else {
+		_, err = tx.Exec(`
+			DELETE FROM record_tags
+			WHERE record_id = ? AND tag_id IN (SELECT id FROM tags WHERE nombre = ?)`,
+			before.ID, tag.Nombre,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return saveTagChange(tx, before, antes, r.actor)
+}
+
+// GetByIDs obtiene varios records por ID, indexados por su ID
+func (r *RecordRepository) GetByIDs(ids []string) (map[string]*models.Record, error) {
+	records := make(map[string]*models.Record, len(ids))
+	if len(ids) == 0 {
+		return records, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	query := `SELECT ` + recordColumns + ` FROM records WHERE ` + activeRecord + ` AND id IN (` + placeholders + `)`
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records: %w", err)
+	}
+	defer rows.Close()
+
+	list, err := scanRecords(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range list {
+		records[record.ID] = record
+	}
 	return records, nil
 }
+
+// GetMostPlayed obtiene los records con más sesiones de escucha
+func (r *RecordRepository) GetMostPlayed(limit, offset int) ([]*models.Record, error) {
+	query := `
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + activeRecord + ` AND EXISTS (SELECT 1 FROM plays WHERE plays.record_id = records.id)
+		ORDER BY play_count DESC, artista ASC, titulo ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records más escuchados: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// GetNeverPlayed obtiene los records sin ninguna sesión de escucha
+func (r *RecordRepository) GetNeverPlayed(limit, offset int) ([]*models.Record, error) {
+	query := `
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + activeRecord + ` AND NOT EXISTS (SELECT 1 FROM plays WHERE plays.record_id = records.id)
+		ORDER BY created_at ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records nunca escuchados: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// GetNotPlayedSince obtiene los records que se escucharon alguna vez pero
+// no desde la fecha indicada
+func (r *RecordRepository) GetNotPlayedSince(since time.Time, limit, offset int) ([]*models.Record, error) {
+	query := `
+		SELECT ` + recordColumns + ` FROM records
+		WHERE ` + activeRecord + ` AND EXISTS (SELECT 1 FROM plays WHERE plays.record_id = records.id)
+		AND NOT EXISTS (
+			SELECT 1 FROM plays
+			WHERE plays.record_id = records.id AND plays.escuchado_en >= ?
+		)
+		ORDER BY artista ASC, titulo ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, since.UTC(), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records sin escuchar: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// recordFilterSQL traduce un filtro a una cláusula WHERE y sus argumentos
+func recordFilterSQL(f models.RecordFilter) (string, []any) {
+	conditions := []string{activeRecord}
+	var args []any
+
+	if f.Search != "" {
+		searchTerm := "%" + strings.ToLower(f.Search) + "%"
+		compactTerm := "%" + models.CompactIdentifier(f.Search) + "%"
+		conditions = append(conditions, recordSearchSQL)
+		args = append(args, searchTerm, searchTerm, searchTerm, searchTerm, searchTerm, compactTerm)
+	}
+
+	if f.MinRating > 0 {
+		conditions = append(conditions, `rating >= ?`)
+		args = append(args, f.MinRating)
+	}
+
+	if f.Tag != "" {
+		conditions = append(conditions, recordTagSQL)
+		args = append(args, f.Tag)
+	}
+
+	for _, c := range f.Conditions {
+		condition, arg := filterConditionSQL(c)
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// recordSearchSQL busca un término en los datos principales del record y
+// en sus identificadores. El último argumento es el término sin espacios ni
+// guiones, para encontrar códigos de barras escritos en grupos.
+const recordSearchSQL = `(
+	titulo LIKE ? OR artista LIKE ? OR sello LIKE ? OR catalog_number LIKE ?
+	OR EXISTS (
+		SELECT 1 FROM identifiers
+		WHERE identifiers.record_id = records.id
+		AND (identifiers.valor LIKE ? OR identifiers.valor LIKE ?)
+	))`
+
+// recordTagSQL verifica que el record tenga el tag indicado
+const recordTagSQL = `EXISTS (
+	SELECT 1 FROM record_tags rt JOIN tags t ON t.id = rt.tag_id
+	WHERE rt.record_id = records.id AND t.nombre = ?)`
+
+// recordListSQL verifica que una lista JSON del record (géneros o estilos)
+// contenga un valor, sin distinguir mayúsculas
+const recordListSQL = `EXISTS (
+	SELECT 1 FROM json_each(CASE WHEN json_valid(records.%s) THEN records.%s ELSE '[]' END)
+	WHERE LOWER(json_each.value) = LOWER(?))`
+
+// filterListColumns asocia los campos de lista de las expresiones de filtro con su columna
+var filterListColumns = map[string]string{
+	"genero": "generos",
+	"estilo": "estilos",
+}
+
+// filterConditionSQL traduce una condición de una expresión de filtro a SQL.
+// Los campos ya vienen validados por models.ParseFilterExpression, por lo
+// que pueden interpolarse como nombres de columna.
+func filterConditionSQL(c models.FilterCondition) (string, any) {
+	negate := ""
+	if c.Op == "!=" {
+		negate = "NOT "
+	}
+
+	kind, _ := models.FilterFieldKind(c.Field)
+	switch kind {
+	case models.FilterKindNumber:
+		value, _ := strconv.ParseFloat(c.Value, 64)
+		return fmt.Sprintf("%s %s ?", c.Field, c.Op), value
+	case models.FilterKindList:
+		column := filterListColumns[c.Field]
+		return negate + fmt.Sprintf(recordListSQL, column, column), c.Value
+	case models.FilterKindTag:
+		return negate + recordTagSQL, models.NormalizeTag(c.Value)
+	case models.FilterKindCustom:
+		return customFieldConditionSQL(c)
+	}
+
+	if c.Op == "~" {
+		return fmt.Sprintf("LOWER(%s) LIKE ?", c.Field), "%" + strings.ToLower(c.Value) + "%"
+	}
+	if c.Op == "!=" {
+		return fmt.Sprintf("LOWER(IFNULL(%s, '')) != LOWER(?)", c.Field), c.Value
+	}
+	return fmt.Sprintf("LOWER(%s) = LOWER(?)", c.Field), c.Value
+}
+
+// customFieldConditionSQL traduce una condición sobre un campo personalizado.
+// Los booleanos se comparan como 1/0 y los números numéricamente; el resto
+// como texto sin distinguir mayúsculas (las fechas AAAA-MM-DD se ordenan bien como texto).
+// "Contiene" siempre compara como texto.
+func customFieldConditionSQL(c models.FilterCondition) (string, any) {
+	column := fmt.Sprintf("json_extract(records.campos, '$.%s')", strings.TrimPrefix(c.Field, models.CustomFieldFilterPrefix))
+
+	if c.Op == "~" {
+		return fmt.Sprintf("LOWER(%s) LIKE ?", column), "%" + strings.ToLower(c.Value) + "%"
+	}
+
+	switch strings.ToLower(c.Value) {
+	case "true":
+		return fmt.Sprintf("IFNULL(%s, 0) %s ?", column, c.Op), 1
+	case "false":
+		return fmt.Sprintf("IFNULL(%s, 0) %s ?", column, c.Op), 0
+	}
+	if n, err := strconv.ParseFloat(c.Value, 64); err == nil {
+		return fmt.Sprintf("%s %s ?", column, c.Op), n
+	}
+	if c.Op == "!=" {
+		return fmt.Sprintf("LOWER(IFNULL(%s, '')) != LOWER(?)", column), c.Value
+	}
+	return fmt.Sprintf("LOWER(%s) %s LOWER(?)", column, c.Op), c.Value
+}
+
+// recordOrderSQL traduce un ordenamiento a una cláusula ORDER BY
+func recordOrderSQL(sort string) string {
+	switch sort {
+	case models.SortRating:
+		return " ORDER BY rating IS NULL, rating DESC, created_at DESC"
+	case models.SortArtist:
+		return " ORDER BY artista ASC, anio ASC, titulo ASC"
+	case models.SortTitle:
+		return " ORDER BY titulo ASC"
+	case models.SortYear:
+		return " ORDER BY anio IS NULL, anio ASC, artista ASC"
+	}
+	return " ORDER BY created_at DESC"
+}
+
+// List obtiene records aplicando búsqueda, filtros y orden
+func (r *RecordRepository) List(f models.RecordFilter, limit, offset int) ([]*models.Record, error) {
+	where, args := recordFilterSQL(f)
+	query := `SELECT ` + recordColumns + ` FROM records` + where + recordOrderSQL(f.Sort) + ` LIMIT ? OFFSET ?`
+
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("error listando records: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// CountList obtiene el total de records que cumplen un filtro
+func (r *RecordRepository) CountList(f models.RecordFilter) (int, error) {
+	where, args := recordFilterSQL(f)
+	query := `SELECT COUNT(*) FROM records` + where
+
+	var count int
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error contando records: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateCampos guarda los valores de los campos personalizados de un
+// record, siempre que siga en la versión indicada
+func (r *RecordRepository) UpdateCampos(id string, version int, campos sql.NullString) error {
+	query := `
+		UPDATE records SET campos = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ? AND version = ?
+	`
+
+	err := r.change(id, models.HistoryUpdate, version, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.Exec(query, campos, id, version)
+	})
+	if err != nil {
+		return fmt.Errorf("error guardando campos personalizados: %w", err)
+	}
+
+	log.Printf("✅ Campos personalizados actualizados: %s", id)
+	return nil
+}
+
+// UpdateRating guarda la calificación y reseña de un record y de sus
+// tracks, siempre que siga en la versión indicada
+func (r *RecordRepository) UpdateRating(id string, version int, rating sql.NullFloat64, review sql.NullString, tracklist sql.NullString) error {
+	query := `
+		UPDATE records SET rating = ?, review = ?, tracklist = ?, updated_at = CURRENT_TIMESTAMP,
+			version = version + 1
+		WHERE id = ? AND version = ?
+	`
+
+	err := r.change(id, models.HistoryUpdate, version, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.Exec(query, rating, review, tracklist, id, version)
+	})
+	if err != nil {
+		return fmt.Errorf("error guardando calificación: %w", err)
+	}
+
+	log.Printf("✅ Calificación actualizada: %s", id)
+	return nil
+}
+
+// UpdateLocation asigna la ubicación de un record, siempre que siga en la
+// versión indicada. Sin ubicación (NULL) el record queda sin asignar.
+func (r *RecordRepository) UpdateLocation(id string, version int, locationID sql.NullString) error {
+	query := `
+		UPDATE records SET location_id = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1
+		WHERE id = ? AND version = ? AND ` + activeRecord + `
+	`
+
+	err := r.change(id, models.HistoryUpdate, version, func(tx *sql.Tx) (sql.Result, error) {
+		return tx.Exec(query, locationID, id, version)
+	})
+	if err != nil {
+		return fmt.Errorf("error guardando ubicación: %w", err)
+	}
+
+	log.Printf("✅ Ubicación del record actualizada: %s", id)
+	return nil
+}
+
+// UpdatePalette guarda los colores extraídos del arte de un record. No queda
+// en el historial, ya que se derivan del arte, pero sube la versión para que
+// las ediciones hechas sobre los colores anteriores se detecten; updated_at
+// cambia por el trigger de records como en cualquier escritura.
+func (r *RecordRepository) UpdatePalette(id string, palette artwork.Palette) error {
+	result, err := r.db.Exec(
+		`UPDATE records SET color_dominante = ?, color_acento = ?, version = version + 1 WHERE id = ?`,
+		nullColor(palette.Dominant), nullColor(palette.Accent), id,
+	)
+	if err != nil {
+		return fmt.Errorf("error guardando paleta: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("record no encontrado: %s", id)
+	}
+	return nil
+}
+
+// nullColor convierte un color de la paleta en un valor para la base de
+// datos; los colores vacíos o inválidos se guardan como NULL
+func nullColor(color string) sql.NullString {
+	return sql.NullString{String: color, Valid: artwork.IsColor(color)}
+}
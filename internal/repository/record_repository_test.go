@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+func TestBulkSetField(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	a := newTestRecord(t, records, "Nirvana", "Nevermind")
+	b := newTestRecord(t, records, "John Coltrane", "Blue Train")
+
+	action := models.BulkAction{Accion: models.BulkSetField, Campo: "formato", Valor: "LP"}
+	result, err := records.WithActor("ana").Bulk([]string{a.ID, b.ID, "no-existe"}, nil, action)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(result.Changed) != 2 || len(result.Unchanged) != 0 || result.Missing != 1 {
+		t.Fatalf("resultado = %d cambiados, %d sin cambios, %d faltantes", len(result.Changed), len(result.Unchanged), result.Missing)
+	}
+
+	for _, record := range []*models.Record{a, b} {
+		got := reloadRecord(t, records, record.ID)
+		if got.Formato.String != "LP" || got.Version != record.Version+1 {
+			t.Errorf("record %s: formato = %q, versión = %d", record.Titulo, got.Formato.String, got.Version)
+		}
+		if actions := historyActions(t, db, record.ID); !slices.Equal(actions, []string{models.HistoryCreate, models.HistoryUpdate}) {
+			t.Errorf("historial de %s = %v", record.Titulo, actions)
+		}
+	}
+
+	// Repetir la acción no cambia nada ni agrega historial
+	result, err = records.Bulk([]string{a.ID, b.ID}, nil, action)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(result.Changed) != 0 || len(result.Unchanged) != 2 {
+		t.Errorf("al repetir: %d cambiados, %d sin cambios", len(result.Changed), len(result.Unchanged))
+	}
+	if actions := historyActions(t, db, a.ID); len(actions) != 2 {
+		t.Errorf("historial al repetir = %v", actions)
+	}
+}
+
+func TestBulkStaleVersionRollsBack(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	a := newTestRecord(t, records, "Nirvana", "Nevermind")
+	b := newTestRecord(t, records, "John Coltrane", "Blue Train")
+	versions := map[string]int{a.ID: a.Version, b.ID: b.Version}
+
+	// Otra persona edita b después de cargar el listado
+	if err := records.UpdateRating(b.ID, b.Version, sql.NullFloat64{Float64: 4, Valid: true}, sql.NullString{}, sql.NullString{}); err != nil {
+		t.Fatalf("error calificando: %v", err)
+	}
+
+	for _, action := range []models.BulkAction{
+		{Accion: models.BulkSetField, Campo: "formato", Valor: "LP"},
+		{Accion: models.BulkAddTag, Valor: "favoritos"},
+		{Accion: models.BulkDelete},
+	} {
+		t.Run(action.Accion, func(t *testing.T) {
+			_, err := records.Bulk([]string{a.ID, b.ID}, versions, action)
+			if !errors.Is(err, ErrConflict) {
+				t.Fatalf("error = %v, se esperaba ErrConflict", err)
+			}
+
+			// a se modificó dentro de la transacción antes de llegar a b
+			got := reloadRecord(t, records, a.ID)
+			if got.Version != a.Version || got.Formato.Valid || got.DeletedAt.Valid {
+				t.Errorf("a cambió: versión %d, formato %q, eliminado %v", got.Version, got.Formato.String, got.DeletedAt.Valid)
+			}
+			if actions := historyActions(t, db, a.ID); len(actions) != 1 {
+				t.Errorf("historial de a = %v, se esperaba solo la creación", actions)
+			}
+			tags, err := NewTagRepository(db).GetByRecord(a.ID)
+			if err != nil || len(tags) != 0 {
+				t.Errorf("tags de a = %v (%v)", tags, err)
+			}
+		})
+	}
+
+	// Con las versiones actuales la acción se aplica
+	b = reloadRecord(t, records, b.ID)
+	versions[b.ID] = b.Version
+	action := models.BulkAction{Accion: models.BulkSetField, Campo: "formato", Valor: "LP"}
+	if result, err := records.Bulk([]string{a.ID, b.ID}, versions, action); err != nil || len(result.Changed) != 2 {
+		t.Fatalf("con versiones actuales: resultado %+v, error %v", result, err)
+	}
+}
+
+func TestBulkTagIsIdempotent(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	tags := NewTagRepository(db)
+	a := newTestRecord(t, records, "Nirvana", "Nevermind")
+	b := newTestRecord(t, records, "John Coltrane", "Blue Train")
+	if err := tags.AddToRecord(a.ID, []string{"Favoritos"}); err != nil {
+		t.Fatalf("error agregando tag: %v", err)
+	}
+	ids := []string{a.ID, b.ID}
+
+	tests := []struct {
+		name      string
+		action    models.BulkAction
+		changed   []string
+		wantTags  int
+		wantCount int // versiones de a y b en el historial
+	}{
+		{name: "agregar a quien no lo tiene", action: models.BulkAction{Accion: models.BulkAddTag, Valor: "favoritos"}, changed: []string{b.ID}, wantTags: 1, wantCount: 4},
+		{name: "agregar de nuevo", action: models.BulkAction{Accion: models.BulkAddTag, Valor: "  FAVORITOS "}, wantTags: 1, wantCount: 4},
+		{name: "quitar", action: models.BulkAction{Accion: models.BulkRemoveTag, Valor: "favoritos"}, changed: ids, wantTags: 0, wantCount: 6},
+		{name: "quitar de nuevo", action: models.BulkAction{Accion: models.BulkRemoveTag, Valor: "favoritos"}, wantTags: 0, wantCount: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := records.Bulk(ids, nil, tt.action)
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			var changed []string
+			for _, item := range result.Changed {
+				changed = append(changed, item.Record.ID)
+			}
+			if !slices.Equal(changed, tt.changed) || len(result.Unchanged) != len(ids)-len(tt.changed) {
+				t.Errorf("cambiados = %v, se esperaba %v", changed, tt.changed)
+			}
+
+			for _, id := range ids {
+				got, err := tags.GetByRecord(id)
+				if err != nil || len(got) != tt.wantTags {
+					t.Errorf("tags de %s = %d (%v), se esperaban %d", id, len(got), err, tt.wantTags)
+				}
+			}
+			// Solo los records que cambiaron suman una versión al historial
+			if total := len(historyActions(t, db, a.ID)) + len(historyActions(t, db, b.ID)); total != tt.wantCount {
+				t.Errorf("versiones en el historial = %d, se esperaban %d", total, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestUpdateConflict(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	record := newTestRecord(t, records, "Nirvana", "Nevermind")
+
+	// Dos personas abren el mismo record en la versión 1
+	ana := reloadRecord(t, records, record.ID)
+	beto := reloadRecord(t, records, record.ID)
+
+	ana.Notas = sql.NullString{String: "Primera edición", Valid: true}
+	if err := records.WithActor("ana").Update(ana); err != nil {
+		t.Fatalf("error guardando: %v", err)
+	}
+	if ana.Version != 2 {
+		t.Errorf("versión tras guardar = %d, se esperaba 2", ana.Version)
+	}
+
+	beto.Notas = sql.NullString{String: "Reedición", Valid: true}
+	if err := records.WithActor("beto").Update(beto); !errors.Is(err, ErrConflict) {
+		t.Fatalf("error = %v, se esperaba ErrConflict", err)
+	}
+
+	got := reloadRecord(t, records, record.ID)
+	if got.Notas.String != "Primera edición" || got.Version != 2 {
+		t.Errorf("record = notas %q, versión %d", got.Notas.String, got.Version)
+	}
+	if actions := historyActions(t, db, record.ID); len(actions) != 2 {
+		t.Errorf("historial = %v, la edición rechazada no debe quedar", actions)
+	}
+}
+
+func TestTrashCycle(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	record := newTestRecord(t, records, "Nirvana", "Nevermind")
+	if err := NewTagRepository(db).AddToRecord(record.ID, []string{"grunge"}); err != nil {
+		t.Fatalf("error agregando tag: %v", err)
+	}
+	play := models.NewPlay()
+	play.RecordID = record.ID
+	if err := NewPlayRepository(db).Create(play); err != nil {
+		t.Fatalf("error registrando escucha: %v", err)
+	}
+
+	// Purgar solo aplica a los records de la papelera
+	if err := records.Purge(record.ID); err == nil {
+		t.Fatal("se purgó un record que no estaba en la papelera")
+	}
+
+	if err := records.WithActor("ana").Delete(record.ID); err != nil {
+		t.Fatalf("error enviando a la papelera: %v", err)
+	}
+	if _, err := records.GetByID(record.ID); err == nil {
+		t.Error("el record en la papelera sigue en la colección")
+	}
+	if total, err := records.CountDeleted(); err != nil || total != 1 {
+		t.Errorf("papelera = %d (%v), se esperaba 1", total, err)
+	}
+	if err := records.Delete(record.ID); err == nil {
+		t.Error("se envió dos veces a la papelera")
+	}
+
+	if err := records.WithActor("ana").Restore(record.ID); err != nil {
+		t.Fatalf("error restaurando: %v", err)
+	}
+	if _, err := records.GetByID(record.ID); err != nil {
+		t.Errorf("el record restaurado no está en la colección: %v", err)
+	}
+	if err := records.Restore(record.ID); err == nil {
+		t.Error("se restauró un record que no estaba en la papelera")
+	}
+
+	if err := records.WithActor("ana").Delete(record.ID); err != nil {
+		t.Fatalf("error enviando a la papelera: %v", err)
+	}
+	if err := records.WithActor("beto").Purge(record.ID); err != nil {
+		t.Fatalf("error purgando: %v", err)
+	}
+	if _, err := records.GetByIDWithDeleted(record.ID); err == nil {
+		t.Error("el record purgado sigue en la base de datos")
+	}
+	for _, table := range []string{"plays", "record_tags"} {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE record_id = ?`, record.ID).Scan(&n); err != nil || n != 0 {
+			t.Errorf("%s del record purgado = %d (%v)", table, n, err)
+		}
+	}
+
+	// El historial se conserva y termina con la purga
+	want := []string{models.HistoryCreate, models.HistoryUpdate, models.HistoryDelete, models.HistoryRestore, models.HistoryDelete, models.HistoryPurge}
+	if actions := historyActions(t, db, record.ID); !slices.Equal(actions, want) {
+		t.Errorf("historial = %v, se esperaba %v", actions, want)
+	}
+	versions, err := NewRecordVersionRepository(db).GetByRecord(record.ID)
+	if err != nil || versions[0].Actor != "beto" {
+		t.Errorf("la purga no quedó a nombre de quien la hizo: %v", err)
+	}
+
+	if err := records.Purge(record.ID); err == nil {
+		t.Error("se purgó dos veces")
+	}
+}
+
+func TestPurgeDeletedBefore(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	old := newTestRecord(t, records, "Nirvana", "Bleach")
+	recent := newTestRecord(t, records, "Nirvana", "Nevermind")
+	active := newTestRecord(t, records, "Nirvana", "In Utero")
+	for _, record := range []*models.Record{old, recent} {
+		if err := records.Delete(record.ID); err != nil {
+			t.Fatalf("error enviando a la papelera: %v", err)
+		}
+	}
+	_, err := db.Exec(`UPDATE records SET deleted_at = ? WHERE id = ?`, time.Now().AddDate(0, 0, -40).UTC().Format(time.DateTime), old.ID)
+	if err != nil {
+		t.Fatalf("error fechando la papelera: %v", err)
+	}
+
+	purged, err := records.PurgeDeletedBefore(time.Now().AddDate(0, 0, -30))
+	if err != nil || purged != 1 {
+		t.Fatalf("purgados = %d (%v), se esperaba 1", purged, err)
+	}
+	if _, err := records.GetByIDWithDeleted(old.ID); err == nil {
+		t.Error("no se purgó el record con más de 30 días en la papelera")
+	}
+	if got := reloadRecord(t, records, recent.ID); !got.DeletedAt.Valid {
+		t.Error("el record reciente salió de la papelera")
+	}
+	reloadRecord(t, records, active.ID)
+
+	if actions := historyActions(t, db, old.ID); actions[len(actions)-1] != models.HistoryPurge {
+		t.Errorf("historial del purgado = %v", actions)
+	}
+	if versions, _ := NewRecordVersionRepository(db).GetByRecord(old.ID); versions[0].Actor != models.SystemActor {
+		t.Errorf("la purga automática quedó a nombre de %q", versions[0].Actor)
+	}
+}
+
+func TestCustomFieldConditionSQL(t *testing.T) {
+	const column = "json_extract(records.campos, '$.firmado')"
+	tests := []struct {
+		condition models.FilterCondition
+		wantSQL   string
+		wantArg   any
+	}{
+		{models.FilterCondition{Field: "campos.firmado", Op: "=", Value: "true"}, "IFNULL(" + column + ", 0) = ?", 1},
+		{models.FilterCondition{Field: "campos.firmado", Op: "!=", Value: "FALSE"}, "IFNULL(" + column + ", 0) != ?", 0},
+		{models.FilterCondition{Field: "campos.firmado", Op: "~", Value: "True"}, "LOWER(" + column + ") LIKE ?", "%true%"},
+		{models.FilterCondition{Field: "campos.firmado", Op: ">=", Value: "3"}, column + " >= ?", 3.0},
+		{models.FilterCondition{Field: "campos.firmado", Op: "!=", Value: "Tapa"}, "LOWER(IFNULL(" + column + ", '')) != LOWER(?)", "Tapa"},
+		{models.FilterCondition{Field: "campos.firmado", Op: "<", Value: "2000-01-01"}, "LOWER(" + column + ") < LOWER(?)", "2000-01-01"},
+	}
+
+	for _, tt := range tests {
+		gotSQL, gotArg := customFieldConditionSQL(tt.condition)
+		if gotSQL != tt.wantSQL || gotArg != tt.wantArg {
+			t.Errorf("%s = %s [%v], se esperaba %s [%v]", tt.condition, gotSQL, gotArg, tt.wantSQL, tt.wantArg)
+		}
+	}
+}
+
+func TestListFilterExpression(t *testing.T) {
+	db := newTestDB(t)
+	records := NewRecordRepository(db)
+	for _, item := range []struct {
+		titulo string
+		anio   int32
+		campos map[string]any
+	}{
+		{"Bleach", 1989, map[string]any{"firmado": true, "copias": 2.0, "prensaje": "Sub Pop"}},
+		{"Nevermind", 1991, map[string]any{"firmado": false, "copias": 1.0, "comprado": "2001-05-04"}},
+		{"In Utero", 1993, nil},
+	} {
+		record := models.NewRecord()
+		record.Artista = "Nirvana"
+		record.Titulo = item.titulo
+		record.Anio = sql.NullInt32{Int32: item.anio, Valid: true}
+		record.SetCampos(item.campos)
+		if err := records.Create(record); err != nil {
+			t.Fatalf("error creando record: %v", err)
+		}
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"campos.firmado=true", []string{"Bleach"}},
+		{"campos.firmado=false", []string{"In Utero", "Nevermind"}},
+		{"campos.firmado!=true", []string{"In Utero", "Nevermind"}},
+		{"campos.firmado~tru", nil},
+		{"campos.copias>=2", []string{"Bleach"}},
+		{"campos.copias<2", []string{"Nevermind"}},
+		{`campos.prensaje="sub pop"`, []string{"Bleach"}},
+		{"campos.prensaje~pop", []string{"Bleach"}},
+		{"campos.prensaje!=Sub", []string{"Bleach", "In Utero", "Nevermind"}},
+		{"campos.comprado<2010-01-01", []string{"Nevermind"}},
+		{"campos.inexistente=1", nil},
+		{"anio>1989 and campos.firmado!=true", []string{"In Utero", "Nevermind"}},
+	}
+
+	for _, tt := range tests {
+		conditions, err := models.ParseFilterExpression(tt.expr)
+		if err != nil {
+			t.Fatalf("expresión %q inválida: %v", tt.expr, err)
+		}
+		list, err := records.List(models.RecordFilter{Conditions: conditions, Sort: models.SortTitle}, 10, 0)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		var got []string
+		for _, record := range list {
+			got = append(got, record.Titulo)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, se esperaba %v", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// RecordVersionRepository maneja las consultas del historial de cambios de
+// los records. Las versiones se registran desde las escrituras de records,
+// dentro de la misma transacción (ver saveVersion).
+type RecordVersionRepository struct {
+	db *database.DB
+}
+
+// NewRecordVersionRepository crea un nuevo repositorio de historial
+func NewRecordVersionRepository(db *database.DB) *RecordVersionRepository {
+	return &RecordVersionRepository{db: db}
+}
+
+// recordVersionColumns lista las columnas de record_versions en el orden que
+// espera scanRecordVersion
+const recordVersionColumns = `id, record_id, numero, accion, actor, cambios, snapshot, created_at`
+
+// scanRecordVersion lee una fila de record_versions en un modelo
+func scanRecordVersion(s rowScanner) (*models.RecordVersion, error) {
+	var version models.RecordVersion
+	err := s.Scan(
+		&version.ID,
+		&version.RecordID,
+		&version.Numero,
+		&version.Accion,
+		&version.Actor,
+		&version.Cambios,
+		&version.Snapshot,
+		&version.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// scanRecordVersions recorre un conjunto de filas de record_versions
+func scanRecordVersions(rows *sql.Rows) ([]*models.RecordVersion, error) {
+	var versions []*models.RecordVersion
+	for rows.Next() {
+		version, err := scanRecordVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando versión: %w", err)
+		}
+		versions = append(versions, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo historial: %w", err)
+	}
+	return versions, nil
+}
+
+// GetByRecord obtiene el historial de un record, de la versión más reciente
+// a la más antigua
+func (r *RecordVersionRepository) GetByRecord(recordID string) ([]*models.RecordVersion, error) {
+	rows, err := r.db.Query(
+		`SELECT `+recordVersionColumns+` FROM record_versions WHERE record_id = ? ORDER BY numero DESC`,
+		recordID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo historial: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecordVersions(rows)
+}
+
+// GetSince obtiene las versiones del historial que dejaron al record en una
+// versión posterior a la indicada, de la más antigua a la más reciente. Son
+// los cambios que otra persona guardó mientras se editaba esa versión.
+func (r *RecordVersionRepository) GetSince(recordID string, version int) ([]*models.RecordVersion, error) {
+	rows, err := r.db.Query(`
+		SELECT `+recordVersionColumns+` FROM record_versions
+		WHERE record_id = ? AND json_extract(snapshot, '$.version') > ?
+		ORDER BY numero ASC
+	`, recordID, version)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo historial: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecordVersions(rows)
+}
+
+// GetByID obtiene una versión del historial de un record
+func (r *RecordVersionRepository) GetByID(recordID, id string) (*models.RecordVersion, error) {
+	version, err := scanRecordVersion(r.db.QueryRow(
+		`SELECT `+recordVersionColumns+` FROM record_versions WHERE id = ? AND record_id = ?`,
+		id, recordID,
+	))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("versión no encontrada: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo versión: %w", err)
+	}
+	return version, nil
+}
+
+// loadRecord obtiene un record dentro de una transacción, esté o no en la
+// papelera
+func loadRecord(tx *sql.Tx, id string) (*models.Record, error) {
+	record, err := scanRecord(tx.QueryRow(`SELECT `+recordColumns+` FROM records WHERE id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("error obteniendo record: %w", err)
+	}
+	return record, nil
+}
+
+// loadRecordsWhere obtiene dentro de una transacción los records que cumplen
+// la condición, estén o no en la papelera
+func loadRecordsWhere(tx *sql.Tx, where string, args ...any) ([]*models.Record, error) {
+	rows, err := tx.Query(`SELECT `+recordColumns+` FROM records WHERE `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo records: %w", err)
+	}
+	defer rows.Close()
+
+	return scanRecords(rows)
+}
+
+// saveVersions registra en el historial el estado de varios records después
+// de un mismo cambio (ver saveVersion)
+func saveVersions(tx *sql.Tx, accion, actor string, before []*models.Record) error {
+	for _, record := range before {
+		if err := saveVersion(tx, record.ID, accion, actor, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveVersion registra en el historial el estado de un record después de un
+// cambio, con los campos que cambiaron respecto de before (nil si el record
+// es nuevo) más los cambios extra que no son campos del record, como los
+// tags. Las ediciones que no cambian nada, como guardar un formulario sin
+// modificarlo, no se registran.
+func saveVersion(tx *sql.Tx, recordID, accion, actor string, before *models.Record, extra ...models.FieldChange) error {
+	after, err := loadRecord(tx, recordID)
+	if err != nil {
+		return err
+	}
+
+	changes := append(models.DiffHistory(before, after), extra...)
+	if len(changes) == 0 && (accion == models.HistoryUpdate || accion == models.HistoryMerge) {
+		return nil
+	}
+
+	if actor == "" {
+		actor = models.SystemActor
+	}
+	version, err := models.NewRecordVersion(after, accion, actor, changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO record_versions (`+recordVersionColumns+`)
+		SELECT ?, ?, COALESCE(MAX(numero), 0) + 1, ?, ?, ?, ?, ?
+		FROM record_versions WHERE record_id = ?
+	`,
+		version.ID,
+		version.RecordID,
+		version.Accion,
+		version.Actor,
+		version.Cambios,
+		version.Snapshot,
+		version.CreatedAt.UTC(),
+		version.RecordID,
+	)
+	if err != nil {
+		return fmt.Errorf("error guardando historial: %w", err)
+	}
+	return nil
+}
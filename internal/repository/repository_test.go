@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// newTestDB crea una base de datos en memoria con todas las migraciones
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("error abriendo BD: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob("../../migrations/*.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no se encontraron migraciones: %v", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("error leyendo %s: %v", file, err)
+		}
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("error aplicando %s: %v", filepath.Base(file), err)
+		}
+	}
+	return db
+}
+
+// newTestRecord crea un record con el artista y título indicados
+func newTestRecord(t *testing.T, records *RecordRepository, artista, titulo string) *models.Record {
+	t.Helper()
+	record := models.NewRecord()
+	record.Artista = artista
+	record.Titulo = titulo
+	if err := records.Create(record); err != nil {
+		t.Fatalf("error creando record: %v", err)
+	}
+	return record
+}
+
+// reloadRecord obtiene el record tal como está en la base de datos, esté o
+// no en la papelera
+func reloadRecord(t *testing.T, records *RecordRepository, id string) *models.Record {
+	t.Helper()
+	record, err := records.GetByIDWithDeleted(id)
+	if err != nil {
+		t.Fatalf("error obteniendo record %s: %v", id, err)
+	}
+	return record
+}
+
+// historyActions retorna las acciones del historial de un record, de la más
+// antigua a la más reciente
+func historyActions(t *testing.T, db *database.DB, recordID string) []string {
+	t.Helper()
+	versions, err := NewRecordVersionRepository(db).GetByRecord(recordID)
+	if err != nil {
+		t.Fatalf("error obteniendo historial: %v", err)
+	}
+	actions := make([]string, len(versions))
+	for i, version := range versions {
+		actions[len(versions)-1-i] = version.Accion
+	}
+	return actions
+}
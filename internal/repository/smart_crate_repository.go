@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// SmartCrateRepository maneja las operaciones de base de datos para crates inteligentes
+type SmartCrateRepository struct {
+	db *database.DB
+}
+
+// NewSmartCrateRepository crea un nuevo repositorio de crates inteligentes
+func NewSmartCrateRepository(db *database.DB) *SmartCrateRepository {
+	return &SmartCrateRepository{db: db}
+}
+
+// smartCrateColumns lista las columnas de smart_crates en el orden que espera scanSmartCrate
+const smartCrateColumns = `id, nombre, descripcion, filtro, created_at, updated_at`
+
+// scanSmartCrate lee una fila de smart_crates en un modelo
+func scanSmartCrate(s rowScanner) (*models.SmartCrate, error) {
+	var crate models.SmartCrate
+	err := s.Scan(
+		&crate.ID,
+		&crate.Nombre,
+		&crate.Descripcion,
+		&crate.Filtro,
+		&crate.CreatedAt,
+		&crate.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &crate, nil
+}
+
+// Create guarda un nuevo crate inteligente. El filtro debe ser una expresión válida.
+func (r *SmartCrateRepository) Create(crate *models.SmartCrate) error {
+	if _, err := models.ParseFilterExpression(crate.Filtro); err != nil {
+		return fmt.Errorf("filtro inválido: %w", err)
+	}
+
+	query := `
+		INSERT INTO smart_crates (
+			id, nombre, descripcion, filtro, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		crate.ID,
+		crate.Nombre,
+		crate.Descripcion,
+		crate.Filtro,
+		crate.CreatedAt,
+		crate.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error creando crate: %w", err)
+	}
+
+	log.Printf("✅ Crate creado: %s (%s)", crate.Nombre, crate.Filtro)
+	return nil
+}
+
+// GetByID obtiene un crate inteligente por su ID
+func (r *SmartCrateRepository) GetByID(id string) (*models.SmartCrate, error) {
+	query := `SELECT ` + smartCrateColumns + ` FROM smart_crates WHERE id = ?`
+
+	crate, err := scanSmartCrate(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("crate no encontrado: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo crate: %w", err)
+	}
+
+	return crate, nil
+}
+
+// GetAll obtiene todos los crates inteligentes ordenados por nombre
+func (r *SmartCrateRepository) GetAll() ([]*models.SmartCrate, error) {
+	rows, err := r.db.Query(`SELECT ` + smartCrateColumns + ` FROM smart_crates ORDER BY nombre ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo crates: %w", err)
+	}
+	defer rows.Close()
+
+	var crates []*models.SmartCrate
+	for rows.Next() {
+		crate, err := scanSmartCrate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando crate: %w", err)
+		}
+		crates = append(crates, crate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo crates: %w", err)
+	}
+
+	return crates, nil
+}
+
+// Delete elimina un crate inteligente. Los records no se ven afectados.
+func (r *SmartCrateRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM smart_crates WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error eliminando crate: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("crate no encontrado: %s", id)
+	}
+
+	log.Printf("✅ Crate eliminado: %s", id)
+	return nil
+}
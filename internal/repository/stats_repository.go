@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// StatsRepository calcula estadísticas agregadas de la colección
+type StatsRepository struct {
+	db *database.DB
+}
+
+// NewStatsRepository crea un nuevo repositorio de estadísticas
+func NewStatsRepository(db *database.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// queryRatingStats ejecuta una consulta que retorna filas (nombre, promedio, cantidad)
+func (r *StatsRepository) queryRatingStats(query string, limit int) ([]models.RatingStat, error) {
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error calculando calificaciones: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.RatingStat
+	for rows.Next() {
+		var stat models.RatingStat
+		if err := rows.Scan(&stat.Nombre, &stat.Promedio, &stat.Cantidad); err != nil {
+			return nil, fmt.Errorf("error escaneando calificaciones: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo calificaciones: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RatingsByArtist obtiene la calificación promedio por artista
+func (r *StatsRepository) RatingsByArtist(limit int) ([]models.RatingStat, error) {
+	return r.queryRatingStats(`
+		SELECT artista, AVG(rating), COUNT(*) FROM records
+		WHERE ` + activeRecord + ` AND rating IS NOT NULL
+		GROUP BY artista
+		ORDER BY AVG(rating) DESC, COUNT(*) DESC
+		LIMIT ?
+	`, limit)
+}
+
+// RatingsByLabel obtiene la calificación promedio por sello
+func (r *StatsRepository) RatingsByLabel(limit int) ([]models.RatingStat, error) {
+	return r.queryRatingStats(`
+		SELECT sello, AVG(rating), COUNT(*) FROM records
+		WHERE ` + activeRecord + ` AND rating IS NOT NULL AND sello IS NOT NULL AND sello != ''
+		GROUP BY sello
+		ORDER BY AVG(rating) DESC, COUNT(*) DESC
+		LIMIT ?
+	`, limit)
+}
+
+// RatingsByGenre obtiene la calificación promedio por género. Un record
+// con varios géneros cuenta en cada uno de ellos.
+func (r *StatsRepository) RatingsByGenre(limit int) ([]models.RatingStat, error) {
+	return r.queryRatingStats(`
+		SELECT g.value, AVG(records.rating), COUNT(*)
+		FROM records, json_each(
+			CASE WHEN json_valid(records.generos) THEN records.generos ELSE '[]' END
+		) AS g
+		WHERE ` + activeRecord + ` AND records.rating IS NOT NULL
+		GROUP BY g.value
+		ORDER BY AVG(records.rating) DESC, COUNT(*) DESC
+		LIMIT ?
+	`, limit)
+}
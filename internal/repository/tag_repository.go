@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// TagRepository maneja las operaciones de base de datos para tags
+type TagRepository struct {
+	db    *database.DB
+	actor string
+}
+
+// NewTagRepository crea un nuevo repositorio de tags
+func NewTagRepository(db *database.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// WithActor retorna una copia del repositorio que registra los cambios de
+// tags en el historial de los records a nombre del actor indicado
+func (r *TagRepository) WithActor(actor string) *TagRepository {
+	return &TagRepository{db: r.db, actor: actor}
+}
+
+// tagColumns lista las columnas de tags en el orden que espera scanTag
+const tagColumns = `tags.id, tags.nombre, tags.created_at,
+	(
+		SELECT COUNT(*) FROM record_tags rt
+		JOIN records ON records.id = rt.record_id AND ` + activeRecord + `
+		WHERE rt.tag_id = tags.id
+	) AS record_count`
+
+// scanTag lee una fila de tags en un modelo
+func scanTag(s rowScanner) (*models.Tag, error) {
+	var tag models.Tag
+	if err := s.Scan(&tag.ID, &tag.Nombre, &tag.CreatedAt, &tag.RecordCount); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// scanTags recorre un conjunto de filas de tags
+func scanTags(rows *sql.Rows) ([]*models.Tag, error) {
+	var tags []*models.Tag
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// GetAll obtiene todos los tags con la cantidad de records de cada uno
+func (r *TagRepository) GetAll() ([]*models.Tag, error) {
+	rows, err := r.db.Query(`SELECT ` + tagColumns + ` FROM tags ORDER BY nombre ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo tags: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+// GetByRecord obtiene los tags asignados a un record
+func (r *TagRepository) GetByRecord(recordID string) ([]*models.Tag, error) {
+	query := `
+		SELECT ` + tagColumns + ` FROM tags
+		JOIN record_tags ON record_tags.tag_id = tags.id
+		WHERE record_tags.record_id = ?
+		ORDER BY tags.nombre ASC
+	`
+
+	rows, err := r.db.Query(query, recordID)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo tags del record: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTags(rows)
+}
+
+// assignTag asigna un tag a un record usando la conexión o transacción
+// indicada, creándolo si no existe
+func assignTag(ex execer, recordID string, tag *models.Tag) error {
+	if _, err := ex.Exec(
+		`INSERT INTO tags (id, nombre, created_at) VALUES (?, ?, ?) ON CONFLICT(nombre) DO NOTHING`,
+		tag.ID, tag.Nombre, tag.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("error creando tag: %w", err)
+	}
+
+	if _, err := ex.Exec(`
+		INSERT OR IGNORE INTO record_tags (record_id, tag_id)
+		SELECT ?, id FROM tags WHERE nombre = ?`,
+		recordID, tag.Nombre,
+	); err != nil {
+		return fmt.Errorf("error asignando tag: %w", err)
+	}
+	return nil
+}
+
+// AddToRecord asigna tags a un record, creando los que no existan, y
+// registra el cambio en su historial
+func (r *TagRepository) AddToRecord(recordID string, nombres []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := loadRecord(tx, recordID)
+	if err != nil {
+		return err
+	}
+	antes, err := recordTagNames(tx, recordID)
+	if err != nil {
+		return err
+	}
+
+	for _, nombre := range nombres {
+		tag := models.NewTag(nombre)
+		if tag.Nombre == "" {
+			continue
+		}
+
+		if err := assignTag(tx, recordID, tag); err != nil {
+			return err
+		}
+	}
+
+	if _, err := saveTagChange(tx, before, antes, r.actor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando tags: %w", err)
+	}
+
+	log.Printf("✅ Tags asignados a %s: %v", recordID, nombres)
+	return nil
+}
+
+// RemoveFromRecord quita un tag de un record y registra el cambio en su
+// historial
+func (r *TagRepository) RemoveFromRecord(recordID, tagID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := loadRecord(tx, recordID)
+	if err != nil {
+		return err
+	}
+	antes, err := recordTagNames(tx, recordID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM record_tags WHERE record_id = ? AND tag_id = ?`, recordID, tagID)
+	if err != nil {
+		return fmt.Errorf("error quitando tag: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return fmt.Errorf("tag no asignado al record: %s", tagID)
+	}
+
+	if _, err := saveTagChange(tx, before, antes, r.actor); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando tags: %w", err)
+	}
+
+	log.Printf("✅ Tag %s quitado de %s", tagID, recordID)
+	return nil
+}
+
+// saveTagChange compara los tags actuales del record con antes y, si
+// cambiaron, sube la versión del record y registra el cambio en su
+// historial. Retorna el cambio, o nada si los tags quedaron iguales.
+func saveTagChange(tx *sql.Tx, before *models.Record, antes, actor string) ([]models.FieldChange, error) {
+	despues, err := recordTagNames(tx, before.ID)
+	if err != nil || antes == despues {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE records SET version = version + 1 WHERE id = ?`, before.ID); err != nil {
+		return nil, fmt.Errorf("error actualizando versión del record: %w", err)
+	}
+
+	changes := []models.FieldChange{{Campo: "tags", Antes: antes, Despues: despues}}
+	if err := saveVersion(tx, before.ID, models.HistoryUpdate, actor, before, changes...); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// recordTagNames retorna los tags de un record separados por coma
+func recordTagNames(tx *sql.Tx, recordID string) (string, error) {
+	rows, err := tx.Query(`
+		SELECT tags.nombre FROM tags
+		JOIN record_tags ON record_tags.tag_id = tags.id
+		WHERE record_tags.record_id = ?
+		ORDER BY tags.nombre`,
+		recordID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("error obteniendo tags: %w", err)
+	}
+	defer rows.Close()
+
+	var nombres []string
+	for rows.Next() {
+		var nombre string
+		if err := rows.Scan(&nombre); err != nil {
+			return "", fmt.Errorf("error escaneando tag: %w", err)
+		}
+		nombres = append(nombres, nombre)
+	}
+	return strings.Join(nombres, ", "), rows.Err()
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/rodrwan/vinilo/internal/database"
+	"github.com/rodrwan/vinilo/internal/models"
+)
+
+// WantlistRepository maneja las operaciones de base de datos para la wantlist
+type WantlistRepository struct {
+	db    *database.DB
+	actor string
+}
+
+// NewWantlistRepository crea un nuevo repositorio de wantlist
+func NewWantlistRepository(db *database.DB) *WantlistRepository {
+	return &WantlistRepository{db: db}
+}
+
+// WithActor retorna una copia del repositorio que registra los records
+// comprados desde la wantlist en el historial a nombre del actor indicado
+func (r *WantlistRepository) WithActor(actor string) *WantlistRepository {
+	return &WantlistRepository{db: r.db, actor: actor}
+}
+
+// wantlistColumns lista las columnas de wantlist en el orden que espera scanWantlistItem
+const wantlistColumns = `
+	id, titulo, artista, sello, catalog_number, anio, formato,
+	generos, estilos, pais, arte_url, notas, precio_maximo,
+	prensaje_preferido, prioridad, created_at, updated_at`
+
+// scanWantlistItem lee una fila de wantlist en un modelo
+func scanWantlistItem(s rowScanner) (*models.WantlistItem, error) {
+	var item models.WantlistItem
+	err := s.Scan(
+		&item.ID,
+		&item.Titulo,
+		&item.Artista,
+		&item.Sello,
+		&item.CatalogNumber,
+		&item.Anio,
+		&item.Formato,
+		&item.Generos,
+		&item.Estilos,
+		&item.Pais,
+		&item.ArteURL,
+		&item.Notas,
+		&item.PrecioMaximo,
+		&item.PrensajePreferido,
+		&item.Prioridad,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create agrega una nueva entrada a la wantlist
+func (r *WantlistRepository) Create(item *models.WantlistItem) error {
+	query := `
+		INSERT INTO wantlist (
+			id, titulo, artista, sello, catalog_number, anio, formato,
+			generos, estilos, pais, arte_url, notas, precio_maximo,
+			prensaje_preferido, prioridad, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		item.ID,
+		item.Titulo,
+		item.Artista,
+		item.Sello,
+		item.CatalogNumber,
+		item.Anio,
+		item.Formato,
+		item.Generos,
+		item.Estilos,
+		item.Pais,
+		item.ArteURL,
+		item.Notas,
+		item.PrecioMaximo,
+		item.PrensajePreferido,
+		item.Prioridad,
+		item.CreatedAt,
+		item.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("error creando entrada de wantlist: %w", err)
+	}
+
+	log.Printf("✅ Agregado a la wantlist: %s - %s", item.Artista, item.Titulo)
+	return nil
+}
+
+// GetByID obtiene una entrada de la wantlist por su ID
+func (r *WantlistRepository) GetByID(id string) (*models.WantlistItem, error) {
+	query := `SELECT ` + wantlistColumns + ` FROM wantlist WHERE id = ?`
+
+	item, err := scanWantlistItem(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("entrada de wantlist no encontrada: %s", id)
+		}
+		return nil, fmt.Errorf("error obteniendo entrada de wantlist: %w", err)
+	}
+
+	return item, nil
+}
+
+// GetAll obtiene todas las entradas ordenadas por prioridad
+func (r *WantlistRepository) GetAll() ([]*models.WantlistItem, error) {
+	query := `
+		SELECT ` + wantlistColumns + ` FROM wantlist
+		ORDER BY prioridad ASC, artista ASC, titulo ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error obteniendo wantlist: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.WantlistItem
+	for rows.Next() {
+		item, err := scanWantlistItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error escaneando entrada de wantlist: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error recorriendo wantlist: %w", err)
+	}
+
+	return items, nil
+}
+
+// Count obtiene el total de entradas de la wantlist
+func (r *WantlistRepository) Count() (int, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM wantlist`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error contando wantlist: %w", err)
+	}
+	return count, nil
+}
+
+// Delete elimina una entrada de la wantlist
+func (r *WantlistRepository) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM wantlist WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error eliminando entrada de wantlist: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error obteniendo filas afectadas: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("entrada de wantlist no encontrada: %s", id)
+	}
+
+	log.Printf("✅ Entrada de wantlist eliminada: %s", id)
+	return nil
+}
+
+// Purchase convierte una entrada de la wantlist en un record de la
+// colección: crea el record y elimina la entrada en una sola transacción
+func (r *WantlistRepository) Purchase(item *models.WantlistItem) (*models.Record, error) {
+	record := item.ToRecord()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error iniciando transacción: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertRecord(tx, record); err != nil {
+		return nil, err
+	}
+	if err := saveVersion(tx, record.ID, models.HistoryCreate, r.actor, nil); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`DELETE FROM wantlist WHERE id = ?`, item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error eliminando entrada de wantlist: %w", err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return nil, fmt.Errorf("entrada de wantlist no encontrada: %s", item.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error confirmando compra: %w", err)
+	}
+
+	log.Printf("✅ Comprado desde la wantlist: %s - %s", record.Artista, record.Titulo)
+	return record, nil
+}
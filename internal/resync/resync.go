@@ -0,0 +1,351 @@
+// Package resync vuelve a consultar periódicamente a los proveedores de
+// metadatos por los records que tienen un identificador externo. Los
+// valores de mercado se actualizan directamente; los cambios de metadatos
+// quedan pendientes de revisión en el admin para no sobrescribir las
+// ediciones manuales.
+package resync
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/artwork"
+	"github.com/rodrwan/vinilo/internal/metadata"
+	"github.com/rodrwan/vinilo/internal/models"
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// ErrRunning indica que ya hay una sincronización en curso
+var ErrRunning = errors.New("ya hay una sincronización de metadatos en curso")
+
+// batchSize es la cantidad de records que se leen por consulta
+const batchSize = 50
+
+// Result resume una ejecución de la sincronización
+type Result struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Synced     int // records consultados con éxito
+	Changes    int // cambios propuestos
+	Errors     int // records cuyo proveedor falló
+}
+
+// Syncer re-sincroniza los metadatos de los records con el proveedor que
+// corresponde a su identificador externo (Discogs o MusicBrainz)
+type Syncer struct {
+	records   *repository.RecordRepository
+	syncs     *repository.MetadataSyncRepository
+	providers []metadata.Provider
+	interval  time.Duration
+
+	mu      sync.Mutex
+	running bool
+	last    *Result
+}
+
+// New crea un sincronizador que vuelve a consultar cada record una vez
+// por intervalo
+func New(records *repository.RecordRepository, syncs *repository.MetadataSyncRepository, providers []metadata.Provider, interval time.Duration) *Syncer {
+	return &Syncer{
+		records:   records,
+		syncs:     syncs,
+		providers: providers,
+		interval:  interval,
+	}
+}
+
+// Interval retorna cada cuánto se re-sincroniza un record
+func (s *Syncer) Interval() time.Duration {
+	return s.interval
+}
+
+// Running indica si hay una sincronización en curso
+func (s *Syncer) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// LastResult retorna el resumen de la última sincronización terminada, o
+// nil si aún no se ejecuta ninguna
+func (s *Syncer) LastResult() *Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Start ejecuta la sincronización al iniciar y luego una vez por
+// intervalo, hasta que se cancele el contexto
+func (s *Syncer) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Run(ctx); err != nil && !errors.Is(err, ErrRunning) && ctx.Err() == nil {
+			log.Printf("❌ Error sincronizando metadatos: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run sincroniza los records que nunca se sincronizaron o cuya última
+// sincronización es anterior al intervalo
+func (s *Syncer) Run(ctx context.Context) (Result, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return Result{}, ErrRunning
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	result := Result{StartedAt: time.Now()}
+	defer func() {
+		result.FinishedAt = time.Now()
+		s.mu.Lock()
+		s.running = false
+		s.last = &result
+		s.mu.Unlock()
+	}()
+
+	before := result.StartedAt.Add(-s.interval)
+	for _, provider := range s.providers {
+		// Los proveedores se asocian a los records por el tipo de
+		// identificador, que coincide con su nombre
+		if !isIdentifierType(provider.Name()) {
+			continue
+		}
+
+		for {
+			identifiers, err := s.syncs.GetDue(provider.Name(), before, batchSize)
+			if err != nil {
+				return result, err
+			}
+			if len(identifiers) == 0 {
+				break
+			}
+
+			for _, identifier := range identifiers {
+				changes, err := s.syncRecord(ctx, provider, identifier)
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				if err != nil {
+					log.Printf("⚠️ No se pudo sincronizar %s con %s: %v", identifier.RecordID, provider.Label(), err)
+					result.Errors++
+					continue
+				}
+				result.Synced++
+				result.Changes += changes
+			}
+		}
+	}
+
+	if result.Synced > 0 || result.Errors > 0 {
+		log.Printf("🔄 Metadatos sincronizados: %d records, %d cambios propuestos, %d errores", result.Synced, result.Changes, result.Errors)
+	}
+	return result, nil
+}
+
+// syncRecord consulta el release de un record y guarda los cambios
+// propuestos y los valores de mercado. Retorna la cantidad de cambios.
+func (s *Syncer) syncRecord(ctx context.Context, provider metadata.Provider, identifier *models.Identifier) (int, error) {
+	state := &models.MetadataSync{
+		RecordID:   identifier.RecordID,
+		Provider:   provider.Name(),
+		ExternalID: identifier.Valor,
+		SyncedAt:   time.Now(),
+	}
+
+	release, err := provider.Release(ctx, identifier.Valor)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, err
+		}
+		if errors.Is(err, metadata.ErrNotFound) {
+			err = fmt.Errorf("el release %s ya no existe en %s", identifier.Valor, provider.Label())
+		}
+		return 0, s.saveError(state, err)
+	}
+
+	record, err := s.records.GetByID(identifier.RecordID)
+	if err != nil {
+		return 0, err
+	}
+
+	rejected, err := s.syncs.GetByRecord(record.ID, models.ChangeRejected)
+	if err != nil {
+		return 0, err
+	}
+
+	var changes []*models.MetadataChange
+	values := releaseValues(release)
+	for _, campo := range models.MetadataFields {
+		nuevo := values[campo]
+		actual := record.MetadataValue(campo)
+		// El proveedor nunca borra datos del record
+		if nuevo == "" || models.SameMetadataValue(campo, actual, nuevo) {
+			continue
+		}
+		if wasRejected(rejected, campo, nuevo) {
+			continue
+		}
+		// El arte subido o copiado localmente no se vuelve a reemplazar
+		// por un enlace externo
+		if campo == "arte_url" && artwork.IsLocal(actual) {
+			continue
+		}
+		changes = append(changes, models.NewMetadataChange(record.ID, provider.Name(), campo, actual, nuevo))
+	}
+
+	if market, ok := provider.(metadata.MarketProvider); ok {
+		stats, err := market.MarketStats(ctx, identifier.Valor)
+		if err != nil {
+			log.Printf("⚠️ Sin valores de mercado para %s: %v", record.ID, err)
+		} else {
+			setMarketStats(state, stats)
+		}
+	}
+
+	if err := s.syncs.Save(state, changes); err != nil {
+		return 0, err
+	}
+	return len(changes), nil
+}
+
+// saveError registra el error de sincronización de un record conservando
+// los valores de mercado conocidos, y lo retorna
+func (s *Syncer) saveError(state *models.MetadataSync, cause error) error {
+	previous, err := s.syncs.GetSync(state.RecordID)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		state.PrecioMinimo = previous.PrecioMinimo
+		state.Moneda = previous.Moneda
+		state.EnVenta = previous.EnVenta
+	}
+	state.Error = sql.NullString{String: cause.Error(), Valid: true}
+
+	if err := s.syncs.Save(state, nil); err != nil {
+		return err
+	}
+	return cause
+}
+
+// Accept aplica cambios pendientes a sus records y los marca como
+// aceptados. Los cambios quedan en el historial a nombre de actor.
+func (s *Syncer) Accept(changes []*models.MetadataChange, actor string) error {
+	byRecord := map[string][]*models.MetadataChange{}
+	var order []string
+	for _, change := range changes {
+		if change.Estado != models.ChangePending {
+			continue
+		}
+		if _, ok := byRecord[change.RecordID]; !ok {
+			order = append(order, change.RecordID)
+		}
+		byRecord[change.RecordID] = append(byRecord[change.RecordID], change)
+	}
+
+	records := s.records.WithActor(actor)
+	for _, recordID := range order {
+		record, err := records.GetByID(recordID)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, 0, len(byRecord[recordID]))
+		for _, change := range byRecord[recordID] {
+			if err := record.SetMetadataValue(change.Campo, change.ValorNuevo); err != nil {
+				return err
+			}
+			ids = append(ids, change.ID)
+		}
+
+		record.UpdatedAt = time.Now()
+		if err := records.Update(record); err != nil {
+			return err
+		}
+		if err := s.syncs.Resolve(ids, models.ChangeAccepted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseValues retorna los campos del release con el mismo formato que
+// models.Record.MetadataValue
+func releaseValues(release *metadata.Release) map[string]string {
+	values := map[string]string{
+		"titulo":         release.Titulo,
+		"artista":        release.Artista,
+		"sello":          release.Sello,
+		"catalog_number": release.CatalogNumber,
+		"formato":        release.Formato,
+		"pais":           release.Pais,
+		"generos":        jsonList(release.Generos),
+		"estilos":        jsonList(release.Estilos),
+		"tracklist":      jsonList(release.Tracklist),
+		"duracion_total": release.DuracionTotal,
+		"arte_url":       release.ArteURL,
+	}
+	if release.Anio > 0 {
+		values["anio"] = strconv.Itoa(release.Anio)
+	}
+	return values
+}
+
+// jsonList serializa una lista no vacía como JSON, o retorna vacío
+func jsonList[T any](list []T) string {
+	if len(list) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// setMarketStats copia los valores de mercado al estado de sincronización
+func setMarketStats(state *models.MetadataSync, stats *metadata.MarketStats) {
+	state.EnVenta = sql.NullInt32{Int32: int32(stats.EnVenta), Valid: true}
+	if stats.PrecioMinimo > 0 {
+		state.PrecioMinimo = sql.NullFloat64{Float64: stats.PrecioMinimo, Valid: true}
+		state.Moneda = sql.NullString{String: stats.Moneda, Valid: stats.Moneda != ""}
+	}
+}
+
+// wasRejected indica si el mismo valor ya se propuso y se rechazó, para
+// no volver a proponerlo en cada sincronización
+func wasRejected(rejected []*models.MetadataChange, campo, valor string) bool {
+	for _, change := range rejected {
+		if change.Campo == campo && models.SameMetadataValue(campo, change.ValorNuevo, valor) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdentifierType indica si el nombre corresponde a un tipo de identificador
+func isIdentifierType(name string) bool {
+	for _, tipo := range models.IdentifierTypes {
+		if tipo == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Package storage guarda archivos binarios (arte de los records y sus
+// miniaturas) por clave. El almacenamiento es intercambiable; por defecto
+// se usa un directorio del sistema de archivos local.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrNotFound indica que no existe un archivo con la clave pedida
+var ErrNotFound = errors.New("archivo no encontrado")
+
+// keyPattern restringe las claves a rutas relativas simples, p. ej.
+// "artwork/3f9a….jpg", para que nunca apunten fuera del almacenamiento
+var keyPattern = regexp.MustCompile(`^[a-z0-9_-]+(/[a-z0-9_-]+)*(\.[a-z0-9]+)?$`)
+
+// Store es un almacenamiento de archivos por clave
+type Store interface {
+	// Put guarda el contenido con la clave indicada, reemplazando el anterior
+	Put(key string, data []byte) error
+	// Open abre el archivo para leerlo; retorna ErrNotFound si no existe
+	Open(key string) (io.ReadSeekCloser, error)
+	// Exists indica si hay un archivo con la clave indicada
+	Exists(key string) (bool, error)
+	// Delete elimina el archivo; no falla si no existe
+	Delete(key string) error
+}
+
+// ValidKey indica si la clave es una ruta relativa válida
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key) && !strings.Contains(key, "..")
+}
+
+// LocalStore guarda los archivos en un directorio del sistema de archivos
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore crea un almacenamiento en el directorio indicado,
+// creándolo si no existe
+func NewLocalStore(root string) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("error creando directorio de archivos: %w", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+// path retorna la ruta en disco de una clave
+func (s *LocalStore) path(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", fmt.Errorf("clave de archivo inválida: %s", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put guarda el contenido escribiendo primero un archivo temporal, para
+// que nunca se sirva un archivo a medio escribir
+func (s *LocalStore) Put(key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creando directorio de archivos: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creando archivo: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error escribiendo archivo: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error escribiendo archivo: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("error escribiendo archivo: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error guardando archivo: %w", err)
+	}
+	return nil
+}
+
+// Open abre el archivo para leerlo
+func (s *LocalStore) Open(key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error abriendo archivo: %w", err)
+	}
+	return file, nil
+}
+
+// Exists indica si hay un archivo con la clave indicada
+func (s *LocalStore) Exists(key string) (bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error consultando archivo: %w", err)
+	}
+	return info.Mode().IsRegular(), nil
+}
+
+// Delete elimina el archivo
+func (s *LocalStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error eliminando archivo: %w", err)
+	}
+	return nil
+}
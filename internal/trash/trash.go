@@ -0,0 +1,71 @@
+// Package trash purga los records que llevan en la papelera más tiempo que
+// la retención configurada. Mientras están en la papelera los records se
+// pueden restaurar; al purgarlos se eliminan junto con todo lo que depende
+// de ellos.
+package trash
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/rodrwan/vinilo/internal/repository"
+)
+
+// checkInterval es cada cuánto se buscan records vencidos en la papelera
+const checkInterval = time.Hour
+
+// Purger elimina definitivamente los records vencidos de la papelera
+type Purger struct {
+	records   *repository.RecordRepository
+	retention time.Duration
+}
+
+// New crea un purgador que conserva los records en la papelera durante la
+// retención indicada. Con retención 0 los records solo se purgan a mano.
+func New(records *repository.RecordRepository, retention time.Duration) *Purger {
+	return &Purger{records: records, retention: retention}
+}
+
+// Retention retorna cuánto tiempo se conserva un record en la papelera
+func (p *Purger) Retention() time.Duration {
+	return p.retention
+}
+
+// PurgeAt retorna cuándo se purgará automáticamente un record enviado a la
+// papelera en la fecha indicada, o false si la purga automática está
+// desactivada
+func (p *Purger) PurgeAt(deletedAt time.Time) (time.Time, bool) {
+	if p.retention <= 0 {
+		return time.Time{}, false
+	}
+	return deletedAt.Add(p.retention), true
+}
+
+// Start purga la papelera al iniciar y luego una vez por hora, hasta que
+// se cancele el contexto
+func (p *Purger) Start(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Run(); err != nil {
+			log.Printf("❌ Error purgando papelera: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run purga los records que llevan en la papelera más que la retención y
+// retorna cuántos eliminó
+func (p *Purger) Run() (int, error) {
+	if p.retention <= 0 {
+		return 0, nil
+	}
+	return p.records.PurgeDeletedBefore(time.Now().Add(-p.retention))
+}
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS locations (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL,
+    tipo TEXT NOT NULL, -- room, shelf, box, slot
+    parent_id TEXT REFERENCES locations(id) ON DELETE SET NULL,
+    notas TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_locations_parent_id ON locations(parent_id);
+
+-- Ubicación física de cada record
+ALTER TABLE records ADD COLUMN location_id TEXT REFERENCES locations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_records_location_id ON records(location_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_records_location_id;
+ALTER TABLE records DROP COLUMN location_id;
+DROP TABLE IF EXISTS locations;
+-- +goose StatementEnd
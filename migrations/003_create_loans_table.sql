@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS loans (
+    id TEXT PRIMARY KEY,
+    record_id TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    prestatario TEXT NOT NULL,
+    fecha_salida DATETIME NOT NULL,
+    fecha_vencimiento DATETIME,
+    fecha_devolucion DATETIME, -- NULL mientras el disco sigue prestado
+    notas TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_record_id ON loans(record_id);
+CREATE INDEX IF NOT EXISTS idx_loans_fecha_devolucion ON loans(fecha_devolucion);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS loans;
+-- +goose StatementEnd
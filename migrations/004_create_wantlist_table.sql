@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- La wantlist reutiliza las columnas de metadatos de records para que una
+-- entrada se pueda convertir directamente en un record de la colección
+CREATE TABLE IF NOT EXISTS wantlist (
+    id TEXT PRIMARY KEY,
+    titulo TEXT NOT NULL,
+    artista TEXT NOT NULL,
+    sello TEXT,
+    catalog_number TEXT,
+    anio INTEGER,
+    formato TEXT,
+    generos TEXT, -- JSON array como string
+    estilos TEXT, -- JSON array como string
+    pais TEXT,
+    arte_url TEXT,
+    notas TEXT,
+    precio_maximo REAL,
+    prensaje_preferido TEXT,
+    prioridad INTEGER NOT NULL DEFAULT 2, -- 1 alta, 2 media, 3 baja
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_wantlist_prioridad ON wantlist(prioridad);
+CREATE INDEX IF NOT EXISTS idx_wantlist_artista ON wantlist(artista);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS wantlist;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS plays (
+    id TEXT PRIMARY KEY,
+    record_id TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    escuchado_en DATETIME NOT NULL, -- guardado en UTC
+    lados TEXT, -- lados escuchados, por ejemplo "A, B"
+    notas TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_plays_record_id ON plays(record_id);
+CREATE INDEX IF NOT EXISTS idx_plays_escuchado_en ON plays(escuchado_en);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS plays;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Calificación personal de 0.5 a 5 estrellas (en pasos de media estrella)
+ALTER TABLE records ADD COLUMN rating REAL;
+ALTER TABLE records ADD COLUMN review TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_records_rating ON records(rating);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_records_rating;
+ALTER TABLE records DROP COLUMN review;
+ALTER TABLE records DROP COLUMN rating;
+-- +goose StatementEnd
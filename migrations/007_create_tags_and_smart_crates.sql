@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tags (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL UNIQUE, -- normalizado en minúsculas
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS record_tags (
+    record_id TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    tag_id TEXT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (record_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_record_tags_tag_id ON record_tags(tag_id);
+
+-- Crates inteligentes: su contenido se calcula a partir de un filtro guardado
+CREATE TABLE IF NOT EXISTS smart_crates (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL,
+    descripcion TEXT,
+    filtro TEXT NOT NULL, -- por ejemplo: formato=LP and anio<1980 and tag=jazz
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS smart_crates;
+DROP TABLE IF EXISTS record_tags;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
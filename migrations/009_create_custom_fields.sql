@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Campos personalizados definidos por el administrador
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id TEXT PRIMARY KEY,
+    clave TEXT NOT NULL UNIQUE, -- usada en filtros y exportaciones, p. ej. planta_de_prensaje
+    nombre TEXT NOT NULL,
+    tipo TEXT NOT NULL, -- text, number, bool, date o enum
+    opciones TEXT, -- JSON array con las opciones de un enum
+    posicion INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Valores de los campos personalizados de cada record, como objeto JSON por clave
+ALTER TABLE records ADD COLUMN campos TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE records DROP COLUMN campos;
+DROP TABLE IF EXISTS custom_fields;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Identificadores de cada record: códigos de barras EAN/UPC, inscripciones
+-- de matriz/runout e IDs en Discogs y MusicBrainz
+CREATE TABLE IF NOT EXISTS identifiers (
+    id TEXT PRIMARY KEY,
+    record_id TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    tipo TEXT NOT NULL, -- barcode, matrix, discogs o musicbrainz
+    valor TEXT NOT NULL, -- normalizado: solo dígitos para códigos de barras
+    descripcion TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_identifiers_record_id ON identifiers(record_id);
+CREATE INDEX IF NOT EXISTS idx_identifiers_tipo_valor ON identifiers(tipo, valor);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS identifiers;
+-- +goose StatementEnd
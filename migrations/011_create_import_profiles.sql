@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Perfiles de mapeo de columnas reutilizables para importar CSV/JSON
+CREATE TABLE IF NOT EXISTS import_profiles (
+    id TEXT PRIMARY KEY,
+    nombre TEXT NOT NULL UNIQUE,
+    mapeo TEXT NOT NULL, -- JSON object columna → campo del record
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS import_profiles;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Estado de la re-sincronización periódica de metadatos de cada record
+-- con su proveedor externo, incluidos los valores de mercado
+CREATE TABLE IF NOT EXISTS metadata_syncs (
+    record_id TEXT PRIMARY KEY REFERENCES records(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL, -- discogs o musicbrainz
+    external_id TEXT NOT NULL,
+    synced_at DATETIME NOT NULL,
+    error TEXT, -- error de la última sincronización, si falló
+    precio_minimo REAL, -- precio más bajo a la venta en el marketplace
+    moneda TEXT,
+    en_venta INTEGER -- copias a la venta en el marketplace
+);
+
+-- Cambios de metadatos propuestos por el proveedor, que se aceptan o
+-- rechazan desde el admin en vez de sobrescribir los datos del record
+CREATE TABLE IF NOT EXISTS metadata_changes (
+    id TEXT PRIMARY KEY,
+    record_id TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    campo TEXT NOT NULL, -- columna de records, p. ej. anio o tracklist
+    valor_actual TEXT,
+    valor_nuevo TEXT NOT NULL,
+    estado TEXT NOT NULL DEFAULT 'pendiente', -- pendiente, aceptado o rechazado
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    resolved_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_metadata_changes_record_id ON metadata_changes(record_id);
+CREATE INDEX IF NOT EXISTS idx_metadata_changes_estado ON metadata_changes(estado);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS metadata_changes;
+DROP TABLE IF EXISTS metadata_syncs;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Copias locales del arte enlazado desde sitios externos. Se guarda una
+-- fila por record con la URL original, tanto si se copió como si el enlace
+-- estaba roto, para no volver a descargar la misma URL
+CREATE TABLE IF NOT EXISTS artwork_mirrors (
+    record_id TEXT PRIMARY KEY REFERENCES records(id) ON DELETE CASCADE,
+    url_original TEXT NOT NULL,
+    estado TEXT NOT NULL, -- copiado o roto
+    error TEXT, -- motivo por el que no se pudo copiar
+    intentado_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_artwork_mirrors_estado ON artwork_mirrors(estado);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS artwork_mirrors;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Galería de imágenes de cada record (portada, contraportada, etiquetas,
+-- insertos, fotos del runout), en el orden en que se muestran
+CREATE TABLE IF NOT EXISTS record_images (
+    id TEXT PRIMARY KEY,
+    record_id TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    tipo TEXT NOT NULL, -- front, back, label_a, label_b, insert o runout
+    leyenda TEXT,
+    posicion INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_record_images_record_id ON record_images(record_id, posicion);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS record_images;
+-- +goose StatementEnd
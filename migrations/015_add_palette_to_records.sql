@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Colores dominante y de acento del arte de cada record ("#rrggbb"), que
+-- tiñen el fondo del detalle y las tarjetas del catálogo
+ALTER TABLE records ADD COLUMN color_dominante TEXT;
+ALTER TABLE records ADD COLUMN color_acento TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE records DROP COLUMN color_acento;
+ALTER TABLE records DROP COLUMN color_dominante;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Pares de records que el buscador de duplicados propuso y se marcaron como
+-- distintos (p. ej. dos copias de la misma edición), para no volver a
+-- proponerlos. record_a es siempre el menor de los dos IDs.
+CREATE TABLE IF NOT EXISTS duplicate_dismissals (
+    record_a TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    record_b TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (record_a, record_b)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS duplicate_dismissals;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Papelera: los records eliminados quedan con la fecha de eliminación y se
+-- excluyen de todas las consultas hasta que se restauran o se purgan
+ALTER TABLE records ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX IF NOT EXISTS idx_records_deleted_at ON records(deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_records_deleted_at;
+ALTER TABLE records DROP COLUMN deleted_at;
+-- +goose StatementEnd
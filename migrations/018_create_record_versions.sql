@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Historial de cambios de los records: cada creación, edición, eliminación,
+-- restauración o reversión guarda quién la hizo, los campos que cambiaron
+-- (JSON con el valor anterior y el nuevo) y el record completo tal como
+-- quedó (JSON), para poder volver a esa versión.
+CREATE TABLE IF NOT EXISTS record_versions (
+    id TEXT PRIMARY KEY,
+    record_id TEXT NOT NULL REFERENCES records(id) ON DELETE CASCADE,
+    numero INTEGER NOT NULL,
+    accion TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    cambios TEXT,
+    snapshot TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (record_id, numero)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS record_versions;
+-- +goose StatementEnd
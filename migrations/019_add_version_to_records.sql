@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Control de concurrencia optimista: cada escritura de un record incrementa
+-- su versión, y las ediciones solo se guardan si el record sigue en la
+-- versión con la que se cargó el formulario
+ALTER TABLE records ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE records DROP COLUMN version;
+-- +goose StatementEnd
@@ -56,7 +56,7 @@ func Layout(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\"><meta property=\"og:description\" content=\"Colección personal de vinilos - Descubre música clásica y contemporánea\"><meta property=\"og:type\" content=\"website\"><meta property=\"og:url\" content=\"https://vinilo.local\"><!-- Favicon --><link rel=\"icon\" type=\"image/svg+xml\" href=\"/static/images/favicon.svg\"><!-- Google Fonts --><link rel=\"preconnect\" href=\"https://fonts.googleapis.com\"><link rel=\"preconnect\" href=\"https://fonts.gstatic.com\" crossorigin=\"\"><link href=\"https://fonts.googleapis.com/css2?family=Fira+Code:wght@300..700&family=Plus+Jakarta+Sans:ital,wght@0,200..800;1,200..800\" rel=\"stylesheet\"><!-- Tailwind CSS CDN --><script src=\"https://cdn.tailwindcss.com\"></script><!-- Tailwind Config --><script>\n\t\t\t\ttailwind.config = {\n\t\t\t\t\ttheme: {\n\t\t\t\t\t\textend: {\n\t\t\t\t\t\t\tcolors: {\n\t\t\t\t\t\t\t\t'primary-red': 'rgba(230, 57, 70, 0.6)',\n\t\t\t\t\t\t\t\t'primary-orange': 'rgba(255, 107, 53, 0.6)',\n\t\t\t\t\t\t\t\t'primary-yellow': 'rgba(255, 215, 0, 0.6)',\n\t\t\t\t\t\t\t\t'accent-orange': 'rgba(255, 140, 66, 0.6)',\n\t\t\t\t\t\t\t\t'red': {\n\t\t\t\t\t\t\t\t\t500: 'rgba(230, 57, 70, 0.6)',\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\t'orange': {\n\t\t\t\t\t\t\t\t\t500: 'rgba(255, 107, 53, 0.6)',\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\t'yellow': {\n\t\t\t\t\t\t\t\t\t500: 'rgba(255, 215, 0, 0.6)',\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\t'dark-gray': '#2d3748',\n\t\t\t\t\t\t\t\t'light-bg': '#fafafa',\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t},\n\t\t\t\t\t},\n\t\t\t\t};\n\t\t\t</script><style>\n\t\t\t\t:root {\n\t\t\t\t\t--primary-orange: rgba(255, 107, 53, 0.6);\n\t\t\t\t\t--primary-red: rgba(230, 57, 70, 0.6);\n\t\t\t\t\t--accent-orange: rgba(255, 140, 66, 0.6);\n\t\t\t\t\t--dark-gray: #2d3748;\n\t\t\t\t\t--light-bg: #fafafa;\n\t\t\t\t\t--primary-yellow: rgba(255, 215, 0, 0.6);\n\t\t\t\t}\n\n\t\t\t\t/* Fira Code as main font */\n\t\t\t\tbody {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t}\n\n\t\t\t\t.font-display {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t}\n\n\t\t\t\t.font-handwritten {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t\tfont-style: italic;\n\t\t\t\t\tfont-weight: 400;\n\t\t\t\t}\n\n\t\t\t\t.gradient-text {\n\t\t\t\t\tbackground: linear-gradient(135deg, var(--primary-red), var(--primary-orange));\n\t\t\t\t\t-webkit-background-clip: text;\n\t\t\t\t\t-webkit-text-fill-color: transparent;\n\t\t\t\t\tbackground-clip: text;\n\t\t\t\t}\n\n\t\t\t\t.outlined-text {\n\t\t\t\t\t-webkit-text-stroke: 2px var(--dark-gray);\n\t\t\t\t\tcolor: transparent;\n\t\t\t\t}\n\n\t\t\t\t.vinyl-card {\n\t\t\t\t\tbackground: linear-gradient(135deg, #fff, #f8f9fa);\n\t\t\t\t\tborder-radius: 50%;\n\t\t\t\t\tbox-shadow: 0 8px 32px rgba(0,0,0,0.1);\n\t\t\t\t\ttransition: all 0.3s ease;\n\t\t\t\t}\n\n\t\t\t\t.vinyl-card:hover {\n\t\t\t\t\ttransform: translateY(-5px);\n\t\t\t\t\tbox-shadow: 0 12px 40px rgba(0,0,0,0.15);\n\t\t\t\t}\n\n\t\t\t\t.btn-primary {\n\t\t\t\t\tbackground: linear-gradient(135deg, var(--primary-red), var(--primary-orange));\n\t\t\t\t\tcolor: white;\n\t\t\t\t\tborder-radius: 50px;\n\t\t\t\t\tpadding: 12px 32px;\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t\ttransition: all 0.3s ease;\n\t\t\t\t\tbox-shadow: 0 4px 15px rgba(230, 57, 70, 0.3);\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t}\n\n\t\t\t\t.btn-primary:hover {\n\t\t\t\t\ttransform: translateY(-2px);\n\t\t\t\t\tbox-shadow: 0 6px 20px rgba(230, 57, 70, 0.4);\n\t\t\t\t}\n\n\t\t\t\t/* Glassmorphism effects */\n\t\t\t\t.glass-header {\n\t\t\t\t\tbackdrop-filter: blur(16px);\n\t\t\t\t\t-webkit-backdrop-filter: blur(16px);\n\t\t\t\t\tbackground: var(--primary-red);\n\t\t\t\t\tborder-bottom: 1px solid rgba(255, 255, 255, 0.1);\n\t\t\t\t\tbox-shadow: 0 4px 20px rgba(0, 0, 0, 0.3);\n\t\t\t\t}\n\n\t\t\t\t.glass-footer {\n\t\t\t\t\tbackdrop-filter: blur(16px);\n\t\t\t\t\t-webkit-backdrop-filter: blur(16px);\n\t\t\t\t\tbackground: rgba(0, 0, 0, 0.6);\n\t\t\t\t\tborder-top: 1px solid rgba(255, 255, 255, 0.1);\n\t\t\t\t}\n\n\t\t\t\t/* Animation keyframes */\n\t\t\t\t@keyframes float {\n\t\t\t\t\t0%, 100% { transform: translateY(0px); }\n\t\t\t\t\t50% { transform: translateY(-10px); }\n\t\t\t\t}\n\n\t\t\t\t.animate-float {\n\t\t\t\t\tanimation: float 3s ease-in-out infinite;\n\t\t\t\t}\n\n\t\t\t\t/* Typography improvements for Fira Code */\n\t\t\t\th1, h2, h3, h4, h5, h6 {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t}\n\n\t\t\t\tp, span, div, a, button {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t}\n\n\t\t\t\t/* Better letter spacing for monospace font */\n\t\t\t\t.font-display {\n\t\t\t\t\tletter-spacing: -0.02em;\n\t\t\t\t}\n\n\t\t\t\t.font-handwritten {\n\t\t\t\t\tletter-spacing: 0.01em;\n\t\t\t\t}\n\t\t\t</style></head><body class=\"h-full font-mono\"><!-- Header with Glassmorphism --><header class=\"glass-header sticky top-0 z-50\"><div class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8\"><div class=\"flex justify-between items-center h-16\"><!-- Logo --><div class=\"flex items-center\"><a href=\"/\" class=\"flex items-center space-x-3 group\"><div class=\"w-10 h-10 bg-gradient-to-br from-red-500 to-orange-500 rounded-full flex items-center justify-center group-hover:scale-110 transition-transform\"><span class=\"text-white font-bold text-lg\">VA</span></div><span class=\"text-xl font-display font-semibold text-white\">Vinilo</span></a></div><!-- Navigation --><nav class=\"hidden md:flex space-x-8\"><a href=\"/\" class=\"text-white/90 hover:text-white px-3 py-2 text-sm font-medium transition-colors rounded-lg hover:bg-white/10\">Inicio</a> <a href=\"/records\" class=\"text-white/90 hover:text-white px-3 py-2 text-sm font-medium transition-colors rounded-lg hover:bg-white/10\">Catálogo</a></nav><!-- Right side icons --><div class=\"flex items-center space-x-4\"><!-- Notification bell --><button class=\"relative p-2 text-white/90 hover:text-white transition-colors rounded-lg hover:bg-white/10\"><svg class=\"w-6 h-6\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15 17h5l-5 5v-5zM9 7h6m0 10v-3m-3 3h.01M9 17h.01M9 14h.01M12 14h.01M15 11h.01M12 11h.01M9 11h.01M7 21h10a2 2 0 002-2V5a2 2 0 00-2-2H7a2 2 0 00-2 2v14a2 2 0 002 2z\"></path></svg> <span class=\"absolute -top-1 -right-1 bg-red-500 text-white text-xs rounded-full w-5 h-5 flex items-center justify-center\">3</span></button><!-- Search icon --><button class=\"p-2 text-white/90 hover:text-white transition-colors rounded-lg hover:bg-white/10\"><svg class=\"w-6 h-6\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z\"></path></svg></button><!-- User profile --><div class=\"w-8 h-8 bg-gradient-to-br from-gray-400 to-gray-600 rounded-full flex items-center justify-center border border-white/20\"><span class=\"text-white text-sm font-medium\">U</span></div></div></div></div></header><!-- Main Content --><main class=\"flex-1\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "\"><meta property=\"og:description\" content=\"Colección personal de vinilos - Descubre música clásica y contemporánea\"><meta property=\"og:type\" content=\"website\"><meta property=\"og:url\" content=\"https://vinilo.local\"><!-- Favicon --><link rel=\"icon\" type=\"image/svg+xml\" href=\"/static/images/favicon.svg\"><!-- Google Fonts --><link rel=\"preconnect\" href=\"https://fonts.googleapis.com\"><link rel=\"preconnect\" href=\"https://fonts.gstatic.com\" crossorigin=\"\"><link href=\"https://fonts.googleapis.com/css2?family=Fira+Code:wght@300..700&family=Plus+Jakarta+Sans:ital,wght@0,200..800;1,200..800\" rel=\"stylesheet\"><!-- Tailwind CSS CDN --><script src=\"https://cdn.tailwindcss.com\"></script><!-- Tailwind Config --><script>\n\t\t\t\ttailwind.config = {\n\t\t\t\t\ttheme: {\n\t\t\t\t\t\textend: {\n\t\t\t\t\t\t\tcolors: {\n\t\t\t\t\t\t\t\t'primary-red': 'rgba(230, 57, 70, 0.6)',\n\t\t\t\t\t\t\t\t'primary-orange': 'rgba(255, 107, 53, 0.6)',\n\t\t\t\t\t\t\t\t'primary-yellow': 'rgba(255, 215, 0, 0.6)',\n\t\t\t\t\t\t\t\t'accent-orange': 'rgba(255, 140, 66, 0.6)',\n\t\t\t\t\t\t\t\t'red': {\n\t\t\t\t\t\t\t\t\t500: 'rgba(230, 57, 70, 0.6)',\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\t'orange': {\n\t\t\t\t\t\t\t\t\t500: 'rgba(255, 107, 53, 0.6)',\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\t'yellow': {\n\t\t\t\t\t\t\t\t\t500: 'rgba(255, 215, 0, 0.6)',\n\t\t\t\t\t\t\t\t},\n\t\t\t\t\t\t\t\t'dark-gray': '#2d3748',\n\t\t\t\t\t\t\t\t'light-bg': '#fafafa',\n\t\t\t\t\t\t\t},\n\t\t\t\t\t\t},\n\t\t\t\t\t},\n\t\t\t\t};\n\t\t\t</script><style>\n\t\t\t\t:root {\n\t\t\t\t\t--primary-orange: rgba(255, 107, 53, 0.6);\n\t\t\t\t\t--primary-red: rgba(230, 57, 70, 0.6);\n\t\t\t\t\t--accent-orange: rgba(255, 140, 66, 0.6);\n\t\t\t\t\t--dark-gray: #2d3748;\n\t\t\t\t\t--light-bg: #fafafa;\n\t\t\t\t\t--primary-yellow: rgba(255, 215, 0, 0.6);\n\t\t\t\t}\n\n\t\t\t\t/* Fira Code as main font */\n\t\t\t\tbody {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t}\n\n\t\t\t\t.font-display {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t}\n\n\t\t\t\t.font-handwritten {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t\tfont-style: italic;\n\t\t\t\t\tfont-weight: 400;\n\t\t\t\t}\n\n\t\t\t\t.gradient-text {\n\t\t\t\t\tbackground: linear-gradient(135deg, var(--primary-red), var(--primary-orange));\n\t\t\t\t\t-webkit-background-clip: text;\n\t\t\t\t\t-webkit-text-fill-color: transparent;\n\t\t\t\t\tbackground-clip: text;\n\t\t\t\t}\n\n\t\t\t\t.outlined-text {\n\t\t\t\t\t-webkit-text-stroke: 2px var(--dark-gray);\n\t\t\t\t\tcolor: transparent;\n\t\t\t\t}\n\n\t\t\t\t.vinyl-card {\n\t\t\t\t\tbackground: linear-gradient(135deg, #fff, #f8f9fa);\n\t\t\t\t\tborder-radius: 50%;\n\t\t\t\t\tbox-shadow: 0 8px 32px rgba(0,0,0,0.1);\n\t\t\t\t\ttransition: all 0.3s ease;\n\t\t\t\t}\n\n\t\t\t\t.vinyl-card:hover {\n\t\t\t\t\ttransform: translateY(-5px);\n\t\t\t\t\tbox-shadow: 0 12px 40px rgba(0,0,0,0.15);\n\t\t\t\t}\n\n\t\t\t\t.btn-primary {\n\t\t\t\t\tbackground: linear-gradient(135deg, var(--primary-red), var(--primary-orange));\n\t\t\t\t\tcolor: white;\n\t\t\t\t\tborder-radius: 50px;\n\t\t\t\t\tpadding: 12px 32px;\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t\ttransition: all 0.3s ease;\n\t\t\t\t\tbox-shadow: 0 4px 15px rgba(230, 57, 70, 0.3);\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t}\n\n\t\t\t\t.btn-primary:hover {\n\t\t\t\t\ttransform: translateY(-2px);\n\t\t\t\t\tbox-shadow: 0 6px 20px rgba(230, 57, 70, 0.4);\n\t\t\t\t}\n\n\t\t\t\t/* Glassmorphism effects */\n\t\t\t\t.glass-header {\n\t\t\t\t\tbackdrop-filter: blur(16px);\n\t\t\t\t\t-webkit-backdrop-filter: blur(16px);\n\t\t\t\t\tbackground: var(--primary-red);\n\t\t\t\t\tborder-bottom: 1px solid rgba(255, 255, 255, 0.1);\n\t\t\t\t\tbox-shadow: 0 4px 20px rgba(0, 0, 0, 0.3);\n\t\t\t\t}\n\n\t\t\t\t.glass-footer {\n\t\t\t\t\tbackdrop-filter: blur(16px);\n\t\t\t\t\t-webkit-backdrop-filter: blur(16px);\n\t\t\t\t\tbackground: rgba(0, 0, 0, 0.6);\n\t\t\t\t\tborder-top: 1px solid rgba(255, 255, 255, 0.1);\n\t\t\t\t}\n\n\t\t\t\t/* Animation keyframes */\n\t\t\t\t@keyframes float {\n\t\t\t\t\t0%, 100% { transform: translateY(0px); }\n\t\t\t\t\t50% { transform: translateY(-10px); }\n\t\t\t\t}\n\n\t\t\t\t.animate-float {\n\t\t\t\t\tanimation: float 3s ease-in-out infinite;\n\t\t\t\t}\n\n\t\t\t\t/* Typography improvements for Fira Code */\n\t\t\t\th1, h2, h3, h4, h5, h6 {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t\tfont-weight: 600;\n\t\t\t\t}\n\n\t\t\t\tp, span, div, a, button {\n\t\t\t\t\tfont-family: 'Fira Code', monospace;\n\t\t\t\t}\n\n\t\t\t\t/* Better letter spacing for monospace font */\n\t\t\t\t.font-display {\n\t\t\t\t\tletter-spacing: -0.02em;\n\t\t\t\t}\n\n\t\t\t\t.font-handwritten {\n\t\t\t\t\tletter-spacing: 0.01em;\n\t\t\t\t}\n\t\t\t</style></head><body class=\"h-full font-mono\"><!-- Header with Glassmorphism --><header class=\"glass-header sticky top-0 z-50\"><div class=\"max-w-7xl mx-auto px-4 sm:px-6 lg:px-8\"><div class=\"flex justify-between items-center h-16\"><!-- Logo --><div class=\"flex items-center\"><a href=\"/\" class=\"flex items-center space-x-3 group\"><div class=\"w-10 h-10 bg-gradient-to-br from-red-500 to-orange-500 rounded-full flex items-center justify-center group-hover:scale-110 transition-transform\"><span class=\"text-white font-bold text-lg\">VA</span></div><span class=\"text-xl font-display font-semibold text-white\">Vinilo</span></a></div><!-- Navigation --><nav class=\"hidden md:flex space-x-8\"><a href=\"/\" class=\"text-white/90 hover:text-white px-3 py-2 text-sm font-medium transition-colors rounded-lg hover:bg-white/10\">Inicio</a> <a href=\"/records\" class=\"text-white/90 hover:text-white px-3 py-2 text-sm font-medium transition-colors rounded-lg hover:bg-white/10\">Catálogo</a> <a href=\"/wantlist\" class=\"text-white/90 hover:text-white px-3 py-2 text-sm font-medium transition-colors rounded-lg hover:bg-white/10\">Wantlist</a> <a href=\"/crates\" class=\"text-white/90 hover:text-white px-3 py-2 text-sm font-medium transition-colors rounded-lg hover:bg-white/10\">Crates</a></nav><!-- Right side icons --><div class=\"flex items-center space-x-4\"><!-- Notification bell --><button class=\"relative p-2 text-white/90 hover:text-white transition-colors rounded-lg hover:bg-white/10\"><svg class=\"w-6 h-6\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M15 17h5l-5 5v-5zM9 7h6m0 10v-3m-3 3h.01M9 17h.01M9 14h.01M12 14h.01M15 11h.01M12 11h.01M9 11h.01M7 21h10a2 2 0 002-2V5a2 2 0 00-2-2H7a2 2 0 00-2 2v14a2 2 0 002 2z\"></path></svg> <span class=\"absolute -top-1 -right-1 bg-red-500 text-white text-xs rounded-full w-5 h-5 flex items-center justify-center\">3</span></button><!-- Search icon --><button class=\"p-2 text-white/90 hover:text-white transition-colors rounded-lg hover:bg-white/10\"><svg class=\"w-6 h-6\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z\"></path></svg></button><!-- User profile --><div class=\"w-8 h-8 bg-gradient-to-br from-gray-400 to-gray-600 rounded-full flex items-center justify-center border border-white/20\"><span class=\"text-white text-sm font-medium\">U</span></div></div></div></div></header><!-- Main Content --><main class=\"flex-1\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}